@@ -0,0 +1,149 @@
+package gocql
+
+import (
+	"context"
+	"sync"
+)
+
+// Future is the pending result of a query started with Query.ExecAsync or
+// Query.IterAsync. Its methods are safe for concurrent use.
+type Future struct {
+	iter *Iter
+	err  error
+	done chan struct{}
+	// cancel, if not nil, cancels the context of the query once its iterator
+	// is closed.
+	cancel context.CancelFunc
+}
+
+func newFuture(run func() (*Iter, error)) *Future {
+	f := &Future{done: make(chan struct{})}
+	go func() {
+		defer close(f.done)
+		f.iter, f.err = run()
+	}()
+	return f
+}
+
+// Done returns a channel that is closed once the query has completed.
+func (f *Future) Done() <-chan struct{} {
+	return f.done
+}
+
+// Iter waits for the query to complete and returns its iterator. The iterator
+// of a future returned by ExecAsync has already been closed.
+func (f *Future) Iter() *Iter {
+	<-f.done
+	return f.iter
+}
+
+// Err waits for the query to complete and returns the error it failed with,
+// if any. For a future returned by IterAsync, errors that happen while
+// iterating over further pages are reported by closing the Iter.
+func (f *Future) Err() error {
+	<-f.done
+	return f.err
+}
+
+// Close waits for the query to complete, closes its iterator and returns its
+// error, like Iter().Close().
+func (f *Future) Close() error {
+	<-f.done
+	err := f.err
+	if f.iter != nil {
+		err = f.iter.Close()
+	}
+	if f.cancel != nil {
+		f.cancel()
+	}
+	return err
+}
+
+// Wait waits for the query to complete or ctx to be done, whichever happens
+// first, and returns the query error or the context error respectively.
+// Cancelling ctx does not cancel the query itself, use Query.WithContext
+// for that.
+func (f *Future) Wait(ctx context.Context) error {
+	select {
+	case <-f.done:
+		return f.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// ExecAsync executes the query without returning any rows, like Exec, in a
+// new goroutine. The query goes through the same execution path as Exec, so
+// retries, speculative executions and the QueryObserver all apply. The query
+// must not be modified or released until the returned future is done.
+func (q *Query) ExecAsync() *Future {
+	return newFuture(func() (*Iter, error) {
+		iter := q.Iter()
+		return iter, iter.Close()
+	})
+}
+
+// IterAsync executes the query like Iter in a new goroutine. The iterator
+// returned by the future's Iter method must be closed as usual. The query
+// must not be modified or released until the iterator is closed.
+func (q *Query) IterAsync() *Future {
+	return newFuture(func() (*Iter, error) {
+		iter := q.Iter()
+		return iter, iter.err
+	})
+}
+
+// ExecuteConcurrent executes queries like Iter, running at most concurrency
+// of them at the same time, and returns their futures in the same order as
+// queries, once all of them have completed. A non-positive concurrency runs
+// all of them at once. The rows of every query can be read from the Iter of
+// its future, which must then be closed with Future.Close.
+//
+// Each query runs with its own context, which is additionally cancelled when
+// ctx is done, until its future is closed. Once ctx is done, queries that
+// were not started yet are not executed and report ctx.Err().
+func (s *Session) ExecuteConcurrent(ctx context.Context, queries []*Query, concurrency int) []*Future {
+	futures := make([]*Future, len(queries))
+	if concurrency <= 0 || concurrency > len(queries) {
+		concurrency = len(queries)
+	}
+
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, qry := range queries {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if err := ctx.Err(); err != nil {
+			for j := i; j < len(queries); j++ {
+				futures[j] = &Future{iter: &Iter{err: err}, err: err, done: make(chan struct{})}
+				close(futures[j].done)
+			}
+			break
+		}
+
+		qctx, cancel := context.WithCancel(qry.Context())
+		stop := context.AfterFunc(ctx, cancel)
+		f := &Future{
+			done: make(chan struct{}),
+			cancel: func() {
+				stop()
+				cancel()
+			},
+		}
+		futures[i] = f
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer close(f.done)
+			defer func() { <-sem }()
+
+			f.iter = qry.WithContext(qctx).Iter()
+			f.err = f.iter.err
+		}()
+	}
+	wg.Wait()
+	return futures
+}
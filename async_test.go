@@ -0,0 +1,177 @@
+//go:build unit
+// +build unit
+
+package gocql
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql/internal/tests/mock"
+)
+
+func newAsyncTestQuery(exec func(ctx context.Context) *Iter) *Query {
+	qry := newWarningTestQuery()
+	qry.conn = &pagingTestConn{
+		executeQueryFunc: func(ctx context.Context, _ *Query) *Iter {
+			return exec(ctx)
+		},
+	}
+	return qry
+}
+
+func TestQueryExecAsync(t *testing.T) {
+	wantErr := errors.New("write timeout")
+	release := make(chan struct{})
+	qry := newAsyncTestQuery(func(context.Context) *Iter {
+		<-release
+		return &Iter{err: wantErr}
+	})
+
+	f := qry.ExecAsync()
+	select {
+	case <-f.Done():
+		t.Fatal("expected the future to be pending while the query runs")
+	default:
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	if err := f.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected Wait to give up with the context, got %v", err)
+	}
+
+	close(release)
+	if err := f.Err(); !errors.Is(err, wantErr) {
+		t.Fatalf("got error %v, want %v", err, wantErr)
+	}
+	if err := f.Wait(context.Background()); !errors.Is(err, wantErr) {
+		t.Fatalf("got error %v, want %v", err, wantErr)
+	}
+	if f.Iter() == nil {
+		t.Fatal("expected the future to carry the closed iterator")
+	}
+}
+
+func TestQueryIterAsync(t *testing.T) {
+	qry := newAsyncTestQuery(func(context.Context) *Iter {
+		return &Iter{}
+	})
+
+	f := qry.IterAsync()
+	if err := f.Err(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	iter := f.Iter()
+	if atomic.LoadInt32(&iter.closed) != 0 {
+		t.Fatal("expected the iterator to be left open for the caller")
+	}
+	if err := iter.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestSessionExecuteConcurrent(t *testing.T) {
+	t.Run("BoundsConcurrency", func(t *testing.T) {
+		const concurrency = 2
+		var running, maxRunning atomic.Int32
+		failing := errors.New("failed")
+
+		queries := make([]*Query, 6)
+		for i := range queries {
+			fail := i == 3
+			queries[i] = newAsyncTestQuery(func(context.Context) *Iter {
+				n := running.Add(1)
+				for {
+					max := maxRunning.Load()
+					if n <= max || maxRunning.CompareAndSwap(max, n) {
+						break
+					}
+				}
+				time.Sleep(5 * time.Millisecond)
+				running.Add(-1)
+				if fail {
+					return &Iter{err: failing}
+				}
+				return &Iter{}
+			})
+		}
+
+		futures := (&Session{}).ExecuteConcurrent(context.Background(), queries, concurrency)
+		for i, f := range futures {
+			err := f.Close()
+			if i == 3 {
+				if !errors.Is(err, failing) {
+					t.Errorf("query %d: got error %v, want %v", i, err, failing)
+				}
+			} else if err != nil {
+				t.Errorf("query %d: unexpected error: %v", i, err)
+			}
+		}
+		if got := maxRunning.Load(); got > concurrency {
+			t.Fatalf("ran %d queries at once, want at most %d", got, concurrency)
+		}
+	})
+
+	t.Run("Cancellation", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		started := make(chan struct{})
+		var executed atomic.Int32
+
+		queries := make([]*Query, 3)
+		for i := range queries {
+			queries[i] = newAsyncTestQuery(func(qctx context.Context) *Iter {
+				executed.Add(1)
+				close(started)
+				<-qctx.Done()
+				return &Iter{err: qctx.Err()}
+			})
+		}
+
+		go func() {
+			<-started
+			cancel()
+		}()
+		futures := (&Session{}).ExecuteConcurrent(ctx, queries, 1)
+
+		for i, f := range futures {
+			if err := f.Close(); !errors.Is(err, context.Canceled) {
+				t.Errorf("query %d: got error %v, want %v", i, err, context.Canceled)
+			}
+		}
+		if got := executed.Load(); got != 1 {
+			t.Fatalf("expected only the in-flight query to run, %d ran", got)
+		}
+	})
+
+	t.Run("Rows", func(t *testing.T) {
+		queries := make([]*Query, 2)
+		for i := range queries {
+			value := marshalTestInt(t, int32(i))
+			queries[i] = newAsyncTestQuery(func(context.Context) *Iter {
+				return &Iter{
+					meta: resultMetadata{
+						columns:        []ColumnInfo{{Name: "id", TypeInfo: NativeType{typ: TypeInt, proto: 4}}},
+						actualColCount: 1,
+					},
+					framer:  &mock.MockFramer{Data: [][]byte{value}},
+					numRows: 1,
+				}
+			})
+		}
+
+		futures := (&Session{}).ExecuteConcurrent(context.Background(), queries, 0)
+		for i, f := range futures {
+			var id int32
+			if !f.Iter().Scan(&id) || id != int32(i) {
+				t.Errorf("query %d: scanned %d, want %d", i, id, i)
+			}
+			if err := f.Close(); err != nil {
+				t.Errorf("query %d: unexpected error: %v", i, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,270 @@
+package gocql
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+const (
+	defaultBatchSplitMaxStatements = 100
+	// defaultBatchSplitMaxBytes matches the default batch_size_warn_threshold
+	// of Cassandra, well below the 128 KiB default of Scylla.
+	defaultBatchSplitMaxBytes = 5 * 1024
+)
+
+// BatchSplitter configures Session.ExecuteBatchSplit, which splits a batch
+// into single-partition sub-batches of bounded size. The zero value uses the
+// defaults described on each field.
+type BatchSplitter struct {
+	// MaxStatements caps the number of statements of every sub-batch.
+	// Defaults to 100.
+	MaxStatements int
+
+	// MaxBytes caps the size of the values and statements of every
+	// sub-batch, which is what the server batch size thresholds measure.
+	// A single statement larger than MaxBytes is sent on its own.
+	// Defaults to 5 KiB.
+	MaxBytes int
+
+	// Concurrency is the number of sub-batches executed at the same time.
+	// A non-positive value executes all of them at once.
+	Concurrency int
+}
+
+// BatchGroupError is the failure of a single sub-batch executed by
+// Session.ExecuteBatchSplit.
+type BatchGroupError struct {
+	Err error
+	// RoutingKey is the partition key shared by the entries, nil if the
+	// entries could not be routed.
+	RoutingKey []byte
+	Entries    []BatchEntry
+}
+
+func (e *BatchGroupError) Error() string {
+	return fmt.Sprintf("gocql: sub-batch of %d statements failed: %v", len(e.Entries), e.Err)
+}
+
+func (e *BatchGroupError) Unwrap() error {
+	return e.Err
+}
+
+// BatchSplitError is returned by Session.ExecuteBatchSplit when some of the
+// sub-batches failed. The other sub-batches were applied.
+type BatchSplitError struct {
+	Failed  []*BatchGroupError
+	Batches int
+}
+
+func (e *BatchSplitError) Error() string {
+	return fmt.Sprintf("gocql: %d of %d sub-batches failed, first error: %v", len(e.Failed), e.Batches, e.Failed[0].Err)
+}
+
+func (e *BatchSplitError) Unwrap() []error {
+	errs := make([]error, len(e.Failed))
+	for i, f := range e.Failed {
+		errs[i] = f
+	}
+	return errs
+}
+
+// batchSplitEntry is a batch entry with the partition it belongs to and its
+// size once marshalled.
+type batchSplitEntry struct {
+	info  *routingKeyInfo
+	key   []byte
+	group string
+	entry BatchEntry
+	size  int
+}
+
+// batchSplitGroup is a sub-batch of entries of the same partition.
+type batchSplitGroup struct {
+	info    *routingKeyInfo
+	key     []byte
+	entries []BatchEntry
+}
+
+// splitBatchEntries groups entries by partition, in the order the partitions
+// first appear and keeping the order of the entries of a partition, and then
+// splits every group so that it holds at most maxStatements entries of at
+// most maxBytes in total.
+func splitBatchEntries(entries []batchSplitEntry, maxStatements, maxBytes int) []batchSplitGroup {
+	var (
+		order  []string
+		groups = make(map[string][]batchSplitEntry)
+	)
+	for _, e := range entries {
+		if _, ok := groups[e.group]; !ok {
+			order = append(order, e.group)
+		}
+		groups[e.group] = append(groups[e.group], e)
+	}
+
+	var split []batchSplitGroup
+	for _, name := range order {
+		var (
+			cur  batchSplitGroup
+			size int
+		)
+		for _, e := range groups[name] {
+			if len(cur.entries) > 0 && (len(cur.entries) >= maxStatements || size+e.size > maxBytes) {
+				split = append(split, cur)
+				cur, size = batchSplitGroup{}, 0
+			}
+			cur.info, cur.key = e.info, e.key
+			cur.entries = append(cur.entries, e.entry)
+			size += e.size
+		}
+		split = append(split, cur)
+	}
+	return split
+}
+
+// batchSplitEntry resolves the partition of entry and its marshalled size.
+// Entries using a binding callback have no values to route them by, and are
+// grouped together.
+func (s *Session) batchSplitEntry(b *Batch, entry BatchEntry) (batchSplitEntry, error) {
+	e := batchSplitEntry{entry: entry, size: len(entry.Stmt)}
+	if entry.binding != nil {
+		return e, nil
+	}
+
+	info, err := s.routingKeyInfo(b.Context(), entry.Stmt, b.keyspace, b.GetRequestTimeout())
+	if err != nil {
+		return e, err
+	}
+	if e.key, err = createRoutingKey(s.codecs, info, entry.Args); err != nil {
+		return e, err
+	}
+	if info != nil {
+		e.info = info
+		e.group = info.keyspace + "\x00" + string(e.key)
+	}
+
+	if len(entry.Args) > 0 {
+		conn := s.getConn()
+		if conn == nil {
+			return e, errors.New("gocql: unable to fetch prepared info: no connection available")
+		}
+		prepared, err := conn.prepareStatement(b.Context(), entry.Stmt, nil, b.keyspace, b.GetRequestTimeout())
+		if err != nil {
+			return e, err
+		}
+		columns := prepared.request.columns
+		if len(entry.Args) != len(columns) {
+			return e, ErrQueryArgLength
+		}
+		for i, arg := range entry.Args {
+			var v queryValues
+			if err := marshalQueryValue(s.codecs, columns[i].TypeInfo, arg, &v); err != nil {
+				return e, err
+			}
+			e.size += len(v.value)
+		}
+	}
+	return e, nil
+}
+
+// subBatch returns a batch with the settings of b, holding the entries of g
+// and routed by its partition key.
+func (b *Batch) subBatch(g batchSplitGroup) *Batch {
+	sub := *b
+	sub.Entries = g.entries
+	sub.routingKey = g.key
+	sub.routingInfo = &queryRoutingInfo{}
+	if g.info != nil {
+		sub.routingInfo.partitioner = g.info.partitioner
+		sub.routingInfo.lwt = g.info.lwt
+		sub.routingInfo.keyspace = g.info.keyspace
+		sub.routingInfo.table = g.info.table
+	}
+	sub.metrics = newQueryMetrics()
+	sub.metricsOwner = queryMetricsOwner{}
+	sub.metricsOwner.self = &sub.metricsOwner
+	sub.executionAttempts = nil
+	return &sub
+}
+
+// ExecuteBatchSplit executes the entries of batch as several sub-batches,
+// one or more per partition, so that none of them spans partitions or exceeds
+// the limits of splitter. Entries of a partition keep their relative order.
+//
+// The sub-batches keep the settings of batch, are routed to a replica of
+// their partition and are executed concurrently. Entries added with a binding
+// callback can not be routed and are sent in sub-batches of their own.
+//
+// Splitting a logged batch would lose its atomicity, so only unlogged and
+// counter batches are accepted, and conditional statements must all belong to
+// the same sub-batch.
+//
+// If some of the sub-batches fail, a *BatchSplitError listing them is
+// returned.
+func (s *Session) ExecuteBatchSplit(batch *Batch, splitter BatchSplitter) error {
+	if s.Closed() {
+		return ErrSessionClosed
+	}
+	if batch.Type == LoggedBatch {
+		return errors.New("gocql: splitting a logged batch would break its atomicity")
+	}
+	if len(batch.Entries) == 0 {
+		return nil
+	}
+
+	maxStatements := splitter.MaxStatements
+	if maxStatements <= 0 {
+		maxStatements = defaultBatchSplitMaxStatements
+	}
+	maxBytes := splitter.MaxBytes
+	if maxBytes <= 0 {
+		maxBytes = defaultBatchSplitMaxBytes
+	}
+
+	entries := make([]batchSplitEntry, len(batch.Entries))
+	lwt := false
+	for i, entry := range batch.Entries {
+		e, err := s.batchSplitEntry(batch, entry)
+		if err != nil {
+			return fmt.Errorf("gocql: unable to route batch statement %d: %w", i, err)
+		}
+		entries[i] = e
+		lwt = lwt || e.info != nil && e.info.lwt
+	}
+	groups := splitBatchEntries(entries, maxStatements, maxBytes)
+	if lwt && len(groups) > 1 {
+		return errors.New("gocql: a batch with conditional statements can not be split")
+	}
+
+	concurrency := splitter.Concurrency
+	if concurrency <= 0 || concurrency > len(groups) {
+		concurrency = len(groups)
+	}
+
+	errs := make([]error, len(groups))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, g := range groups {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			errs[i] = s.ExecuteBatch(batch.subBatch(g))
+		}()
+	}
+	wg.Wait()
+
+	var failed []*BatchGroupError
+	for i, err := range errs {
+		if err != nil {
+			failed = append(failed, &BatchGroupError{Err: err, RoutingKey: groups[i].key, Entries: groups[i].entries})
+		}
+	}
+	if len(failed) > 0 {
+		return &BatchSplitError{Failed: failed, Batches: len(groups)}
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+//go:build unit
+// +build unit
+
+package gocql
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSplitBatchEntries(t *testing.T) {
+	info := &routingKeyInfo{keyspace: "ks", table: "tbl", partitioner: murmur3Partitioner{}}
+	entry := func(stmt, group string, size int) batchSplitEntry {
+		return batchSplitEntry{
+			info:  info,
+			key:   []byte(group),
+			group: group,
+			entry: BatchEntry{Stmt: stmt},
+			size:  size,
+		}
+	}
+	entries := []batchSplitEntry{
+		entry("a1", "a", 10),
+		entry("b1", "b", 10),
+		entry("a2", "a", 10),
+		entry("a3", "a", 10),
+		entry("b2", "b", 100),
+		entry("a4", "a", 10),
+		entry("b3", "b", 10),
+	}
+
+	groups := splitBatchEntries(entries, 3, 50)
+	want := [][]string{{"a1", "a2", "a3"}, {"a4"}, {"b1"}, {"b2"}, {"b3"}}
+	if len(groups) != len(want) {
+		t.Fatalf("got %d sub-batches, want %d", len(groups), len(want))
+	}
+	for i, g := range groups {
+		if len(g.entries) != len(want[i]) {
+			t.Fatalf("sub-batch %d: got %d entries, want %v", i, len(g.entries), want[i])
+		}
+		for j, e := range g.entries {
+			if e.Stmt != want[i][j] {
+				t.Fatalf("sub-batch %d: got statement %q at %d, want %q", i, e.Stmt, j, want[i][j])
+			}
+		}
+		if string(g.key) != want[i][0][:1] {
+			t.Fatalf("sub-batch %d: got routing key %q, want %q", i, g.key, want[i][0][:1])
+		}
+	}
+}
+
+func TestBatchSubBatch(t *testing.T) {
+	b := &Batch{
+		Type:        UnloggedBatch,
+		Cons:        Quorum,
+		routingInfo: &queryRoutingInfo{},
+		metrics:     newQueryMetrics(),
+		Entries:     []BatchEntry{{Stmt: "a"}, {Stmt: "b"}},
+	}
+	info := &routingKeyInfo{keyspace: "ks", table: "tbl", partitioner: murmur3Partitioner{}}
+	sub := b.subBatch(batchSplitGroup{info: info, key: []byte{1}, entries: b.Entries[1:]})
+
+	if sub.Cons != Quorum || sub.Type != UnloggedBatch {
+		t.Fatal("expected the sub-batch to keep the settings of the batch")
+	}
+	if sub.Size() != 1 || sub.Entries[0].Stmt != "b" {
+		t.Fatalf("got entries %v, want the entries of the group", sub.Entries)
+	}
+	key, err := sub.GetRoutingKey()
+	if err != nil || len(key) != 1 || key[0] != 1 {
+		t.Fatalf("got routing key %v (%v), want the key of the group", key, err)
+	}
+	if sub.Table() != "tbl" || sub.GetCustomPartitioner() == nil {
+		t.Fatal("expected the sub-batch to carry the routing info of the group")
+	}
+	if sub.routingInfo == b.routingInfo || sub.metrics == b.metrics {
+		t.Fatal("expected the sub-batch not to share state with the batch")
+	}
+}
+
+func TestBatchSplitError(t *testing.T) {
+	cause := errors.New("write timeout")
+	err := error(&BatchSplitError{
+		Failed:  []*BatchGroupError{{Err: cause, Entries: []BatchEntry{{Stmt: "a"}}}},
+		Batches: 3,
+	})
+	if !errors.Is(err, cause) {
+		t.Fatal("expected BatchSplitError to unwrap to the failures of its groups")
+	}
+	var groupErr *BatchGroupError
+	if !errors.As(err, &groupErr) || len(groupErr.Entries) != 1 {
+		t.Fatal("expected BatchSplitError to unwrap to a BatchGroupError")
+	}
+
+	s := &Session{}
+	if err := s.ExecuteBatchSplit(&Batch{Type: LoggedBatch, Entries: []BatchEntry{{Stmt: "a"}}}, BatchSplitter{}); err == nil {
+		t.Fatal("expected splitting a logged batch to fail")
+	}
+}
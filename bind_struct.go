@@ -0,0 +1,119 @@
+package gocql
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// missingBindMarkersError reports the bind markers that source has no value for.
+func missingBindMarkersError(missing []string, source string) error {
+	return fmt.Errorf("gocql: no value in %s for bind markers %q", source, missing)
+}
+
+// bindStructValues resolves the values of the bind markers described by args
+// from the fields of v, which must be a struct or a pointer to one.
+func bindStructValues(v any, args []ColumnInfo) ([]any, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil, fmt.Errorf("gocql: cannot bind nil %T", v)
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("gocql: cannot bind %T, a struct or a pointer to a struct is required", v)
+	}
+
+	fields := cachedStructFields(rv.Type())
+	values := make([]any, len(args))
+	var missing []string
+	for i, arg := range args {
+		field, ok := fields.byName[arg.Name]
+		if !ok {
+			missing = append(missing, arg.Name)
+			continue
+		}
+
+		fv, err := rv.FieldByIndexErr(field.index)
+		if err != nil {
+			// The field sits behind a nil embedded struct pointer, so it has
+			// no value of its own.
+			if field.omitEmpty {
+				values[i] = UnsetValue
+			}
+			continue
+		}
+		if field.omitEmpty && fv.IsZero() {
+			values[i] = UnsetValue
+			continue
+		}
+		values[i] = fv.Interface()
+	}
+	if len(missing) > 0 {
+		return nil, missingBindMarkersError(missing, rv.Type().String())
+	}
+	return values, nil
+}
+
+// bindMapValues resolves the values of the bind markers described by args
+// from m.
+func bindMapValues(m map[string]any, args []ColumnInfo) ([]any, error) {
+	values := make([]any, len(args))
+	var missing []string
+	for i, arg := range args {
+		value, ok := m[arg.Name]
+		if !ok {
+			missing = append(missing, arg.Name)
+			continue
+		}
+		values[i] = value
+	}
+	if len(missing) > 0 {
+		return nil, missingBindMarkersError(missing, "map")
+	}
+	return values, nil
+}
+
+// BindStruct sets the query arguments from the fields of v, which must be a
+// struct or a pointer to one. The query is prepared and each bind marker is
+// resolved by name, either the name of a named marker (:name) or the column a
+// positional marker (?) is bound to, against the struct fields as mapped by
+// StructScan: by their `cql:"name"` tag or their lower-cased name.
+//
+// Fields tagged with the omitempty option, as in `cql:"name,omitempty"`, are
+// sent as UnsetValue instead of null when they hold their zero value, which
+// avoids writing tombstones. Executing the query fails with an error naming
+// every bind marker that has no corresponding field.
+//
+// The values are read from v when the query is executed, so v must not be
+// modified until then. Like queries created with Session.Bind, the query is
+// not routed by its token.
+func (q *Query) BindStruct(v any) *Query {
+	q.values = nil
+	q.pageState = nil
+	q.binding = func(info *QueryInfo) ([]any, error) {
+		return bindStructValues(v, info.Args)
+	}
+	return q
+}
+
+// BindMap sets the query arguments from m, keyed by bind marker name. See
+// BindStruct for how bind markers are named. Executing the query fails with
+// an error naming every bind marker missing from m.
+func (q *Query) BindMap(m map[string]any) *Query {
+	q.values = nil
+	q.pageState = nil
+	q.binding = func(info *QueryInfo) ([]any, error) {
+		return bindMapValues(m, info.Args)
+	}
+	return q
+}
+
+// QueryStruct adds the query to the batch operation, binding its arguments
+// from the fields of v as described in Query.BindStruct.
+func (b *Batch) QueryStruct(stmt string, v any) *Batch {
+	b.Entries = append(b.Entries, BatchEntry{Stmt: stmt, binding: func(info *QueryInfo) ([]any, error) {
+		return bindStructValues(v, info.Args)
+	}})
+	return b
+}
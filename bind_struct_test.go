@@ -0,0 +1,135 @@
+//go:build unit
+// +build unit
+
+package gocql
+
+import (
+	"strings"
+	"testing"
+)
+
+type BindStructAudit struct {
+	UpdatedBy string `cql:"updated_by"`
+}
+
+type bindStructUser struct {
+	*BindStructAudit
+	ID       int32   `cql:"id"`
+	Name     string  `cql:"name,omitempty"`
+	Nickname *string `cql:"nickname"`
+	Email    string
+}
+
+func bindMarkers(names ...string) []ColumnInfo {
+	args := make([]ColumnInfo, len(names))
+	for i, name := range names {
+		args[i] = ColumnInfo{Name: name, TypeInfo: NativeType{typ: TypeVarchar, proto: 4}}
+	}
+	return args
+}
+
+func TestBindStructValues(t *testing.T) {
+	t.Run("ResolvesByName", func(t *testing.T) {
+		nickname := "al"
+		user := bindStructUser{ID: 1, Name: "alice", Nickname: &nickname, Email: "a@example.com"}
+
+		values, err := bindStructValues(&user, bindMarkers("email", "nickname", "id", "name"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if values[0] != "a@example.com" || values[1] != &nickname || values[2] != int32(1) || values[3] != "alice" {
+			t.Fatalf("unexpected values: %v", values)
+		}
+	})
+
+	t.Run("OmitEmptyBindsUnset", func(t *testing.T) {
+		values, err := bindStructValues(bindStructUser{ID: 1}, bindMarkers("id", "name", "nickname"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if values[1] != UnsetValue {
+			t.Errorf("expected an empty omitempty field to be unset, got %v", values[1])
+		}
+		if values[2] != (*string)(nil) {
+			t.Errorf("expected an empty field without omitempty to be null, got %v", values[2])
+		}
+	})
+
+	t.Run("NilEmbeddedPointerBindsNull", func(t *testing.T) {
+		values, err := bindStructValues(bindStructUser{}, bindMarkers("updated_by"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if values[0] != nil {
+			t.Fatalf("expected null, got %v", values[0])
+		}
+	})
+
+	t.Run("ReportsMissingMarkers", func(t *testing.T) {
+		_, err := bindStructValues(bindStructUser{}, bindMarkers("id", "age", "city"))
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+		if !strings.Contains(err.Error(), `"age"`) || !strings.Contains(err.Error(), `"city"`) {
+			t.Fatalf("expected the error to name every missing marker, got %v", err)
+		}
+	})
+
+	t.Run("RejectsNonStruct", func(t *testing.T) {
+		if _, err := bindStructValues(42, bindMarkers("id")); err == nil {
+			t.Fatal("expected an error")
+		}
+		if _, err := bindStructValues((*bindStructUser)(nil), bindMarkers("id")); err == nil {
+			t.Fatal("expected an error")
+		}
+	})
+}
+
+func TestBindMapValues(t *testing.T) {
+	values, err := bindMapValues(map[string]any{"id": 1, "name": "alice"}, bindMarkers("name", "id"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if values[0] != "alice" || values[1] != 1 {
+		t.Fatalf("unexpected values: %v", values)
+	}
+
+	if _, err := bindMapValues(map[string]any{"id": 1}, bindMarkers("id", "name")); err == nil ||
+		!strings.Contains(err.Error(), `"name"`) {
+		t.Fatalf("expected an error naming the missing marker, got %v", err)
+	}
+}
+
+func TestQueryBindStruct(t *testing.T) {
+	q := &Query{values: []any{1}, pageState: []byte{1}}
+	q.BindStruct(bindStructUser{ID: 7})
+	if q.values != nil || q.pageState != nil {
+		t.Fatal("expected BindStruct to clear positional values and paging state")
+	}
+	values, err := q.binding(&QueryInfo{Args: bindMarkers("id")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if values[0] != int32(7) {
+		t.Fatalf("unexpected values: %v", values)
+	}
+
+	q.BindMap(map[string]any{"id": 8})
+	values, err = q.binding(&QueryInfo{Args: bindMarkers("id")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if values[0] != 8 {
+		t.Fatalf("unexpected values: %v", values)
+	}
+
+	b := &Batch{}
+	b.QueryStruct("INSERT INTO users (id) VALUES (?)", bindStructUser{ID: 9})
+	values, err = b.Entries[0].binding(&QueryInfo{Args: bindMarkers("id")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if values[0] != int32(9) {
+		t.Fatalf("unexpected values: %v", values)
+	}
+}
@@ -0,0 +1,290 @@
+package gocql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+const (
+	defaultBulkWriterQueueSize    = 256
+	defaultBulkWriterHostInFlight = 128
+)
+
+// ErrBulkWriterClosed is returned by BulkWriter.Write once the writer is closed.
+var ErrBulkWriterClosed = errors.New("gocql: bulk writer is closed")
+
+// BulkWriterOptions configures a BulkWriter.
+type BulkWriterOptions struct {
+	// OnError, if set, is called with the values of every row that could
+	// not be written, after retries. It is called concurrently from
+	// multiple goroutines.
+	OnError func(values []any, err error)
+
+	// RetryPolicy is the retry policy of the writes. Defaults to the
+	// session retry policy. Only idempotent writes are retried.
+	RetryPolicy RetryPolicy
+
+	// QueueSize is the number of rows that can be queued for every shard
+	// of every host before Write blocks. Defaults to 256.
+	QueueSize int
+
+	// MaxInFlightPerHost caps the number of writes in flight to every
+	// host. Defaults to 128.
+	MaxInFlightPerHost int
+
+	// Consistency is the consistency of the writes. Defaults to the
+	// session consistency when zero.
+	Consistency Consistency
+
+	// Idempotent marks the writes as idempotent, which allows retrying
+	// them. Writes of conditional statements, counter updates or list
+	// appends must not be marked idempotent.
+	Idempotent bool
+}
+
+// bulkWriterQueueKey identifies the replica and shard a row is queued for.
+type bulkWriterQueueKey struct {
+	hostID string
+	shard  int
+}
+
+type bulkWriterQueue struct {
+	rows chan *Query
+	// inFlight is shared by the queues of all the shards of a host.
+	inFlight chan struct{}
+}
+
+// BulkWriter writes rows with a single prepared statement at high throughput.
+// Rows are grouped by the replica, and the shard of the replica, that owns
+// their partition, and every group is queued and written concurrently, with
+// a bounded number of writes in flight to every host. Write blocks when the
+// queue of a group is full, which paces the producer to the cluster.
+//
+// A BulkWriter is safe for concurrent use. It must be closed to release its
+// goroutines.
+type BulkWriter struct {
+	ctx      context.Context
+	session  *Session
+	ps       *PreparedStatement
+	opts     BulkWriterOptions
+	queues   map[bulkWriterQueueKey]*bulkWriterQueue
+	inFlight map[string]chan struct{}
+	// exec is overridden in tests.
+	exec     func(*Query) error
+	done     chan struct{}
+	drained  chan struct{}
+	firstErr error
+	mu       sync.Mutex
+	pending  int
+	failed   int
+	closed   bool
+}
+
+// NewBulkWriter prepares stmt and returns a BulkWriter executing it. Writes
+// run with ctx, cancelling it fails the writes that were not executed yet.
+func (s *Session) NewBulkWriter(ctx context.Context, stmt string, opts BulkWriterOptions) (*BulkWriter, error) {
+	ps, err := s.Prepare(ctx, stmt)
+	if err != nil {
+		return nil, err
+	}
+	return newBulkWriter(ctx, s, ps, opts), nil
+}
+
+func newBulkWriter(ctx context.Context, s *Session, ps *PreparedStatement, opts BulkWriterOptions) *BulkWriter {
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = defaultBulkWriterQueueSize
+	}
+	if opts.MaxInFlightPerHost <= 0 {
+		opts.MaxInFlightPerHost = defaultBulkWriterHostInFlight
+	}
+	return &BulkWriter{
+		ctx:      ctx,
+		session:  s,
+		ps:       ps,
+		opts:     opts,
+		queues:   make(map[bulkWriterQueueKey]*bulkWriterQueue),
+		inFlight: make(map[string]chan struct{}),
+		exec:     (*Query).Exec,
+		done:     make(chan struct{}),
+	}
+}
+
+// route returns the replica and shard owning the partition of qry. Rows that
+// can not be routed share a single queue.
+func (w *BulkWriter) route(qry *Query) bulkWriterQueueKey {
+	key := bulkWriterQueueKey{shard: -1}
+	if w.session.policy == nil {
+		return key
+	}
+	selected := w.session.policy.Pick(qry)()
+	if selected == nil || selected.Info() == nil {
+		return key
+	}
+	host := selected.Info()
+	key.hostID = host.HostID()
+
+	token, ok := int64Token(0), false
+	if th, isInt64 := selected.(int64TokenSelectedHost); isInt64 {
+		token, ok = th.TokenInt64()
+	} else {
+		token, ok = selected.Token().(int64Token)
+	}
+	if ok && w.session.pool != nil {
+		if pool, found := w.session.pool.getPool(host); found {
+			key.shard = pool.shardOf(token)
+		}
+	}
+	return key
+}
+
+// queue returns the queue of key, starting its dispatcher if needed.
+func (w *BulkWriter) queue(key bulkWriterQueueKey) *bulkWriterQueue {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if q, ok := w.queues[key]; ok {
+		return q
+	}
+
+	inFlight, ok := w.inFlight[key.hostID]
+	if !ok {
+		inFlight = make(chan struct{}, w.opts.MaxInFlightPerHost)
+		w.inFlight[key.hostID] = inFlight
+	}
+	q := &bulkWriterQueue{
+		rows:     make(chan *Query, w.opts.QueueSize),
+		inFlight: inFlight,
+	}
+	w.queues[key] = q
+	go w.dispatch(q)
+	return q
+}
+
+// dispatch executes the rows of q as host capacity allows.
+func (w *BulkWriter) dispatch(q *bulkWriterQueue) {
+	for {
+		select {
+		case qry := <-q.rows:
+			q.inFlight <- struct{}{}
+			go func() {
+				defer func() { <-q.inFlight }()
+				w.write(qry)
+			}()
+		case <-w.done:
+			return
+		}
+	}
+}
+
+func (w *BulkWriter) write(qry *Query) {
+	values := qry.values
+	err := w.exec(qry)
+	qry.Release()
+	if err != nil && w.opts.OnError != nil {
+		w.opts.OnError(values, err)
+	}
+	w.finish(err)
+}
+
+// BindMarkers returns the bind markers of the statement, in the order Write
+// takes the values of a row in. See PreparedStatement.BindMarkers.
+func (w *BulkWriter) BindMarkers() []ColumnInfo {
+	return w.ps.BindMarkers()
+}
+
+// Write queues a row for writing, blocking while the queue of the replica
+// and shard owning the row is full. It returns once the row is queued, not
+// written: write failures are reported to BulkWriterOptions.OnError and by
+// Flush. values must not be modified until the row is written.
+func (w *BulkWriter) Write(ctx context.Context, values ...any) error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return ErrBulkWriterClosed
+	}
+	if w.pending == 0 {
+		w.drained = make(chan struct{})
+	}
+	w.pending++
+	w.mu.Unlock()
+
+	qry := w.ps.Bind(values...).Idempotent(w.opts.Idempotent)
+	qry.context = w.ctx
+	if w.opts.RetryPolicy != nil {
+		qry.RetryPolicy(w.opts.RetryPolicy)
+	}
+	if w.opts.Consistency != 0 {
+		qry.Consistency(w.opts.Consistency)
+	}
+
+	q := w.queue(w.route(qry))
+	select {
+	case q.rows <- qry:
+		return nil
+	case <-ctx.Done():
+		qry.Release()
+		w.finish(nil)
+		return ctx.Err()
+	}
+}
+
+// finish records the outcome of a row leaving the writer.
+func (w *BulkWriter) finish(err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err != nil {
+		w.failed++
+		if w.firstErr == nil {
+			w.firstErr = err
+		}
+	}
+	w.pending--
+	if w.pending == 0 {
+		close(w.drained)
+	}
+}
+
+// Flush waits until every queued row has been written or ctx is done. It
+// returns an error if rows failed to be written since the previous Flush.
+func (w *BulkWriter) Flush(ctx context.Context) error {
+	w.mu.Lock()
+	drained := w.drained
+	if w.pending == 0 {
+		drained = nil
+	}
+	w.mu.Unlock()
+
+	if drained != nil {
+		select {
+		case <-drained:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.failed == 0 {
+		return nil
+	}
+	err := fmt.Errorf("gocql: %d bulk writes failed, first error: %w", w.failed, w.firstErr)
+	w.failed, w.firstErr = 0, nil
+	return err
+}
+
+// Close stops accepting rows, waits for the queued rows to be written and
+// releases the writer. It returns the same error as Flush.
+func (w *BulkWriter) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	w.mu.Unlock()
+
+	err := w.Flush(context.Background())
+	close(w.done)
+	return err
+}
@@ -0,0 +1,114 @@
+//go:build unit
+// +build unit
+
+package gocql
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newBulkWriterTest(opts BulkWriterOptions, exec func(*Query) error) *BulkWriter {
+	ps := &PreparedStatement{session: &Session{}, stmt: "INSERT INTO ks.tbl (pk, v) VALUES (?, ?)"}
+	w := newBulkWriter(context.Background(), ps.session, ps, opts)
+	w.exec = exec
+	return w
+}
+
+func TestBulkWriterReportsFailures(t *testing.T) {
+	failing := errors.New("write timeout")
+	var (
+		mu      sync.Mutex
+		written []any
+		failed  []any
+	)
+	w := newBulkWriterTest(BulkWriterOptions{
+		Idempotent: true,
+		OnError: func(values []any, err error) {
+			if !errors.Is(err, failing) {
+				t.Errorf("got error %v, want %v", err, failing)
+			}
+			mu.Lock()
+			failed = append(failed, values[0])
+			mu.Unlock()
+		},
+	}, func(qry *Query) error {
+		if !qry.IsIdempotent() {
+			t.Error("expected the write to be idempotent")
+		}
+		if qry.values[0] == 3 {
+			return failing
+		}
+		mu.Lock()
+		written = append(written, qry.values[0])
+		mu.Unlock()
+		return nil
+	})
+
+	for i := 0; i < 5; i++ {
+		if err := w.Write(context.Background(), i, "v"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if err := w.Flush(context.Background()); !errors.Is(err, failing) {
+		t.Fatalf("got error %v, want %v", err, failing)
+	}
+	if len(written) != 4 || len(failed) != 1 || failed[0] != 3 {
+		t.Fatalf("got written %v and failed %v, want row 3 to fail", written, failed)
+	}
+	if err := w.Flush(context.Background()); err != nil {
+		t.Fatalf("expected failures to be reported once, got %v", err)
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := w.Write(context.Background(), 6, "v"); !errors.Is(err, ErrBulkWriterClosed) {
+		t.Fatalf("got error %v, want %v", err, ErrBulkWriterClosed)
+	}
+}
+
+func TestBulkWriterBackpressure(t *testing.T) {
+	release := make(chan struct{})
+	var running, maxRunning atomic.Int32
+	w := newBulkWriterTest(BulkWriterOptions{QueueSize: 1, MaxInFlightPerHost: 2}, func(*Query) error {
+		n := running.Add(1)
+		for {
+			max := maxRunning.Load()
+			if n <= max || maxRunning.CompareAndSwap(max, n) {
+				break
+			}
+		}
+		<-release
+		running.Add(-1)
+		return nil
+	})
+
+	// Two writes in flight, one taken by the dispatcher waiting for capacity
+	// and one queued fill the writer.
+	for i := 0; i < 4; i++ {
+		if err := w.Write(context.Background(), i, "v"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		// Let the dispatcher take the row off the queue.
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := w.Write(ctx, 4, "v"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected Write to block while the queue is full, got %v", err)
+	}
+
+	close(release)
+	if err := w.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := maxRunning.Load(); got != 2 {
+		t.Fatalf("ran %d writes at once, want 2", got)
+	}
+}
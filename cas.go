@@ -0,0 +1,150 @@
+package gocql
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// casAppliedColumn is the column conditional statements report whether they
+// were applied in.
+const casAppliedColumn = "[applied]"
+
+const (
+	defaultCASMaxAttempts = 10
+	defaultCASMinBackoff  = 10 * time.Millisecond
+	defaultCASMaxBackoff  = time.Second
+)
+
+// ErrCASNotApplied is returned by CompareAndSet when the conditional write
+// did not apply within the allowed number of attempts.
+var ErrCASNotApplied = errors.New("gocql: compare-and-set not applied, too many conflicting updates")
+
+// ScanCASStruct executes a lightweight transaction (i.e. an UPDATE or INSERT
+// statement containing an IF clause) and reports whether it was applied. If
+// it was not, the current values returned by the server are copied into the
+// struct pointed at by dest, matching columns to fields as StructScan does.
+//
+// Unlike ScanCAS, columns are matched by name, so dest does not need to list
+// the columns in the order the server returns them.
+func (q *Query) ScanCASStruct(dest any) (applied bool, err error) {
+	q.disableSkipMetadata = true
+	iter := q.Iter()
+	if err := iter.checkErrAndNotFound(); err != nil {
+		iter.Close()
+		return false, err
+	}
+	iter.structScan(dest, &applied)
+	return applied, iter.Close()
+}
+
+// CompareAndSetOptions configures CompareAndSet.
+type CompareAndSetOptions struct {
+	// WriteApplied reports, from the state just read with serial
+	// consistency, whether a write whose outcome is unknown was applied.
+	// If nil, a write failing with RequestErrCASWriteUnknown makes
+	// CompareAndSet return that error, since retrying it could apply the
+	// update twice.
+	WriteApplied func() bool
+
+	// MaxAttempts is the number of times the state is read and the write
+	// attempted before giving up with ErrCASNotApplied. Defaults to 10.
+	MaxAttempts int
+
+	// MinBackoff and MaxBackoff bound the exponentially growing, jittered
+	// delay between attempts. They default to 10ms and 1s.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+
+	// ReadConsistency is passed to the read function for regular reads.
+	// Zero leaves the consistency of the read unchanged.
+	ReadConsistency Consistency
+
+	// SerialConsistency is passed to the read function when re-reading the
+	// state after a write of unknown outcome. Defaults to the session
+	// serial consistency, or SERIAL if it is not set.
+	SerialConsistency Consistency
+}
+
+// CompareAndSet runs a read-modify-write cycle with optimistic concurrency.
+// read loads the current state, with the given consistency when it is not
+// zero, and write attempts a conditional update of that state, reporting
+// whether it was applied, typically with Query.ScanCAS or ScanCASStruct.
+// Whenever the update is not applied because of a concurrent update, the
+// state is read again and the write retried after a backoff.
+//
+// A write failing with RequestErrCASWriteUnknown may or may not have been
+// applied. The state is then re-read with serial consistency, which completes
+// any in-progress Paxos round, and opts.WriteApplied decides whether the
+// write went through or must be retried.
+//
+// Any other error returned by read or write, or by ctx, stops CompareAndSet
+// and is returned.
+func CompareAndSet(ctx context.Context, session *Session, read func(ctx context.Context, cons Consistency) error,
+	write func(ctx context.Context) (applied bool, err error), opts CompareAndSetOptions) error {
+	if session != nil && session.Closed() {
+		return ErrSessionClosed
+	}
+
+	maxAttempts := opts.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = defaultCASMaxAttempts
+	}
+	minBackoff, maxBackoff := opts.MinBackoff, opts.MaxBackoff
+	if minBackoff <= 0 {
+		minBackoff = defaultCASMinBackoff
+	}
+	if maxBackoff <= 0 {
+		maxBackoff = defaultCASMaxBackoff
+	}
+	serialCons := opts.SerialConsistency
+	if serialCons == 0 && session != nil {
+		serialCons = session.cfg.SerialConsistency
+	}
+	if serialCons == 0 {
+		serialCons = Serial
+	}
+
+	// fresh is set when the state was just re-read with serial consistency
+	// and does not need to be read again.
+	fresh := false
+	for attempt := 1; ; attempt++ {
+		if !fresh {
+			if err := read(ctx, opts.ReadConsistency); err != nil {
+				return err
+			}
+		}
+		fresh = false
+
+		applied, err := write(ctx)
+		var unknown *RequestErrCASWriteUnknown
+		switch {
+		case errors.As(err, &unknown):
+			if opts.WriteApplied == nil {
+				return err
+			}
+			if err := read(ctx, serialCons); err != nil {
+				return err
+			}
+			if opts.WriteApplied() {
+				return nil
+			}
+			fresh = true
+		case err != nil:
+			return err
+		case applied:
+			return nil
+		}
+
+		if attempt >= maxAttempts {
+			return ErrCASNotApplied
+		}
+		t := time.NewTimer(getExponentialTime(minBackoff, maxBackoff, attempt))
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		}
+	}
+}
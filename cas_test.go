@@ -0,0 +1,129 @@
+//go:build unit
+// +build unit
+
+package gocql
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql/internal/tests/mock"
+)
+
+func TestQueryScanCASStruct(t *testing.T) {
+	boolType := NativeType{typ: TypeBoolean, proto: 4}
+	applied, err := Marshal(boolType, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	qry := newAsyncTestQuery(func(context.Context) *Iter {
+		return &Iter{
+			meta: resultMetadata{
+				columns: []ColumnInfo{
+					{Name: "[applied]", TypeInfo: boolType},
+					{Name: "user_name", TypeInfo: NativeType{typ: TypeVarchar, proto: 4}},
+					{Name: "id", TypeInfo: NativeType{typ: TypeInt, proto: 4}},
+				},
+				actualColCount: 3,
+			},
+			framer:  &mock.MockFramer{Data: [][]byte{applied, []byte("alice"), marshalTestInt(t, 42)}},
+			numRows: 1,
+		}
+	})
+
+	var cur struct {
+		ID   int32  `cql:"id"`
+		Name string `cql:"user_name"`
+	}
+	ok, err := qry.ScanCASStruct(&cur)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ok {
+		t.Fatal("expected the transaction not to be applied")
+	}
+	if cur.ID != 42 || cur.Name != "alice" {
+		t.Fatalf("got current values %+v, want id 42 and name alice", cur)
+	}
+}
+
+func TestCompareAndSet(t *testing.T) {
+	opts := CompareAndSetOptions{MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+
+	t.Run("RetriesConflicts", func(t *testing.T) {
+		var reads, writes int
+		err := CompareAndSet(context.Background(), nil, func(context.Context, Consistency) error {
+			reads++
+			return nil
+		}, func(context.Context) (bool, error) {
+			writes++
+			return writes == 3, nil
+		}, opts)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if reads != 3 || writes != 3 {
+			t.Fatalf("got %d reads and %d writes, want 3 of each", reads, writes)
+		}
+	})
+
+	t.Run("GivesUp", func(t *testing.T) {
+		opts := opts
+		opts.MaxAttempts = 2
+		err := CompareAndSet(context.Background(), nil, func(context.Context, Consistency) error {
+			return nil
+		}, func(context.Context) (bool, error) {
+			return false, nil
+		}, opts)
+		if !errors.Is(err, ErrCASNotApplied) {
+			t.Fatalf("got error %v, want %v", err, ErrCASNotApplied)
+		}
+	})
+
+	t.Run("ResolvesUnknownWrites", func(t *testing.T) {
+		var (
+			consistencies []Consistency
+			writes        int
+			stored        bool
+		)
+		opts := opts
+		opts.WriteApplied = func() bool { return stored }
+		err := CompareAndSet(context.Background(), nil, func(_ context.Context, cons Consistency) error {
+			consistencies = append(consistencies, cons)
+			return nil
+		}, func(context.Context) (bool, error) {
+			writes++
+			if writes == 2 {
+				// The second write lands, but its outcome is unknown.
+				stored = true
+			}
+			return false, &RequestErrCASWriteUnknown{}
+		}, opts)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := []Consistency{Any, Serial, Serial}
+		if len(consistencies) != len(want) {
+			t.Fatalf("got reads with %v, want %v", consistencies, want)
+		}
+		for i := range want {
+			if consistencies[i] != want[i] {
+				t.Fatalf("got reads with %v, want %v", consistencies, want)
+			}
+		}
+	})
+
+	t.Run("UnknownWriteWithoutResolver", func(t *testing.T) {
+		err := CompareAndSet(context.Background(), nil, func(context.Context, Consistency) error {
+			return nil
+		}, func(context.Context) (bool, error) {
+			return false, &RequestErrCASWriteUnknown{}
+		}, opts)
+		var unknown *RequestErrCASWriteUnknown
+		if !errors.As(err, &unknown) {
+			t.Fatalf("expected the unknown outcome to be returned, got %v", err)
+		}
+	})
+}
@@ -373,7 +373,7 @@ func (p *ClientRoutesHandler) Initialize(s *Session) error {
 	p.startReadingEvents(connectionIDs)
 	err := p.updateHostPortMappingSync(updateTask{connectionIDs: connectionIDs})
 	if err != nil {
-		p.log.Printf("error updating host ports: %v\n", err)
+		slogger(p.log).Error("error updating host ports", logError(err))
 	}
 	return nil
 }
@@ -415,11 +415,11 @@ func (p *ClientRoutesHandler) startReadingEvents(connectionIDs []string) {
 			case *events.ClientRoutesChangedEvent:
 				if debug.Enabled {
 					if len(evt.ConnectionIDs) == 0 {
-						p.log.Printf("got CLIENT_ROUTES_CHANGE event with no connection IDs")
+						slogger(p.log).Debug("got CLIENT_ROUTES_CHANGE event with no connection IDs")
 						continue
 					}
 					if len(evt.HostIDs) == 0 {
-						p.log.Printf("got CLIENT_ROUTES_CHANGE event with no host IDs")
+						slogger(p.log).Debug("got CLIENT_ROUTES_CHANGE event with no host IDs")
 					}
 				}
 				filteredConnectionIDs := filterAllowedConnectionIDs(evt.ConnectionIDs, connectionIDs)
@@ -477,9 +477,7 @@ func (p *ClientRoutesHandler) startUpdateWorker() {
 			case task := <-p.updateTasks:
 				err := p.updateHostPortMapping(task)
 				if err != nil {
-					if debug.Enabled {
-						p.log.Printf("failed to update host port mapping: %v", err)
-					}
+					slogger(p.log).Debug("failed to update host port mapping", logError(err))
 				}
 				if task.result != nil {
 					task.result <- err
@@ -30,6 +30,7 @@ import (
 	"crypto/x509"
 	"errors"
 	"fmt"
+	"log/slog"
 	"net"
 	"os"
 	"strconv"
@@ -75,6 +76,11 @@ type ClusterConfig struct {
 	// Logger for this ClusterConfig.
 	// If not specified, defaults to the gocql.defaultLogger.
 	Logger StdLogger
+	// StructuredLogger, if not nil, receives the logs of the driver instead
+	// of Logger, with levels and the attributes named by the LogKey
+	// constants, such as the host and the error a record is about.
+	// Records that have no level are logged with the info level.
+	StructuredLogger *slog.Logger
 	// HostDialer will be used to establish all connections for this Cluster.
 	// Unlike Dialer, HostDialer is responsible for setting up the entire connection, including the TLS session.
 	// To support shard-aware port, HostDialer should implement ShardDialer.
@@ -92,6 +98,19 @@ type ClusterConfig struct {
 	// QueryObserver will set the provided query observer on all queries created from this session.
 	// Use it to collect metrics / stats from queries by providing an implementation of QueryObserver.
 	QueryObserver QueryObserver
+	// RequestTracer will trace all the requests of this session on the
+	// client side, such as with OpenTelemetry.
+	// Default: unset
+	RequestTracer RequestTracer
+	// Metrics enables the built-in metrics of the session, read with
+	// Session.Metrics and Session.MetricsHandler.
+	// Default: unset
+	Metrics *MetricsConfig
+	// Codecs are consulted before the built-in conversions when marshaling
+	// query values and unmarshaling results. Sessions copy the registry when
+	// they are created.
+	// Default: unset
+	Codecs *CodecRegistry
 	// AddressTranslator will translate addresses found on peer discovery and/or
 	// node change events.
 	AddressTranslator AddressTranslator
@@ -478,10 +497,14 @@ func NewCluster(hosts ...string) *ClusterConfig {
 }
 
 func (cfg *ClusterConfig) logger() StdLogger {
-	if cfg.Logger == nil {
-		return &defaultLogger{}
+	if cfg.StructuredLogger != nil {
+		return structuredLogger{logger: cfg.StructuredLogger}
+	}
+	logger := cfg.Logger
+	if logger == nil {
+		logger = &defaultLogger{}
 	}
-	return cfg.Logger
+	return textLogger{StdLogger: logger, logger: slogger(logger)}
 }
 
 // CreateSession initializes the cluster based on this config and returns a
@@ -715,7 +738,7 @@ func (cfg *ClusterConfig) Validate() error {
 		// skipping is both safe and the default — so say which case is actually risky.
 		// The protocol version is negotiated per connection, long after Validate runs,
 		// so this cannot be narrowed down here.
-		cfg.Logger.Println("warning: skipping result metadata can lead to unpredictable results if columns involved in a prepared query are altered, on connections that exchange no result metadata ID (protocol v4 or lower without the SCYLLA_USE_METADATA_ID extension).")
+		slogger(cfg.logger()).Warn("skipping result metadata can lead to unpredictable results if columns involved in a prepared query are altered, on connections that exchange no result metadata ID (protocol v4 or lower without the SCYLLA_USE_METADATA_ID extension).")
 	}
 
 	if cfg.SerialConsistency > 0 && !cfg.SerialConsistency.IsSerial() {
@@ -0,0 +1,93 @@
+package gocql
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Codec converts values of a Go type to and from the encoding of a CQL type.
+// It lets a session handle Go types that can not implement Marshaler and
+// Unmarshaler, such as types defined in third-party packages.
+type Codec struct {
+	// GoType is the Go type the codec handles. Values of pointer types are
+	// dereferenced before a codec is looked up, unless a codec is registered
+	// for the pointer type itself.
+	GoType reflect.Type
+	// CQLType is the CQL type the codec handles.
+	CQLType Type
+	// Marshal encodes value, which is of GoType, as info. It may be nil if
+	// the codec is only used for reading.
+	Marshal func(info TypeInfo, value any) ([]byte, error)
+	// Unmarshal decodes data of info into value, which is a pointer to
+	// GoType. data is nil if the CQL value is null. It may be nil if the
+	// codec is only used for writing.
+	Unmarshal func(info TypeInfo, data []byte, value any) error
+}
+
+type codecKey struct {
+	goType  reflect.Type
+	cqlType Type
+}
+
+// CodecRegistry holds the codecs of a session. Registered codecs are
+// consulted before the built-in conversions, both for top level values and
+// for elements of collections, tuples, user-defined types and vectors.
+//
+// A session copies the registry of its ClusterConfig when it is created, so
+// codecs registered afterwards do not affect existing sessions. Register must
+// not be called concurrently with creating a session from the registry.
+type CodecRegistry struct {
+	codecs map[codecKey]Codec
+}
+
+// NewCodecRegistry returns an empty CodecRegistry.
+func NewCodecRegistry() *CodecRegistry {
+	return &CodecRegistry{codecs: make(map[codecKey]Codec)}
+}
+
+// Register adds c to the registry. It returns an error if c is incomplete or
+// a codec is already registered for the same Go and CQL types.
+func (r *CodecRegistry) Register(c Codec) error {
+	if c.GoType == nil {
+		return fmt.Errorf("gocql: codec for %s has no Go type", c.CQLType)
+	}
+	if c.Marshal == nil && c.Unmarshal == nil {
+		return fmt.Errorf("gocql: codec for %s and %s has neither Marshal nor Unmarshal", c.GoType, c.CQLType)
+	}
+	key := codecKey{goType: c.GoType, cqlType: c.CQLType}
+	if _, ok := r.codecs[key]; ok {
+		return fmt.Errorf("gocql: codec for %s and %s already registered", c.GoType, c.CQLType)
+	}
+	if r.codecs == nil {
+		r.codecs = make(map[codecKey]Codec)
+	}
+	r.codecs[key] = c
+	return nil
+}
+
+// clone returns a copy of the registry, or nil if it holds no codecs, so
+// that sessions without codecs skip the lookups entirely.
+func (r *CodecRegistry) clone() *CodecRegistry {
+	if r == nil || len(r.codecs) == 0 {
+		return nil
+	}
+	c := &CodecRegistry{codecs: make(map[codecKey]Codec, len(r.codecs))}
+	for k, v := range r.codecs {
+		c.codecs[k] = v
+	}
+	return c
+}
+
+func (r *CodecRegistry) marshaler(goType reflect.Type, cqlType Type) func(TypeInfo, any) ([]byte, error) {
+	if r == nil {
+		return nil
+	}
+	return r.codecs[codecKey{goType: goType, cqlType: cqlType}].Marshal
+}
+
+func (r *CodecRegistry) unmarshaler(goType reflect.Type, cqlType Type) func(TypeInfo, []byte, any) error {
+	if r == nil {
+		return nil
+	}
+	return r.codecs[codecKey{goType: goType, cqlType: cqlType}].Unmarshal
+}
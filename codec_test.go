@@ -0,0 +1,253 @@
+//go:build unit
+// +build unit
+
+package gocql
+
+import (
+	"reflect"
+	"testing"
+)
+
+type money struct {
+	cents int64
+}
+
+func moneyCodec() Codec {
+	return Codec{
+		GoType:  reflect.TypeOf(money{}),
+		CQLType: TypeBigInt,
+		Marshal: func(info TypeInfo, value any) ([]byte, error) {
+			return Marshal(info, value.(money).cents)
+		},
+		Unmarshal: func(info TypeInfo, data []byte, value any) error {
+			var cents int64
+			if err := Unmarshal(info, data, &cents); err != nil {
+				return err
+			}
+			*value.(*money) = money{cents: cents}
+			return nil
+		},
+	}
+}
+
+func newMoneyCodecs(t *testing.T) *CodecRegistry {
+	t.Helper()
+	r := NewCodecRegistry()
+	if err := r.Register(moneyCodec()); err != nil {
+		t.Fatal(err)
+	}
+	return r.clone()
+}
+
+func TestCodecRegistryRegister(t *testing.T) {
+	r := NewCodecRegistry()
+	if err := r.Register(Codec{CQLType: TypeBigInt, Marshal: moneyCodec().Marshal}); err == nil {
+		t.Error("expected error for codec without Go type")
+	}
+	if err := r.Register(Codec{GoType: reflect.TypeOf(money{}), CQLType: TypeBigInt}); err == nil {
+		t.Error("expected error for codec without Marshal and Unmarshal")
+	}
+	if err := r.Register(moneyCodec()); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Register(moneyCodec()); err == nil {
+		t.Error("expected error for duplicate codec")
+	}
+	c := moneyCodec()
+	c.CQLType = TypeVarint
+	if err := r.Register(c); err != nil {
+		t.Errorf("codec for another CQL type: %v", err)
+	}
+}
+
+func TestCodecRegistryClone(t *testing.T) {
+	if NewCodecRegistry().clone() != nil {
+		t.Error("expected empty registry to clone to nil")
+	}
+
+	r := NewCodecRegistry()
+	if err := r.Register(moneyCodec()); err != nil {
+		t.Fatal(err)
+	}
+	c := r.clone()
+
+	text := moneyCodec()
+	text.CQLType = TypeText
+	if err := r.Register(text); err != nil {
+		t.Fatal(err)
+	}
+	if c.marshaler(reflect.TypeOf(money{}), TypeText) != nil {
+		t.Error("codec registered after clone leaked into the copy")
+	}
+	if c.marshaler(reflect.TypeOf(money{}), TypeBigInt) == nil {
+		t.Error("codec registered before clone is missing from the copy")
+	}
+}
+
+func TestCodecMarshal(t *testing.T) {
+	codecs := newMoneyCodecs(t)
+	bigint := NativeType{proto: protoVersion4, typ: TypeBigInt}
+	want, err := Marshal(bigint, int64(1234))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, value := range []any{money{cents: 1234}, &money{cents: 1234}} {
+		data, err := marshal(codecs, bigint, value)
+		if err != nil {
+			t.Fatalf("marshal %T: %v", value, err)
+		}
+		if !reflect.DeepEqual(data, want) {
+			t.Errorf("marshal %T: got %x, want %x", value, data, want)
+		}
+	}
+
+	var m money
+	if err := unmarshal(codecs, bigint, want, &m); err != nil {
+		t.Fatal(err)
+	}
+	if m.cents != 1234 {
+		t.Errorf("unmarshal: got %d cents, want 1234", m.cents)
+	}
+
+	var p *money
+	if err := unmarshal(codecs, bigint, want, &p); err != nil {
+		t.Fatal(err)
+	}
+	if p == nil || p.cents != 1234 {
+		t.Errorf("unmarshal into pointer: got %v", p)
+	}
+
+	if _, err := Marshal(bigint, money{cents: 1234}); err == nil {
+		t.Error("expected Marshal without codecs to fail")
+	}
+	if err := unmarshal(codecs, NativeType{proto: protoVersion4, typ: TypeInt}, want[4:], &m); err == nil {
+		t.Error("expected codec for bigint not to be used for int")
+	}
+}
+
+func TestCodecMarshalNested(t *testing.T) {
+	codecs := newMoneyCodecs(t)
+	bigint := NativeType{proto: protoVersion4, typ: TypeBigInt}
+	text := NativeType{proto: protoVersion4, typ: TypeText}
+
+	tests := []struct {
+		name  string
+		info  TypeInfo
+		value any
+		dest  any
+	}{
+		{
+			name:  "list",
+			info:  CollectionType{NativeType: NativeType{proto: protoVersion4, typ: TypeList}, Elem: bigint},
+			value: []money{{cents: 1}, {cents: 2}},
+			dest:  new([]money),
+		},
+		{
+			name:  "map",
+			info:  CollectionType{NativeType: NativeType{proto: protoVersion4, typ: TypeMap}, Key: text, Elem: bigint},
+			value: map[string]money{"a": {cents: 1}},
+			dest:  new(map[string]money),
+		},
+		{
+			name: "tuple",
+			info: TupleTypeInfo{NativeType: NativeType{proto: protoVersion4, typ: TypeTuple}, Elems: []TypeInfo{text, bigint}},
+			value: struct {
+				Name  string
+				Price money
+			}{"a", money{cents: 1}},
+			dest: new(struct {
+				Name  string
+				Price money
+			}),
+		},
+		{
+			name: "udt",
+			info: UDTTypeInfo{
+				NativeType: NativeType{proto: protoVersion4, typ: TypeUDT},
+				Name:       "item",
+				Elements:   []UDTField{{Name: "name", Type: text}, {Name: "price", Type: bigint}},
+			},
+			value: struct {
+				Name  string `cql:"name"`
+				Price money  `cql:"price"`
+			}{"a", money{cents: 1}},
+			dest: new(struct {
+				Name  string `cql:"name"`
+				Price money  `cql:"price"`
+			}),
+		},
+		{
+			name: "vector",
+			info: VectorType{
+				NativeType: NativeType{
+					proto:  protoVersion4,
+					typ:    TypeCustom,
+					custom: apacheCassandraTypePrefix + "VectorType(" + apacheCassandraTypePrefix + "LongType, 2)",
+				},
+				SubType:    bigint,
+				Dimensions: 2,
+			},
+			value: []money{{cents: 1}, {cents: 2}},
+			dest:  new([]money),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data, err := marshal(codecs, test.info, test.value)
+			if err != nil {
+				t.Fatalf("marshal: %v", err)
+			}
+			if err := unmarshal(codecs, test.info, data, test.dest); err != nil {
+				t.Fatalf("unmarshal: %v", err)
+			}
+			if got := reflect.ValueOf(test.dest).Elem().Interface(); !reflect.DeepEqual(got, test.value) {
+				t.Errorf("got %+v, want %+v", got, test.value)
+			}
+		})
+	}
+}
+
+func TestCodecVectorFastPath(t *testing.T) {
+	info := makeFloatVectorType(2, "2")
+	r := NewCodecRegistry()
+	err := r.Register(Codec{
+		GoType:  reflect.TypeOf(float32(0)),
+		CQLType: TypeFloat,
+		Marshal: func(info TypeInfo, value any) ([]byte, error) {
+			return Marshal(info, value.(float32)*2)
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := marshal(r.clone(), info, []float32{1, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []float32
+	if err := unmarshal(nil, info, data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if want := []float32{2, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestCreateRoutingKeyCodecs(t *testing.T) {
+	codecs := newMoneyCodecs(t)
+	info := &routingKeyInfo{
+		indexes: []int{0},
+		types:   []TypeInfo{NativeType{proto: protoVersion4, typ: TypeBigInt}},
+	}
+	key, err := createRoutingKey(codecs, info, []any{money{cents: 7}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := Marshal(info.types[0], int64(7))
+	if !reflect.DeepEqual(key, want) {
+		t.Errorf("got %x, want %x", key, want)
+	}
+}
@@ -32,6 +32,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net"
 	"strconv"
 	"strings"
@@ -342,6 +343,20 @@ func (c *Conn) getScyllaSupported() ScyllaConnectionFeatures {
 	return c.scyllaSupported
 }
 
+// slogger returns the structured logger of the connection, with the
+// attributes of its host and of its shard once it is known.
+func (c *Conn) slogger() *slog.Logger {
+	var attrs []any
+	if c.host != nil {
+		attrs = append(attrs, logHostID(c.host))
+	}
+	attrs = append(attrs, logAddress(c.addr))
+	if shard := attemptShard(c); shard >= 0 {
+		attrs = append(attrs, logShard(shard))
+	}
+	return slogger(c.logger).With(attrs...)
+}
+
 // connectShard establishes a connection to a shard.
 // If nrShards is zero, shard-aware dialing is disabled.
 // note: every connection needs to get `conn.finalizeConnection` called ont it when initialization process is done
@@ -655,12 +670,12 @@ func (s *startupCoordinator) options(ctx context.Context, startupCompleted *atom
 	}
 	// Keep raw supported multimap for debug purposes
 	s.conn.supported = v.Supported
-	s.conn.scyllaSupported = parseSupported(s.conn.supported, s.conn.logger)
+	s.conn.scyllaSupported = parseSupported(s.conn.supported, s.conn.slogger())
 	s.conn.recalculateSystemRequestTimeout()
 	if current := s.conn.host.ScyllaFeatures(); current != s.conn.scyllaSupported.ScyllaHostFeatures {
 		s.conn.host.setScyllaFeatures(s.conn.scyllaSupported.ScyllaHostFeatures)
 	}
-	s.conn.cqlProtoExts = parseCQLProtocolExtensions(s.conn.supported, s.conn.version, s.conn.logger)
+	s.conn.cqlProtoExts = parseCQLProtocolExtensions(s.conn.supported, s.conn.version, s.conn.slogger())
 
 	// initFramerCache must be called after startup(), because startup() may
 	// nil out c.compressor if the server does not support the requested
@@ -946,7 +961,7 @@ func (c *Conn) serve(ctx context.Context) {
 		// stream position would be unknown and continuing would mis-frame everything
 		// after it.
 		if errors.Is(err, ErrReadHeaderTimeout) {
-			c.logger.Print("gocql: read header timeout") // TODO: Provide more details from wrapped error?
+			c.slogger().Warn("read header timeout", logError(err))
 			err = nil
 			continue
 		}
@@ -1180,7 +1195,7 @@ func (c *Conn) processFrameSource(ctx context.Context, src frameSource) error {
 		if err != nil {
 			if head.Stream == -1 {
 				// Event frame parse errors should not close the connection.
-				c.logger.Printf("gocql: unable to parse event frame: %v\n", err)
+				c.slogger().Warn("unable to parse event frame", logError(err))
 				return nil
 			}
 			return err
@@ -1207,7 +1222,7 @@ func (c *Conn) processFrameSource(ctx context.Context, src frameSource) error {
 	delete(c.calls, head.Stream)
 	c.mu.Unlock()
 	if call == nil || !ok {
-		c.logger.Printf("gocql: received response for stream which has no handler: header=%v\n", head)
+		c.slogger().Warn("received response for stream which has no handler", slog.Any("header", head))
 		return c.discardFrame(r, head)
 	} else if head.Stream != call.streamID {
 		panic(fmt.Sprintf("call has incorrect streamID: got %d expected %d", call.streamID, head.Stream))
@@ -2354,6 +2369,9 @@ func (c *Conn) prepareStatement(ctx context.Context, stmt string, tracer Tracer,
 		cache.Add(cacheKey, flight)
 		return flight
 	})
+	if m := c.session.metrics; m != nil {
+		m.observePrepare(ok)
+	}
 
 	if !ok {
 		go func() {
@@ -2420,14 +2438,14 @@ func (c *Conn) prepareStatement(ctx context.Context, stmt string, tracer Tracer,
 	}
 }
 
-func marshalQueryValue(typ TypeInfo, value any, dst *queryValues) error {
+func marshalQueryValue(codecs *CodecRegistry, typ TypeInfo, value any, dst *queryValues) error {
 	if named, ok := value.(*namedValue); ok {
 		dst.name = named.name
 		value = named.value
 	}
 
 	if _, ok := value.(unsetColumn); !ok {
-		val, err := Marshal(typ, value)
+		val, err := marshal(codecs, typ, value)
 		if err != nil {
 			return err
 		}
@@ -2573,7 +2591,7 @@ func (c *Conn) executeQueryWithMetrics(ctx context.Context, qry *Query, metrics
 			v := &params.values[i]
 			value := values[i]
 			typ := info.request.columns[i].TypeInfo
-			if err := marshalQueryValue(typ, value, v); err != nil {
+			if err := marshalQueryValue(c.codecs(), typ, value, v); err != nil {
 				putQueryValues(params.values)
 				return &Iter{err: err}
 			}
@@ -2590,7 +2608,7 @@ func (c *Conn) executeQueryWithMetrics(ctx context.Context, qry *Query, metrics
 			preparedID:       info.id,
 			resultMetadataID: info.resultMetadataID,
 			params:           params,
-			customPayload:    qry.customPayload,
+			customPayload:    c.customPayload(ctx, qry.customPayload),
 		}
 
 		// Set "lwt", keyspace", "table" property in the query if it is present in preparedMetadata
@@ -2609,7 +2627,7 @@ func (c *Conn) executeQueryWithMetrics(ctx context.Context, qry *Query, metrics
 		frame = &writeQueryFrame{
 			statement:     qry.stmt,
 			params:        params,
-			customPayload: qry.customPayload,
+			customPayload: c.customPayload(ctx, qry.customPayload),
 		}
 	}
 
@@ -2726,6 +2744,7 @@ func (c *Conn) executeQueryWithMetrics(ctx context.Context, qry *Query, metrics
 			meta:    x.meta,
 			framer:  framer,
 			numRows: x.numRows,
+			codecs:  c.codecs(),
 		}).bindWarningHandlerWithMetrics(qry, metrics, warningHandler)
 
 		if x.meta.noMetaData() {
@@ -2754,7 +2773,7 @@ func (c *Conn) executeQueryWithMetrics(ctx context.Context, qry *Query, metrics
 			bindWarningHandlerWithMetrics(qry, metrics, warningHandler)
 		if err := c.awaitSchemaAgreement(ctx); err != nil {
 			// TODO: should have this behind a flag
-			c.logger.Println(err)
+			c.slogger().Warn("schema agreement failed", logError(err))
 		}
 		// dont return an error from this, might be a good idea to give a warning
 		// though. The impact of this returning an error would be that the cluster
@@ -2805,6 +2824,14 @@ func (c *Conn) AvailableStreams() int {
 	return c.streams.Available()
 }
 
+// codecs returns the codecs of the session owning the connection.
+func (c *Conn) codecs() *CodecRegistry {
+	if c.session == nil {
+		return nil
+	}
+	return c.session.codecs
+}
+
 func useKeyspaceStmt(keyspace string) string {
 	return `USE "` + strings.ReplaceAll(keyspace, `"`, `""`) + `"`
 }
@@ -2861,7 +2888,7 @@ func (c *Conn) executeBatch(ctx context.Context, batch *Batch) (iter *Iter) {
 		serialConsistency:     batch.serialCons,
 		defaultTimestamp:      batch.defaultTimestamp,
 		defaultTimestampValue: batch.defaultTimestampValue,
-		customPayload:         batch.CustomPayload,
+		customPayload:         c.customPayload(ctx, batch.CustomPayload),
 	}
 
 	// Always forward these to the framer regardless of protocol version. On
@@ -2920,7 +2947,7 @@ func (c *Conn) executeBatch(ctx context.Context, batch *Batch) (iter *Iter) {
 				v := &b.values[j]
 				value := values[j]
 				typ := info.request.columns[j].TypeInfo
-				if err := marshalQueryValue(typ, value, v); err != nil {
+				if err := marshalQueryValue(c.codecs(), typ, value, v); err != nil {
 					putBatchQueryValues(req.statements)
 					return &Iter{err: err}
 				}
@@ -2978,6 +3005,7 @@ func (c *Conn) executeBatch(ctx context.Context, batch *Batch) (iter *Iter) {
 			meta:    x.meta,
 			framer:  framer,
 			numRows: x.numRows,
+			codecs:  c.codecs(),
 		}).bindWarningHandler(batch, warningHandler)
 
 		return iter
@@ -3010,7 +3038,7 @@ func getSchemaAgreement(localSchemaVersion string, querySystemPeersRows []schema
 
 	for _, row := range querySystemPeersRows {
 		if !row.IsValid() {
-			logger.Printf("invalid peer or peer with empty schema_version: peer=%q", row)
+			slogger(logger).Warn("invalid peer or peer with empty schema_version", slog.Any("peer", row))
 			continue
 		}
 		versions[row.SchemaVersion.String()] = struct{}{}
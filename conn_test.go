@@ -2479,7 +2479,7 @@ func TestInitFramerCacheProtoV3IgnoresScyllaUseMetadataID(t *testing.T) {
 	t.Parallel()
 
 	c := &Conn{version: protoVersion3, logger: &testLogger{}}
-	c.cqlProtoExts = parseCQLProtocolExtensions(map[string][]string{scyllaUseMetadataID: {}}, c.version, c.logger)
+	c.cqlProtoExts = parseCQLProtocolExtensions(map[string][]string{scyllaUseMetadataID: {}}, c.version, c.slogger())
 	c.initFramerCache()
 
 	if findCQLProtoExtByName(c.cqlProtoExts, scyllaUseMetadataID) != nil {
@@ -26,12 +26,12 @@ package gocql
 
 import (
 	"fmt"
+	"log/slog"
 	"math/rand"
 	"net"
 	"sync"
 	"time"
 
-	"github.com/gocql/gocql/internal/debug"
 	"github.com/gocql/gocql/tablets"
 
 	"github.com/gocql/gocql/debounce"
@@ -259,6 +259,9 @@ func (p *policyConnPool) removeHost(hostID UUID) {
 	delete(p.hostConnPools, hostID)
 	p.mu.Unlock()
 
+	if p.session != nil && p.session.metrics != nil {
+		p.session.metrics.removeHost(hostID)
+	}
 	go pool.Close()
 }
 
@@ -267,6 +270,7 @@ func (p *policyConnPool) removeHost(hostID UUID) {
 type hostConnPool struct {
 	connPicker ConnPicker
 	logger     StdLogger
+	slog       *slog.Logger
 	session    *Session
 	host       *HostInfo
 	debouncer  *debounce.SimpleDebouncer
@@ -298,6 +302,7 @@ func newHostConnPool(session *Session, host *HostInfo, size int, keyspace string
 		logger:     session.logger,
 		debouncer:  debounce.NewSimpleDebouncer(),
 	}
+	pool.slog = pool.newSlogger()
 
 	// the pool is not filled or connected
 	return pool
@@ -343,6 +348,21 @@ func (pool *hostConnPool) PickConn(host SelectedHost, qry ExecutableQuery) *Conn
 	return pool.Pick(host.Token(), qry)
 }
 
+// shardOf returns the shard of the host owning token, or -1 if the host is
+// not sharded.
+func (pool *hostConnPool) shardOf(token int64Token) int {
+	pool.mu.RLock()
+	picker, ok := pool.connPicker.(*scyllaConnPicker)
+	pool.mu.RUnlock()
+	if !ok {
+		return -1
+	}
+
+	picker.mu.RLock()
+	defer picker.mu.RUnlock()
+	return picker.shardOf(token)
+}
+
 // pickable reports whether the pool is open and has (or is filling) connections.
 // Must be called with pool.mu held.
 func (pool *hostConnPool) pickable() bool {
@@ -381,6 +401,30 @@ func (pool *hostConnPool) InFlight() int {
 	return size
 }
 
+// AvailableStreams returns the number of streams available on the
+// connections of the pool, or 0 if its ConnPicker does not report them.
+func (pool *hostConnPool) AvailableStreams() int {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	if p, ok := pool.connPicker.(streamsConnPicker); ok {
+		return p.AvailableStreams()
+	}
+	return 0
+}
+
+// ShardConnections returns the number of connections of the pool to every
+// shard of its host, or nil if the host is not sharded.
+func (pool *hostConnPool) ShardConnections() []int {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	if p, ok := pool.connPicker.(shardsConnPicker); ok {
+		return p.ShardConnections()
+	}
+	return nil
+}
+
 // Close the connection pool
 func (pool *hostConnPool) Close() {
 	pool.mu.Lock()
@@ -470,25 +514,38 @@ func (pool *hostConnPool) fill_debounce() {
 	pool.debouncer.Debounce(pool.fill)
 }
 
+// slogger returns the structured logger of the pool, with the attributes of
+// its host and keyspace, built once by newHostConnPool.
+func (pool *hostConnPool) slogger() *slog.Logger {
+	if pool.slog != nil {
+		return pool.slog
+	}
+	return pool.newSlogger()
+}
+
+func (pool *hostConnPool) newSlogger() *slog.Logger {
+	logger := slogger(pool.logger).With(logHostID(pool.host), logAddress(pool.host.ConnectAddressAndPort()))
+	if pool.keyspace != "" {
+		logger = logger.With(logKeyspace(pool.keyspace))
+	}
+	return logger
+}
+
 func (pool *hostConnPool) logConnectErr(err error) {
 	if opErr, ok := err.(*net.OpError); ok && (opErr.Op == "dial" || opErr.Op == "read") {
 		// connection refused
 		// these are typical during a node outage so avoid log spam.
-		if debug.Enabled {
-			pool.logger.Printf("unable to dial %q: %v\n", pool.host, err)
-		}
+		pool.slogger().Debug("unable to dial host", logError(err))
 	} else if err != nil {
 		// unexpected error
-		pool.logger.Printf("error: failed to connect to %q due to error: %v", pool.host, err)
+		pool.slogger().Error("failed to connect to host", logError(err))
 	}
 }
 
 // transition back to a not-filling state.
 func (pool *hostConnPool) fillingStopped(err error) {
 	if err != nil {
-		if debug.Enabled {
-			pool.logger.Printf("gocql: filling stopped %q: %v\n", pool.host.ConnectAddress(), err)
-		}
+		pool.slogger().Debug("pool filling stopped", logError(err))
 		// wait for some time to avoid back-to-back filling
 		// this provides some time between failed attempts
 		// to fill the pool for the host to recover
@@ -504,9 +561,7 @@ func (pool *hostConnPool) fillingStopped(err error) {
 
 	// if we errored and the size is now zero, make sure the host is marked as down
 	// see https://github.com/apache/cassandra-gocql-driver/issues/1614
-	if debug.Enabled {
-		pool.logger.Printf("gocql: conns of pool after stopped %q: %v\n", host.ConnectAddress(), count)
-	}
+	pool.slogger().Debug("pool filling stopped", slog.Int("connections", count))
 	if err != nil && count == 0 {
 		if pool.session.cfg.ConvictionPolicy.AddFailure(err, host) {
 			pool.session.handleNodeDown(host.ConnectAddress(), port)
@@ -566,10 +621,8 @@ func (pool *hostConnPool) connect() (err error) {
 				break
 			}
 		}
-		if debug.Enabled {
-			pool.logger.Printf("gocql: connection failed %q: %v, reconnecting with %T\n",
-				pool.host.ConnectAddress(), err, reconnectionPolicy)
-		}
+		pool.slogger().Debug("connection failed, reconnecting", logError(err),
+			slog.String("reconnection_policy", fmt.Sprintf("%T", reconnectionPolicy)))
 		time.Sleep(reconnectionPolicy.GetInterval(i))
 	}
 
@@ -598,9 +651,7 @@ func (pool *hostConnPool) connect() (err error) {
 	if err := pool.connPicker.Put(conn); err != nil {
 		pool.mu.Unlock()
 		conn.Close()
-		if debug.Enabled {
-			pool.logger.Printf("gocql: pool connection was not added to the pool: %w", err)
-		}
+		pool.slogger().Debug("connection was not added to the pool", logError(err))
 		return nil
 	}
 	pool.mu.Unlock()
@@ -640,9 +691,7 @@ func (pool *hostConnPool) HandleError(conn *Conn, err error, closed bool) {
 		return
 	}
 
-	if debug.Enabled {
-		pool.logger.Printf("gocql: pool connection error %q: %v\n", conn.addr, err)
-	}
+	pool.slogger().Debug("pool connection error", logShard(attemptShard(conn)), logError(err))
 
 	pool.connPicker.Remove(conn)
 	go pool.fill_debounce()
@@ -31,6 +31,18 @@ type int64ConnPicker interface {
 	PickInt64(int64, ExecutableQuery) *Conn
 }
 
+// streamsConnPicker is an optional ConnPicker method reporting the number of
+// streams available on the connections of the pool, for Session.Metrics.
+type streamsConnPicker interface {
+	AvailableStreams() int
+}
+
+// shardsConnPicker is an optional ConnPicker method reporting the number of
+// connections to every shard of the host, for Session.Diagnostics.
+type shardsConnPicker interface {
+	ShardConnections() []int
+}
+
 type defaultConnPicker struct {
 	conns []*Conn
 	pos   uint32
@@ -95,6 +107,16 @@ func (p *defaultConnPicker) InFlight() int {
 	return size
 }
 
+func (p *defaultConnPicker) AvailableStreams() int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	result := 0
+	for _, conn := range p.conns {
+		result += conn.AvailableStreams()
+	}
+	return result
+}
+
 func (p *defaultConnPicker) Size() (int, int) {
 	p.mu.RLock()
 	size := len(p.conns)
@@ -29,6 +29,7 @@ import (
 	crand "crypto/rand"
 	"errors"
 	"fmt"
+	"log/slog"
 	"math/rand"
 	"net"
 	"regexp"
@@ -38,7 +39,6 @@ import (
 	"time"
 
 	"github.com/gocql/gocql/events"
-	"github.com/gocql/gocql/internal/debug"
 	frm "github.com/gocql/gocql/internal/frame"
 )
 
@@ -300,14 +300,16 @@ func (c *controlConn) connect(hosts []*HostInfo) error {
 		conn, err = c.session.dial(c.session.ctx, host, cfg, c)
 		// conn.finalizeConnection() to be called outside of this function, since initialization process is not completed yet
 		if err != nil {
-			c.session.logger.Printf("gocql: unable to dial control conn %v:%v: %v\n", host.ConnectAddress(), host.Port(), err)
+			slogger(c.session.logger).Warn("unable to dial control connection",
+				logHostID(host), logAddress(host.ConnectAddressAndPort()), logError(err))
 			continue
 		}
 		err = c.setupConn(conn)
 		if err == nil {
 			break
 		}
-		c.session.logger.Printf("gocql: unable setup control conn %v:%v: %v\n", host.ConnectAddress(), host.Port(), err)
+		slogger(c.session.logger).Warn("unable to set up control connection",
+			logHostID(host), logAddress(host.ConnectAddressAndPort()), logError(err))
 		conn.Close()
 		conn = nil
 	}
@@ -425,19 +427,18 @@ func (c *controlConn) reconnect() error {
 
 	err := c.attemptReconnect()
 	if err != nil {
-		err = fmt.Errorf("gocql: unable to reconnect control connection: %w\n", err)
-		c.session.logger.Printf(err.Error())
-		return err
+		slogger(c.session.logger).Error("unable to reconnect control connection", logError(err))
+		return fmt.Errorf("gocql: unable to reconnect control connection: %w", err)
 	}
 
 	err = c.session.refreshRingNow()
 	if err != nil {
-		c.session.logger.Printf("gocql: unable to refresh ring: %v\n", err)
+		slogger(c.session.logger).Error("unable to refresh ring", logError(err))
 	}
 
 	err = c.session.metadataDescriber.refreshAllSchema()
 	if err != nil {
-		c.session.logger.Printf("gocql: unable to refresh the schema: %v\n", err)
+		slogger(c.session.logger).Error("unable to refresh the schema", logError(err))
 	}
 	return nil
 }
@@ -464,8 +465,8 @@ func (c *controlConn) attemptReconnect() error {
 		return nil
 	}
 
-	c.session.logger.Printf("gocql: unable to connect to any ring node: %v\n", err)
-	c.session.logger.Printf("gocql: control falling back to initial contact points.\n")
+	slogger(c.session.logger).Warn("unable to connect to any ring node, control connection falling back to initial contact points",
+		logError(err))
 	// Fallback to initial contact points, as it may be the case that all known initialHosts
 	// changed their IPs while keeping the same hostname(s).
 	initialHosts, resolvErr := resolveInitialEndpoints(c.session.cfg.DNSResolver, c.session.cfg.Hosts, c.session.cfg.Port, c.session.logger)
@@ -484,12 +485,14 @@ func (c *controlConn) attemptReconnectToAnyOfHosts(hosts []*HostInfo) error {
 			if c.session.cfg.ConvictionPolicy.AddFailure(err, host) {
 				c.session.handleNodeDown(host.ConnectAddress(), host.Port())
 			}
-			c.session.logger.Printf("gocql: unable to dial control conn %v:%v: %v\n", host.ConnectAddress(), host.Port(), err)
+			slogger(c.session.logger).Warn("unable to dial control connection",
+				logHostID(host), logAddress(host.ConnectAddressAndPort()), logError(err))
 			continue
 		}
 		err = c.setupConn(conn)
 		if err != nil {
-			c.session.logger.Printf("gocql: unable setup control conn %v:%v: %v\n", host.ConnectAddress(), host.Port(), err)
+			slogger(c.session.logger).Warn("unable to set up control connection",
+				logHostID(host), logAddress(host.ConnectAddressAndPort()), logError(err))
 			conn.Close()
 			continue
 		}
@@ -573,8 +576,9 @@ func (c *controlConn) runQuery(qry *Query) (iter *Iter) {
 		qry.conn = ch.conn
 		iter = ch.conn.executeQuery(context.TODO(), qry)
 
-		if debug.Enabled && iter.err != nil {
-			c.session.logger.Printf("control: error executing %q: %v\n", qry.stmt, iter.err)
+		if iter.err != nil {
+			slogger(c.session.logger).Debug("control connection query failed",
+				slog.String("statement", qry.stmt), logHostID(ch.host), logError(iter.err))
 		}
 
 		qry.AddAttempts(1, ch.host)
@@ -0,0 +1,572 @@
+package gocql
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math"
+	"math/big"
+	"net"
+	"net/netip"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/inf.v0"
+
+	"github.com/gocql/gocql/internal/cqlident"
+)
+
+// FormatCQLLiteral returns value, of the CQL type info, as a CQL literal such
+// as {'a': [1, 2]}, 0xdeadbeef or '2024-01-01'. value is converted with the
+// same rules as Marshal, so any value Marshal accepts for info can be
+// formatted, and nil, null and unset values are formatted as null.
+//
+// Entries of maps and sets are sorted by their literal so that formatting is
+// deterministic.
+func FormatCQLLiteral(info TypeInfo, value any) (string, error) {
+	data, err := Marshal(info, value)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	if err := writeCQLLiteral(&b, info, data); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// cqlTimestampLayout is accepted by CQL for timestamp literals.
+const cqlTimestampLayout = "2006-01-02 15:04:05.000-0700"
+
+// writeCQLLiteral writes the literal of data, the serialized value of info.
+func writeCQLLiteral(b *strings.Builder, info TypeInfo, data []byte) error {
+	if data == nil {
+		b.WriteString("null")
+		return nil
+	}
+
+	switch info.Type() {
+	case TypeVarchar, TypeText, TypeAscii:
+		writeCQLString(b, string(data))
+	case TypeBlob:
+		writeCQLBlob(b, data)
+	case TypeBoolean:
+		var v bool
+		if err := Unmarshal(info, data, &v); err != nil {
+			return err
+		}
+		b.WriteString(strconv.FormatBool(v))
+	case TypeTinyInt, TypeSmallInt, TypeInt, TypeBigInt, TypeCounter:
+		var v int64
+		if err := Unmarshal(info, data, &v); err != nil {
+			return err
+		}
+		b.WriteString(strconv.FormatInt(v, 10))
+	case TypeVarint:
+		var v big.Int
+		if err := Unmarshal(info, data, &v); err != nil {
+			return err
+		}
+		b.WriteString(v.String())
+	case TypeFloat:
+		var v float32
+		if err := Unmarshal(info, data, &v); err != nil {
+			return err
+		}
+		writeCQLFloat(b, float64(v), 32)
+	case TypeDouble:
+		var v float64
+		if err := Unmarshal(info, data, &v); err != nil {
+			return err
+		}
+		writeCQLFloat(b, v, 64)
+	case TypeDecimal:
+		var v inf.Dec
+		if err := Unmarshal(info, data, &v); err != nil {
+			return err
+		}
+		b.WriteString(v.String())
+	case TypeUUID, TypeTimeUUID:
+		var v UUID
+		if err := Unmarshal(info, data, &v); err != nil {
+			return err
+		}
+		b.WriteString(v.String())
+	case TypeInet:
+		var v netip.Addr
+		if err := Unmarshal(info, data, &v); err != nil {
+			return err
+		}
+		writeCQLString(b, v.String())
+	case TypeTimestamp:
+		var v time.Time
+		if err := Unmarshal(info, data, &v); err != nil {
+			return err
+		}
+		writeCQLString(b, v.UTC().Format(cqlTimestampLayout))
+	case TypeDate:
+		var v time.Time
+		if err := Unmarshal(info, data, &v); err != nil {
+			return err
+		}
+		writeCQLString(b, v.UTC().Format("2006-01-02"))
+	case TypeTime:
+		var v time.Duration
+		if err := Unmarshal(info, data, &v); err != nil {
+			return err
+		}
+		writeCQLString(b, fmt.Sprintf("%02d:%02d:%02d.%09d",
+			v/time.Hour, v%time.Hour/time.Minute, v%time.Minute/time.Second, v%time.Second))
+	case TypeDuration:
+		var v Duration
+		if err := Unmarshal(info, data, &v); err != nil {
+			return err
+		}
+		writeCQLDuration(b, v)
+	case TypeList, TypeSet, TypeMap:
+		collection, ok := info.(CollectionType)
+		if !ok {
+			return marshalErrorf("can not format %s: not a collection type", info)
+		}
+		return writeCQLCollection(b, collection, data)
+	case TypeTuple:
+		tuple, ok := info.(TupleTypeInfo)
+		if !ok {
+			return marshalErrorf("can not format %s: not a tuple type", info)
+		}
+		b.WriteByte('(')
+		for i, elem := range tuple.Elems {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			var p []byte
+			if len(data) > 0 {
+				var err error
+				if p, data, err = readCQLLiteralBytes(data); err != nil {
+					return marshalErrorf("can not format %s: %v", info, err)
+				}
+			}
+			if err := writeCQLLiteral(b, elem, p); err != nil {
+				return err
+			}
+		}
+		b.WriteByte(')')
+	case TypeUDT:
+		udt, ok := info.(UDTTypeInfo)
+		if !ok {
+			return marshalErrorf("can not format %s: not a user-defined type", info)
+		}
+		b.WriteByte('{')
+		for i, field := range udt.Elements {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			var p []byte
+			if len(data) > 0 {
+				var err error
+				if p, data, err = readCQLLiteralBytes(data); err != nil {
+					return marshalErrorf("can not format %s: %v", info, err)
+				}
+			}
+			b.WriteString(cqlident.Quote(field.Name))
+			b.WriteString(": ")
+			if err := writeCQLLiteral(b, field.Type, p); err != nil {
+				return err
+			}
+		}
+		b.WriteByte('}')
+	case TypeCustom:
+		vector, ok := info.(VectorType)
+		if !ok {
+			return marshalErrorf("can not format custom type %s", info.Custom())
+		}
+		return writeCQLVector(b, vector, data)
+	default:
+		return marshalErrorf("can not format %s", info)
+	}
+	return nil
+}
+
+func writeCQLString(b *strings.Builder, s string) {
+	b.WriteByte('\'')
+	b.WriteString(strings.ReplaceAll(s, "'", "''"))
+	b.WriteByte('\'')
+}
+
+func writeCQLBlob(b *strings.Builder, data []byte) {
+	b.WriteString("0x")
+	b.WriteString(hex.EncodeToString(data))
+}
+
+func writeCQLFloat(b *strings.Builder, v float64, bitSize int) {
+	switch {
+	case math.IsNaN(v):
+		b.WriteString("NaN")
+	case math.IsInf(v, 1):
+		b.WriteString("Infinity")
+	case math.IsInf(v, -1):
+		b.WriteString("-Infinity")
+	default:
+		b.WriteString(strconv.FormatFloat(v, 'g', -1, bitSize))
+	}
+}
+
+// writeCQLDuration writes d in the unit format of CQL, such as 1mo2d3h4m.
+func writeCQLDuration(b *strings.Builder, d Duration) {
+	if d.Months < 0 || d.Days < 0 || d.Nanoseconds < 0 {
+		b.WriteByte('-')
+	}
+	months, days := int64(d.Months), int64(d.Days)
+	if months < 0 {
+		months = -months
+	}
+	if days < 0 {
+		days = -days
+	}
+	nanos := uint64(d.Nanoseconds)
+	if d.Nanoseconds < 0 {
+		nanos = -nanos
+	}
+	if months == 0 && days == 0 && nanos == 0 {
+		b.WriteString("0s")
+		return
+	}
+	unit := func(v uint64, suffix string) {
+		if v != 0 {
+			b.WriteString(strconv.FormatUint(v, 10))
+			b.WriteString(suffix)
+		}
+	}
+	unit(uint64(months/12), "y")
+	unit(uint64(months%12), "mo")
+	unit(uint64(days), "d")
+	unit(nanos/uint64(time.Hour), "h")
+	unit(nanos%uint64(time.Hour)/uint64(time.Minute), "m")
+	unit(nanos%uint64(time.Minute)/uint64(time.Second), "s")
+	unit(nanos%uint64(time.Second)/uint64(time.Millisecond), "ms")
+	unit(nanos%uint64(time.Millisecond)/uint64(time.Microsecond), "us")
+	unit(nanos%uint64(time.Microsecond), "ns")
+}
+
+// readCQLLiteralBytes reads a [bytes] value, like readBytes, checking its
+// length.
+func readCQLLiteralBytes(data []byte) (p, rest []byte, err error) {
+	if len(data) < 4 {
+		return nil, nil, fmt.Errorf("unexpected eof")
+	}
+	size := readInt(data)
+	data = data[4:]
+	if size < 0 {
+		return nil, data, nil
+	}
+	if int(size) > len(data) {
+		return nil, nil, fmt.Errorf("unexpected eof")
+	}
+	return data[:size], data[size:], nil
+}
+
+func writeCQLCollection(b *strings.Builder, info CollectionType, data []byte) error {
+	n, p, err := readCollectionSize(info, data)
+	if err != nil {
+		return err
+	}
+	if n < 0 || n > len(data)/4 {
+		return marshalErrorf("can not format %s: invalid size %d", info, n)
+	}
+	data = data[p:]
+
+	elems := make([]string, n)
+	for i := range elems {
+		var eb strings.Builder
+		if info.Type() == TypeMap {
+			var key []byte
+			if key, data, err = readCQLLiteralBytes(data); err != nil {
+				return marshalErrorf("can not format %s: %v", info, err)
+			}
+			if err := writeCQLLiteral(&eb, info.Key, key); err != nil {
+				return err
+			}
+			eb.WriteString(": ")
+		}
+		var elem []byte
+		if elem, data, err = readCQLLiteralBytes(data); err != nil {
+			return marshalErrorf("can not format %s: %v", info, err)
+		}
+		if err := writeCQLLiteral(&eb, info.Elem, elem); err != nil {
+			return err
+		}
+		elems[i] = eb.String()
+	}
+
+	if info.Type() == TypeList {
+		b.WriteByte('[')
+	} else {
+		slices.Sort(elems)
+		b.WriteByte('{')
+	}
+	b.WriteString(strings.Join(elems, ", "))
+	if info.Type() == TypeList {
+		b.WriteByte(']')
+	} else {
+		b.WriteByte('}')
+	}
+	return nil
+}
+
+func writeCQLVector(b *strings.Builder, info VectorType, data []byte) error {
+	variable := isVectorVariableLengthType(info.SubType)
+	size := vectorFixedElemSize(info.SubType)
+	b.WriteByte('[')
+	for i := 0; i < info.Dimensions; i++ {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		if variable {
+			m, p, err := readUnsignedVInt(data)
+			if err != nil {
+				return marshalErrorf("can not format %s: %v", info, err)
+			}
+			data = data[p:]
+			size = int(m)
+		}
+		if size > len(data) {
+			return marshalErrorf("can not format %s: unexpected eof", info)
+		}
+		if err := writeCQLLiteral(b, info.SubType, data[:size]); err != nil {
+			return err
+		}
+		data = data[size:]
+	}
+	b.WriteByte(']')
+	return nil
+}
+
+// literalTypeInfo infers the CQL type of the Go type t, for formatting values
+// bound without a prepared statement. It reports false if there is none.
+func literalTypeInfo(proto byte, t reflect.Type) (TypeInfo, bool) {
+	native := func(typ Type) (TypeInfo, bool) {
+		return NewNativeType(proto, typ), true
+	}
+	switch t {
+	case reflect.TypeFor[time.Time]():
+		return native(TypeTimestamp)
+	case reflect.TypeFor[time.Duration](), reflect.TypeFor[Duration]():
+		return native(TypeDuration)
+	case reflect.TypeFor[UUID]():
+		return native(TypeUUID)
+	case reflect.TypeFor[net.IP](), reflect.TypeFor[netip.Addr](), reflect.TypeFor[netip.AddrPort]():
+		return native(TypeInet)
+	case reflect.TypeFor[*big.Int]():
+		return native(TypeVarint)
+	case reflect.TypeFor[*inf.Dec]():
+		return native(TypeDecimal)
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return native(TypeVarchar)
+	case reflect.Bool:
+		return native(TypeBoolean)
+	case reflect.Int8:
+		return native(TypeTinyInt)
+	case reflect.Int16:
+		return native(TypeSmallInt)
+	case reflect.Int32:
+		return native(TypeInt)
+	case reflect.Int, reflect.Int64:
+		return native(TypeBigInt)
+	case reflect.Float32:
+		return native(TypeFloat)
+	case reflect.Float64:
+		return native(TypeDouble)
+	case reflect.Ptr:
+		return literalTypeInfo(proto, t.Elem())
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return native(TypeBlob)
+		}
+		elem, ok := literalTypeInfo(proto, t.Elem())
+		if !ok {
+			return nil, false
+		}
+		return CollectionType{NativeType: NewNativeType(proto, TypeList), Elem: elem}, true
+	case reflect.Map:
+		key, ok := literalTypeInfo(proto, t.Key())
+		if !ok {
+			return nil, false
+		}
+		if t.Elem().Kind() == reflect.Struct && t.Elem().NumField() == 0 {
+			return CollectionType{NativeType: NewNativeType(proto, TypeSet), Elem: key}, true
+		}
+		elem, ok := literalTypeInfo(proto, t.Elem())
+		if !ok {
+			return nil, false
+		}
+		return CollectionType{NativeType: NewNativeType(proto, TypeMap), Key: key, Elem: elem}, true
+	}
+	return nil, false
+}
+
+// DebugString returns the statement of the query with its bound values
+// inlined as CQL literals, for logging and debugging. The result is not meant
+// to be executed.
+//
+// Values of queries bound from a PreparedStatement are formatted according to
+// the types of the bind markers, other values according to their Go types.
+// Values that can not be formatted are written as <T>, their Go type. Values
+// bound with Query.Bind are not included.
+func (q *Query) DebugString() string {
+	return q.DebugStringRedacted(nil)
+}
+
+// DebugStringRedacted is like DebugString but writes <redacted> in place of
+// the values for which redact returns true. redact is called with the index
+// of the value and its bind marker, of which only the name is set when the
+// query was not bound from a PreparedStatement, and only for named values.
+func (q *Query) DebugStringRedacted(redact func(i int, marker ColumnInfo) bool) string {
+	proto := byte(protoVersion4)
+	if q.session != nil && q.session.cfg.ProtoVersion != 0 {
+		proto = byte(q.session.cfg.ProtoVersion)
+	}
+	return debugStatement(proto, q.stmt, q.values, q.bindMarkers, redact)
+}
+
+// debugStatement returns stmt with values inlined, see
+// Query.DebugStringRedacted. markers are the bind markers of values, if
+// known.
+func debugStatement(proto byte, stmt string, values []any, markers []ColumnInfo, redact func(i int, marker ColumnInfo) bool) string {
+	var positional []string
+	named := make(map[string]string)
+	for i, value := range values {
+		var marker ColumnInfo
+		if nv, ok := value.(*namedValue); ok {
+			value = nv.value
+			marker.Name = nv.name
+			for _, m := range markers {
+				if m.Name == nv.name {
+					marker = m
+					break
+				}
+			}
+		} else if i < len(markers) {
+			marker = markers[i]
+		}
+		literal := "<redacted>"
+		if redact == nil || !redact(i, marker) {
+			literal = debugLiteral(proto, marker.TypeInfo, value)
+		}
+		if nv, ok := values[i].(*namedValue); ok {
+			named[nv.name] = literal
+		} else {
+			positional = append(positional, literal)
+		}
+	}
+	return inlineBindMarkers(stmt, positional, named)
+}
+
+func debugLiteral(proto byte, info TypeInfo, value any) string {
+	if value == nil {
+		return "null"
+	}
+	if _, ok := value.(unsetColumn); ok {
+		return "<unset>"
+	}
+	if info == nil {
+		var ok bool
+		if info, ok = literalTypeInfo(proto, reflect.TypeOf(value)); !ok {
+			return fmt.Sprintf("<%T>", value)
+		}
+	}
+	s, err := FormatCQLLiteral(info, value)
+	if err != nil {
+		return fmt.Sprintf("<%T>", value)
+	}
+	return s
+}
+
+// inlineBindMarkers replaces the positional bind markers of stmt with
+// positional, in order, and named ones with named, leaving markers without a
+// value as they are. String literals, quoted identifiers and comments are
+// copied verbatim.
+func inlineBindMarkers(stmt string, positional []string, named map[string]string) string {
+	var b strings.Builder
+	next := 0
+	for i := 0; i < len(stmt); {
+		c := stmt[i]
+		switch {
+		case c == '\'' || c == '"':
+			end := i + 1
+			for end < len(stmt) {
+				if stmt[end] == c {
+					if end+1 < len(stmt) && stmt[end+1] == c {
+						end += 2
+						continue
+					}
+					end++
+					break
+				}
+				end++
+			}
+			b.WriteString(stmt[i:end])
+			i = end
+		case strings.HasPrefix(stmt[i:], "$$"):
+			end := strings.Index(stmt[i+2:], "$$")
+			if end < 0 {
+				end = len(stmt)
+			} else {
+				end += i + 4
+			}
+			b.WriteString(stmt[i:end])
+			i = end
+		case strings.HasPrefix(stmt[i:], "--"), strings.HasPrefix(stmt[i:], "//"):
+			end := strings.IndexByte(stmt[i:], '\n')
+			if end < 0 {
+				end = len(stmt)
+			} else {
+				end += i
+			}
+			b.WriteString(stmt[i:end])
+			i = end
+		case strings.HasPrefix(stmt[i:], "/*"):
+			end := strings.Index(stmt[i+2:], "*/")
+			if end < 0 {
+				end = len(stmt)
+			} else {
+				end += i + 4
+			}
+			b.WriteString(stmt[i:end])
+			i = end
+		case c == '?':
+			if next < len(positional) {
+				b.WriteString(positional[next])
+			} else {
+				b.WriteByte(c)
+			}
+			next++
+			i++
+		case c == ':' && i+1 < len(stmt) && isCQLIdentifierByte(stmt[i+1]) && (i == 0 || !isCQLIdentifierByte(stmt[i-1])):
+			end := i + 1
+			for end < len(stmt) && isCQLIdentifierByte(stmt[end]) {
+				end++
+			}
+			if literal, ok := named[strings.ToLower(stmt[i+1:end])]; ok {
+				b.WriteString(literal)
+			} else if literal, ok := named[stmt[i+1:end]]; ok {
+				b.WriteString(literal)
+			} else {
+				b.WriteString(stmt[i:end])
+			}
+			i = end
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+	return b.String()
+}
+
+func isCQLIdentifierByte(c byte) bool {
+	return c == '_' || '0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
@@ -0,0 +1,154 @@
+//go:build unit
+// +build unit
+
+package gocql
+
+import (
+	"math"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"gopkg.in/inf.v0"
+)
+
+func TestFormatCQLLiteral(t *testing.T) {
+	const proto = protoVersion4
+	native := func(typ Type) NativeType { return NewNativeType(proto, typ) }
+	list := func(elem TypeInfo) CollectionType {
+		return CollectionType{NativeType: native(TypeList), Elem: elem}
+	}
+
+	tests := []struct {
+		info  TypeInfo
+		value any
+		want  string
+	}{
+		{native(TypeVarchar), "it's", `'it''s'`},
+		{native(TypeAscii), "", `''`},
+		{native(TypeBlob), []byte{0xde, 0xad, 0xbe, 0xef}, "0xdeadbeef"},
+		{native(TypeBlob), []byte{}, "0x"},
+		{native(TypeBoolean), true, "true"},
+		{native(TypeTinyInt), int8(-1), "-1"},
+		{native(TypeSmallInt), 300, "300"},
+		{native(TypeInt), int32(math.MinInt32), "-2147483648"},
+		{native(TypeBigInt), int64(1) << 40, "1099511627776"},
+		{native(TypeCounter), 7, "7"},
+		{native(TypeVarint), new(big.Int).Lsh(big.NewInt(1), 70), "1180591620717411303424"},
+		{native(TypeFloat), float32(1.5), "1.5"},
+		{native(TypeDouble), 0.1, "0.1"},
+		{native(TypeDouble), math.NaN(), "NaN"},
+		{native(TypeDouble), math.Inf(-1), "-Infinity"},
+		{native(TypeDecimal), inf.NewDec(1234, 2), "12.34"},
+		{native(TypeUUID), ParseUUIDMust("6ba7b810-9dad-11d1-80b4-00c04fd430c8"), "6ba7b810-9dad-11d1-80b4-00c04fd430c8"},
+		{native(TypeInet), net.ParseIP("::ffff:10.0.0.1"), "'10.0.0.1'"},
+		{native(TypeInet), "2001:db8::1", "'2001:db8::1'"},
+		{native(TypeTimestamp), time.Date(2024, 1, 2, 3, 4, 5, 6e6, time.FixedZone("", 3600)), "'2024-01-02 02:04:05.006+0000'"},
+		{native(TypeDate), time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), "'2024-01-01'"},
+		{native(TypeTime), 13*time.Hour + 30*time.Minute + 54*time.Second + 234*time.Millisecond, "'13:30:54.234000000'"},
+		{native(TypeDuration), Duration{Months: 14, Days: 2, Nanoseconds: int64(time.Hour + 1500*time.Microsecond + 3)}, "1y2mo2d1h1ms500us3ns"},
+		{native(TypeDuration), Duration{Nanoseconds: -int64(90 * time.Second)}, "-1m30s"},
+		{native(TypeDuration), Duration{}, "0s"},
+		{native(TypeInt), nil, "null"},
+		{native(TypeInt), UnsetValue, "null"},
+		{list(native(TypeInt)), []int{3, 1, 2}, "[3, 1, 2]"},
+		{list(native(TypeInt)), []int{}, "[]"},
+		{CollectionType{NativeType: native(TypeSet), Elem: native(TypeVarchar)}, []string{"b", "a"}, "{'a', 'b'}"},
+		{
+			CollectionType{NativeType: native(TypeMap), Key: native(TypeVarchar), Elem: list(native(TypeInt))},
+			map[string][]int{"b": {3}, "a": {1, 2}},
+			"{'a': [1, 2], 'b': [3]}",
+		},
+		{
+			TupleTypeInfo{NativeType: native(TypeTuple), Elems: []TypeInfo{native(TypeInt), native(TypeVarchar)}},
+			[]any{1, nil},
+			"(1, null)",
+		},
+		{
+			UDTTypeInfo{
+				NativeType: native(TypeUDT),
+				KeySpace:   "ks",
+				Name:       "point",
+				Elements: []UDTField{
+					{Name: "x", Type: native(TypeInt)},
+					{Name: "Label", Type: native(TypeVarchar)},
+				},
+			},
+			map[string]any{"x": 1, "Label": "a"},
+			`{"x": 1, "Label": 'a'}`,
+		},
+		{makeFloatVectorType(3, "3"), []float32{1, 2.5, -3}, "[1, 2.5, -3]"},
+		{
+			VectorType{
+				NativeType: NewCustomType(proto, TypeCustom, apacheCassandraTypePrefix+"VectorType"),
+				SubType:    native(TypeVarchar),
+				Dimensions: 2,
+			},
+			[]string{"a", "bc"},
+			"['a', 'bc']",
+		},
+	}
+	for _, test := range tests {
+		got, err := FormatCQLLiteral(test.info, test.value)
+		if err != nil {
+			t.Errorf("%s %v: %v", test.info, test.value, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("%s %v: got %s, want %s", test.info, test.value, got, test.want)
+		}
+	}
+
+	if _, err := FormatCQLLiteral(native(TypeInt), "x"); err == nil {
+		t.Error("expected error formatting a value Marshal rejects")
+	}
+	if _, err := FormatCQLLiteral(NewCustomType(proto, TypeCustom, "com.example.Custom"), []byte{1}); err == nil {
+		t.Error("expected error formatting a custom type")
+	}
+}
+
+func TestQueryDebugString(t *testing.T) {
+	q := &Query{
+		stmt: `INSERT INTO ks.t (id, "a?b", name, tags) VALUES (?, '?', ?, ?) -- ?`,
+		values: []any{
+			42,
+			"it's",
+			[]string{"x"},
+		},
+	}
+	want := `INSERT INTO ks.t (id, "a?b", name, tags) VALUES (42, '?', 'it''s', ['x']) -- ?`
+	if got := q.DebugString(); got != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+
+	want = `INSERT INTO ks.t (id, "a?b", name, tags) VALUES (42, '?', <redacted>, ['x']) -- ?`
+	got := q.DebugStringRedacted(func(i int, _ ColumnInfo) bool { return i == 1 })
+	if got != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+
+	q = &Query{
+		stmt:   "UPDATE t SET ts = :ts, v = :v WHERE id = :id AND x = ?",
+		values: []any{NamedValue("id", UUID{}), NamedValue("v", struct{}{}), NamedValue("ts", UnsetValue)},
+	}
+	want = "UPDATE t SET ts = <unset>, v = <struct {}> WHERE id = 00000000-0000-0000-0000-000000000000 AND x = ?"
+	if got := q.DebugString(); got != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+
+	// Prepared bind markers take precedence over the Go types of the values.
+	q = &Query{
+		stmt:   "SELECT * FROM t WHERE d = ? AND secret = ?",
+		values: []any{time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), "s"},
+		bindMarkers: []ColumnInfo{
+			{Name: "d", TypeInfo: NewNativeType(protoVersion4, TypeDate)},
+			{Name: "secret", TypeInfo: NewNativeType(protoVersion4, TypeBlob)},
+		},
+	}
+	want = "SELECT * FROM t WHERE d = '2024-01-01' AND secret = <redacted>"
+	got = q.DebugStringRedacted(func(_ int, marker ColumnInfo) bool { return marker.Name == "secret" })
+	if got != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+}
@@ -0,0 +1,298 @@
+package gocql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// cqlNativeTypes maps the names of CQL native types to their Type. text is
+// an alias of varchar, which is how servers describe it in result metadata.
+var cqlNativeTypes = map[string]Type{
+	"ascii":     TypeAscii,
+	"bigint":    TypeBigInt,
+	"blob":      TypeBlob,
+	"boolean":   TypeBoolean,
+	"counter":   TypeCounter,
+	"date":      TypeDate,
+	"decimal":   TypeDecimal,
+	"double":    TypeDouble,
+	"duration":  TypeDuration,
+	"float":     TypeFloat,
+	"inet":      TypeInet,
+	"int":       TypeInt,
+	"smallint":  TypeSmallInt,
+	"text":      TypeVarchar,
+	"time":      TypeTime,
+	"timestamp": TypeTimestamp,
+	"timeuuid":  TypeTimeUUID,
+	"tinyint":   TypeTinyInt,
+	"uuid":      TypeUUID,
+	"varchar":   TypeVarchar,
+	"varint":    TypeVarint,
+}
+
+// ParseCQLType parses a CQL type as written in schema metadata, such as
+// "frozen<map<text, list<int>>>" or "vector<float, 3>", into the TypeInfo of
+// protocol version proto. References to user-defined types are resolved
+// against ks, which may be nil if s does not refer to any.
+func ParseCQLType(s string, proto byte, ks *KeyspaceMetadata) (TypeInfo, error) {
+	p := cqlTypeParser{s: s, proto: proto, ks: ks}
+	return p.parse()
+}
+
+// TypeInfo returns the type of the column, resolving user-defined types
+// against ks, the keyspace of the column.
+func (c *ColumnMetadata) TypeInfo(proto byte, ks *KeyspaceMetadata) (TypeInfo, error) {
+	return ParseCQLType(c.Type, proto, ks)
+}
+
+// TypeInfo returns the user-defined type, resolving user-defined types of its
+// fields against ks, the keyspace of the type.
+func (t *TypeMetadata) TypeInfo(proto byte, ks *KeyspaceMetadata) (TypeInfo, error) {
+	return udtTypeInfo(t, proto, ks, 0)
+}
+
+// maxCQLTypeDepth bounds the nesting of user-defined types, which CQL does
+// not allow to be recursive, to fail on inconsistent metadata.
+const maxCQLTypeDepth = 64
+
+func udtTypeInfo(t *TypeMetadata, proto byte, ks *KeyspaceMetadata, depth int) (TypeInfo, error) {
+	if depth > maxCQLTypeDepth {
+		return nil, fmt.Errorf("gocql: user-defined type %s.%s nested too deeply", t.Keyspace, t.Name)
+	}
+	if len(t.FieldNames) != len(t.FieldTypes) {
+		return nil, fmt.Errorf("gocql: user-defined type %s.%s has %d field names but %d field types",
+			t.Keyspace, t.Name, len(t.FieldNames), len(t.FieldTypes))
+	}
+	udt := UDTTypeInfo{
+		NativeType: NewNativeType(proto, TypeUDT),
+		KeySpace:   t.Keyspace,
+		Name:       t.Name,
+		Elements:   make([]UDTField, len(t.FieldNames)),
+	}
+	for i, name := range t.FieldNames {
+		p := cqlTypeParser{s: t.FieldTypes[i], proto: proto, ks: ks, depth: depth + 1}
+		info, err := p.parse()
+		if err != nil {
+			return nil, fmt.Errorf("gocql: field %s of user-defined type %s.%s: %w", name, t.Keyspace, t.Name, err)
+		}
+		udt.Elements[i] = UDTField{Name: name, Type: info}
+	}
+	return udt, nil
+}
+
+type cqlTypeParser struct {
+	ks    *KeyspaceMetadata
+	s     string
+	pos   int
+	depth int
+	proto byte
+}
+
+func (p *cqlTypeParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("gocql: invalid CQL type %q: %s", p.s, fmt.Sprintf(format, args...))
+}
+
+// next returns the next token: an identifier, a quoted identifier or class
+// name, a number, or one of '<', '>' and ','. It returns "" at the end.
+func (p *cqlTypeParser) next() string {
+	for p.pos < len(p.s) && p.s[p.pos] == ' ' {
+		p.pos++
+	}
+	if p.pos == len(p.s) {
+		return ""
+	}
+	start := p.pos
+	switch c := p.s[p.pos]; c {
+	case '<', '>', ',':
+		p.pos++
+	case '"', '\'':
+		p.pos++
+		for p.pos < len(p.s) {
+			if p.s[p.pos] == c {
+				if p.pos+1 < len(p.s) && p.s[p.pos+1] == c {
+					p.pos += 2
+					continue
+				}
+				p.pos++
+				return p.s[start:p.pos]
+			}
+			p.pos++
+		}
+		// Unterminated quotes are returned whole and rejected by the caller.
+	default:
+		for p.pos < len(p.s) && !strings.ContainsRune(` <>,"'`, rune(p.s[p.pos])) {
+			p.pos++
+		}
+	}
+	return p.s[start:p.pos]
+}
+
+// parse parses the whole string as a single type.
+func (p *cqlTypeParser) parse() (TypeInfo, error) {
+	info, err := p.parseType()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.next(); tok != "" {
+		return nil, p.errorf("unexpected %q after type", tok)
+	}
+	return info, nil
+}
+
+func (p *cqlTypeParser) expect(tok string) error {
+	if got := p.next(); got != tok {
+		return p.errorf("expected %q, got %q", tok, got)
+	}
+	return nil
+}
+
+func (p *cqlTypeParser) parseType() (TypeInfo, error) {
+	tok := p.next()
+	switch {
+	case tok == "":
+		return nil, p.errorf("unexpected end")
+	case tok[0] == '\'':
+		class, ok := unquoteCQL(tok)
+		if !ok {
+			return nil, p.errorf("unterminated class name %s", tok)
+		}
+		info := getCassandraLongType(class, p.proto, nopLogger{})
+		if info.Type() == TypeCustom {
+			if _, ok := info.(VectorType); !ok {
+				return NewCustomType(p.proto, TypeCustom, class), nil
+			}
+		}
+		return info, nil
+	case tok[0] == '"':
+		name, ok := unquoteCQL(tok)
+		if !ok {
+			return nil, p.errorf("unterminated identifier %s", tok)
+		}
+		return p.resolveUDT(name)
+	}
+
+	name := strings.ToLower(tok)
+	if typ, ok := cqlNativeTypes[name]; ok {
+		return NewNativeType(p.proto, typ), nil
+	}
+	switch name {
+	case "frozen":
+		if err := p.expect("<"); err != nil {
+			return nil, err
+		}
+		info, err := p.parseType()
+		if err != nil {
+			return nil, err
+		}
+		return info, p.expect(">")
+	case "list", "set":
+		typ := TypeList
+		if name == "set" {
+			typ = TypeSet
+		}
+		elems, err := p.parseParams(1)
+		if err != nil {
+			return nil, err
+		}
+		return CollectionType{NativeType: NewNativeType(p.proto, typ), Elem: elems[0]}, nil
+	case "map":
+		elems, err := p.parseParams(2)
+		if err != nil {
+			return nil, err
+		}
+		return CollectionType{NativeType: NewNativeType(p.proto, TypeMap), Key: elems[0], Elem: elems[1]}, nil
+	case "tuple":
+		elems, err := p.parseParams(-1)
+		if err != nil {
+			return nil, err
+		}
+		return TupleTypeInfo{NativeType: NewNativeType(p.proto, TypeTuple), Elems: elems}, nil
+	case "vector":
+		return p.parseVector()
+	}
+	return p.resolveUDT(name)
+}
+
+// parseParams parses the type parameters between '<' and '>'. n is the
+// number of parameters expected, or -1 for any non-zero number.
+func (p *cqlTypeParser) parseParams(n int) ([]TypeInfo, error) {
+	if err := p.expect("<"); err != nil {
+		return nil, err
+	}
+	var elems []TypeInfo
+	for {
+		info, err := p.parseType()
+		if err != nil {
+			return nil, err
+		}
+		elems = append(elems, info)
+		tok := p.next()
+		if tok == ">" {
+			break
+		}
+		if tok != "," {
+			return nil, p.errorf("expected \",\" or \">\", got %q", tok)
+		}
+	}
+	if n >= 0 && len(elems) != n {
+		return nil, p.errorf("expected %d type parameters, got %d", n, len(elems))
+	}
+	return elems, nil
+}
+
+func (p *cqlTypeParser) parseVector() (TypeInfo, error) {
+	if err := p.expect("<"); err != nil {
+		return nil, err
+	}
+	sub, err := p.parseType()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(","); err != nil {
+		return nil, err
+	}
+	tok := p.next()
+	dim, err := strconv.Atoi(tok)
+	if err != nil || dim <= 0 {
+		return nil, p.errorf("invalid vector dimension %q", tok)
+	}
+	if err := p.expect(">"); err != nil {
+		return nil, err
+	}
+	return VectorType{
+		NativeType: NewCustomType(p.proto, TypeCustom, apacheCassandraTypePrefix+"VectorType"),
+		SubType:    sub,
+		Dimensions: dim,
+	}, nil
+}
+
+// resolveUDT resolves name, which may be qualified by the keyspace, against
+// the keyspace metadata.
+func (p *cqlTypeParser) resolveUDT(name string) (TypeInfo, error) {
+	if p.ks == nil {
+		return nil, p.errorf("unknown type %q, keyspace metadata is needed to resolve user-defined types", name)
+	}
+	if ks, typ, ok := strings.Cut(name, "."); ok {
+		if ks != p.ks.Name {
+			return nil, p.errorf("user-defined type %q is not in keyspace %q", name, p.ks.Name)
+		}
+		name = typ
+	}
+	t, ok := p.ks.Types[name]
+	if !ok {
+		return nil, p.errorf("unknown type %q in keyspace %q", name, p.ks.Name)
+	}
+	return udtTypeInfo(t, p.proto, p.ks, p.depth)
+}
+
+// unquoteCQL removes the quotes around tok and unescapes doubled quotes. It
+// reports false if tok is not terminated.
+func unquoteCQL(tok string) (string, bool) {
+	q := tok[:1]
+	if len(tok) < 2 || tok[len(tok)-1:] != q {
+		return "", false
+	}
+	return strings.ReplaceAll(tok[1:len(tok)-1], q+q, q), true
+}
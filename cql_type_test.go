@@ -0,0 +1,169 @@
+//go:build unit
+// +build unit
+
+package gocql
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseCQLType(t *testing.T) {
+	const proto = protoVersion4
+	native := func(typ Type) NativeType { return NewNativeType(proto, typ) }
+
+	ks := &KeyspaceMetadata{
+		Name: "ks",
+		Types: map[string]*TypeMetadata{
+			"address": {
+				Keyspace:   "ks",
+				Name:       "address",
+				FieldNames: []string{"street", "zip"},
+				FieldTypes: []string{"text", "int"},
+			},
+			"Person": {
+				Keyspace:   "ks",
+				Name:       "Person",
+				FieldNames: []string{"name", "addresses"},
+				FieldTypes: []string{"text", "frozen<list<frozen<address>>>"},
+			},
+		},
+	}
+	address := UDTTypeInfo{
+		NativeType: native(TypeUDT),
+		KeySpace:   "ks",
+		Name:       "address",
+		Elements: []UDTField{
+			{Name: "street", Type: native(TypeVarchar)},
+			{Name: "zip", Type: native(TypeInt)},
+		},
+	}
+
+	tests := []struct {
+		cql  string
+		want TypeInfo
+	}{
+		{"int", native(TypeInt)},
+		{"TEXT", native(TypeVarchar)},
+		{"timeuuid", native(TypeTimeUUID)},
+		{"list<int>", CollectionType{NativeType: native(TypeList), Elem: native(TypeInt)}},
+		{"frozen<set<text>>", CollectionType{NativeType: native(TypeSet), Elem: native(TypeVarchar)}},
+		{
+			"map<text, frozen<list<bigint>>>",
+			CollectionType{
+				NativeType: native(TypeMap),
+				Key:        native(TypeVarchar),
+				Elem:       CollectionType{NativeType: native(TypeList), Elem: native(TypeBigInt)},
+			},
+		},
+		{
+			"frozen<tuple<int, text, list<uuid>>>",
+			TupleTypeInfo{
+				NativeType: native(TypeTuple),
+				Elems: []TypeInfo{
+					native(TypeInt),
+					native(TypeVarchar),
+					CollectionType{NativeType: native(TypeList), Elem: native(TypeUUID)},
+				},
+			},
+		},
+		{
+			"vector<float, 3>",
+			VectorType{
+				NativeType: NewCustomType(proto, TypeCustom, apacheCassandraTypePrefix+"VectorType"),
+				SubType:    native(TypeFloat),
+				Dimensions: 3,
+			},
+		},
+		{"frozen<address>", address},
+		{"ks.address", address},
+		{
+			`map<int, frozen<"Person">>`,
+			CollectionType{
+				NativeType: native(TypeMap),
+				Key:        native(TypeInt),
+				Elem: UDTTypeInfo{
+					NativeType: native(TypeUDT),
+					KeySpace:   "ks",
+					Name:       "Person",
+					Elements: []UDTField{
+						{Name: "name", Type: native(TypeVarchar)},
+						{Name: "addresses", Type: CollectionType{NativeType: native(TypeList), Elem: address}},
+					},
+				},
+			},
+		},
+		{"'org.apache.cassandra.db.marshal.Int32Type'", native(TypeInt)},
+		{"'com.example.CustomType'", NewCustomType(proto, TypeCustom, "com.example.CustomType")},
+	}
+	for _, test := range tests {
+		got, err := ParseCQLType(test.cql, proto, ks)
+		if err != nil {
+			t.Errorf("%s: %v", test.cql, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: got %#v, want %#v", test.cql, got, test.want)
+		}
+	}
+}
+
+func TestParseCQLTypeInvalid(t *testing.T) {
+	ks := &KeyspaceMetadata{Name: "ks", Types: map[string]*TypeMetadata{}}
+	for _, cql := range []string{
+		"",
+		"list",
+		"list<int",
+		"list<int, int>",
+		"map<int>",
+		"tuple<>",
+		"vector<float>",
+		"vector<float, 0>",
+		"int int",
+		"frozen<int>>",
+		"unknown",
+		"other.address",
+		`"unterminated`,
+	} {
+		if _, err := ParseCQLType(cql, protoVersion4, ks); err == nil {
+			t.Errorf("%q: expected error", cql)
+		}
+	}
+	if _, err := ParseCQLType("address", protoVersion4, nil); err == nil {
+		t.Error("expected error resolving a user-defined type without keyspace metadata")
+	}
+}
+
+func TestColumnMetadataTypeInfo(t *testing.T) {
+	ks := &KeyspaceMetadata{
+		Name: "ks",
+		Types: map[string]*TypeMetadata{
+			"point": {Keyspace: "ks", Name: "point", FieldNames: []string{"x", "y"}, FieldTypes: []string{"int", "int"}},
+		},
+	}
+	col := &ColumnMetadata{Keyspace: "ks", Table: "t", Name: "p", Type: "frozen<point>"}
+	info, err := col.TypeInfo(protoVersion4, ks)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := Marshal(info, map[string]any{"x": 1, "y": 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]any
+	if err := Unmarshal(info, data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]any{"x": 1, "y": 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	udt, err := ks.Types["point"].TypeInfo(protoVersion4, ks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(udt, info) {
+		t.Errorf("TypeMetadata.TypeInfo: got %#v, want %#v", udt, info)
+	}
+}
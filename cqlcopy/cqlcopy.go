@@ -0,0 +1,284 @@
+// Package cqlcopy exports query results to, and imports rows from, CSV and
+// JSON, like the COPY TO and COPY FROM commands of cqlsh.
+//
+// Rows are streamed: exports read the rows of an Iter as they are fetched,
+// page by page, and imports write rows with a gocql.BulkWriter as they are
+// read, so tables of any size can be copied in bounded memory:
+//
+//	iter := session.Query(`SELECT * FROM ks.users`).PageSize(1000).Iter()
+//	n, err := cqlcopy.ExportCSV(ctx, iter, w)
+//
+//	n, err := cqlcopy.ImportCSV(ctx, session, "ks.users", r, cqlcopy.ImportOptions{})
+//
+// Values are formatted according to the type of their column. In CSV, text
+// values are written as is, null as an empty field, and other values as CQL
+// literals without the quotes around strings, so that timestamps are written
+// as 2024-01-02 03:04:05.000+0000 and collections as [1, 2] or
+// {'a': 0xcafe}. In JSON, numbers, booleans, lists, sets, tuples and vectors
+// are written as their JSON counterparts, maps and user-defined types as
+// objects and other values as strings formatted like in CSV.
+package cqlcopy
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/inf.v0"
+
+	"github.com/gocql/gocql"
+)
+
+// ImportOptions configures ImportCSV and ImportJSON.
+type ImportOptions struct {
+	// Columns are the columns the fields of CSV records are written to, in
+	// order, or the columns JSON objects may have. Defaults to the first
+	// record of the CSV, which is then read as a header, or to the keys of
+	// the first JSON object.
+	Columns []string
+
+	// Null is the CSV field that is read as null. Defaults to the empty
+	// field.
+	Null string
+
+	// SkipNulls writes nulls as unset values, which leave the columns
+	// unchanged instead of writing tombstones.
+	SkipNulls bool
+
+	// Writer configures the writes of the rows.
+	Writer gocql.BulkWriterOptions
+}
+
+// isText reports whether values of typ are written as is.
+func isText(typ gocql.Type) bool {
+	return typ == gocql.TypeVarchar || typ == gocql.TypeText || typ == gocql.TypeAscii
+}
+
+// isCustom reports whether info is a custom type other than a vector, whose
+// values are copied as blobs.
+func isCustom(info gocql.TypeInfo) bool {
+	_, vector := info.(gocql.VectorType)
+	return info.Type() == gocql.TypeCustom && !vector
+}
+
+// formatField formats the non-null serialized value data of type info as
+// text: as is for text types and as a CQL literal without quotes otherwise.
+func formatField(info gocql.TypeInfo, data []byte) (string, error) {
+	switch {
+	case isText(info.Type()):
+		return string(data), nil
+	case isCustom(info):
+		return "0x" + hex.EncodeToString(data), nil
+	}
+	literal, err := gocql.FormatCQLLiteral(info, gocql.DirectMarshal(data))
+	if err != nil {
+		return "", err
+	}
+	if strings.HasPrefix(literal, "'") {
+		return strings.ReplaceAll(literal[1:len(literal)-1], "''", "'"), nil
+	}
+	return literal, nil
+}
+
+// parseField parses s, formatted by formatField, into a value that can be
+// marshaled as info.
+func parseField(info gocql.TypeInfo, s string) (any, error) {
+	switch info.Type() {
+	case gocql.TypeList, gocql.TypeSet, gocql.TypeMap, gocql.TypeTuple, gocql.TypeUDT:
+	case gocql.TypeCustom:
+		if isCustom(info) {
+			b, err := parseBlob(s)
+			return gocql.DirectMarshal(b), err
+		}
+	default:
+		return parseScalar(info, s)
+	}
+	p := literalParser{s: s}
+	v, err := p.value(info)
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.pos != len(p.s) {
+		return nil, p.errorf("unexpected %q after value", p.s[p.pos:])
+	}
+	return v, nil
+}
+
+// parseScalar parses s as a value of the native type info.
+func parseScalar(info gocql.TypeInfo, s string) (any, error) {
+	switch info.Type() {
+	case gocql.TypeVarchar, gocql.TypeText, gocql.TypeAscii:
+		return s, nil
+	case gocql.TypeBlob:
+		return parseBlob(s)
+	case gocql.TypeBoolean:
+		switch strings.ToLower(s) {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		}
+	case gocql.TypeTinyInt, gocql.TypeSmallInt, gocql.TypeInt, gocql.TypeBigInt, gocql.TypeCounter:
+		if v, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return v, nil
+		}
+	case gocql.TypeVarint:
+		if v, ok := new(big.Int).SetString(s, 10); ok {
+			return v, nil
+		}
+	case gocql.TypeDecimal:
+		if v, ok := new(inf.Dec).SetString(s); ok {
+			return v, nil
+		}
+	case gocql.TypeFloat:
+		// ParseFloat accepts NaN, Infinity and -Infinity, like CQL.
+		if v, err := strconv.ParseFloat(s, 32); err == nil {
+			return float32(v), nil
+		}
+	case gocql.TypeDouble:
+		if v, err := strconv.ParseFloat(s, 64); err == nil {
+			return v, nil
+		}
+	case gocql.TypeUUID, gocql.TypeTimeUUID:
+		return gocql.ParseUUID(s)
+	case gocql.TypeInet:
+		if ip := net.ParseIP(s); ip != nil {
+			return ip, nil
+		}
+	case gocql.TypeTimestamp:
+		return parseTimestamp(s)
+	case gocql.TypeDate:
+		if v, err := time.Parse(time.DateOnly, s); err == nil {
+			return v, nil
+		}
+	case gocql.TypeTime:
+		if v, err := time.Parse("15:04:05.999999999", s); err == nil {
+			return time.Duration(v.Hour())*time.Hour + time.Duration(v.Minute())*time.Minute +
+				time.Duration(v.Second())*time.Second + time.Duration(v.Nanosecond()), nil
+		}
+	case gocql.TypeDuration:
+		return parseDuration(s)
+	default:
+		return nil, fmt.Errorf("unsupported type %s", info)
+	}
+	return nil, fmt.Errorf("invalid %s value %q", info, s)
+}
+
+func parseBlob(s string) ([]byte, error) {
+	if len(s) < 2 || s[0] != '0' || (s[1] != 'x' && s[1] != 'X') {
+		return nil, fmt.Errorf("invalid blob value %q, expected 0x followed by hex digits", s)
+	}
+	b, err := hex.DecodeString(s[2:])
+	if err != nil {
+		return nil, fmt.Errorf("invalid blob value %q: %v", s, err)
+	}
+	return b, nil
+}
+
+// timestampLayouts are the layouts timestamps are parsed with, the first of
+// which is the one FormatCQLLiteral formats them with.
+var timestampLayouts = []string{
+	"2006-01-02 15:04:05.999-0700",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02T15:04:05.999999999-0700",
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	time.DateOnly,
+}
+
+// parseTimestamp parses s in one of timestampLayouts, in UTC unless s has a
+// time zone, or as a number of milliseconds since the epoch.
+func parseTimestamp(s string) (time.Time, error) {
+	if ms, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.UnixMilli(ms).UTC(), nil
+	}
+	for _, layout := range timestampLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid timestamp value %q", s)
+}
+
+// parseDuration parses a duration in the unit format of CQL, such as
+// -1y2mo3d4h5m6s7ms8us9ns.
+func parseDuration(s string) (gocql.Duration, error) {
+	invalid := fmt.Errorf("invalid duration value %q", s)
+	rest, neg := strings.CutPrefix(s, "-")
+	if rest == "" {
+		return gocql.Duration{}, invalid
+	}
+	var months, days, nanos int64
+	for rest != "" {
+		i := strings.IndexFunc(rest, func(r rune) bool { return r < '0' || r > '9' })
+		if i <= 0 {
+			return gocql.Duration{}, invalid
+		}
+		n, err := strconv.ParseInt(rest[:i], 10, 64)
+		if err != nil {
+			return gocql.Duration{}, invalid
+		}
+		rest = rest[i:]
+		j := strings.IndexFunc(rest, func(r rune) bool { return r >= '0' && r <= '9' })
+		if j < 0 {
+			j = len(rest)
+		}
+		unit := strings.ToLower(rest[:j])
+		rest = rest[j:]
+		switch unit {
+		case "y":
+			months += n * 12
+		case "mo":
+			months += n
+		case "w":
+			days += n * 7
+		case "d":
+			days += n
+		case "h":
+			nanos += n * int64(time.Hour)
+		case "m":
+			nanos += n * int64(time.Minute)
+		case "s":
+			nanos += n * int64(time.Second)
+		case "ms":
+			nanos += n * int64(time.Millisecond)
+		case "us", "µs":
+			nanos += n * int64(time.Microsecond)
+		case "ns":
+			nanos += n
+		default:
+			return gocql.Duration{}, invalid
+		}
+	}
+	if months > 1<<31-1 || days > 1<<31-1 {
+		return gocql.Duration{}, invalid
+	}
+	d := gocql.Duration{Months: int32(months), Days: int32(days), Nanoseconds: nanos}
+	if neg {
+		d = gocql.Duration{Months: -d.Months, Days: -d.Days, Nanoseconds: -d.Nanoseconds}
+	}
+	return d, nil
+}
+
+// marshaledKey is a map key that is already serialized, as the values of
+// some CQL types, such as blobs, can not be Go map keys.
+type marshaledKey string
+
+func (k marshaledKey) MarshalCQL(gocql.TypeInfo) ([]byte, error) {
+	return []byte(k), nil
+}
+
+// mapKey returns v, a parsed key of the map info, as a marshaledKey.
+func mapKey(info gocql.CollectionType, v any) (marshaledKey, error) {
+	b, err := gocql.Marshal(info.Key, v)
+	if err != nil {
+		return "", err
+	}
+	return marshaledKey(b), nil
+}
@@ -0,0 +1,563 @@
+//go:build unit
+// +build unit
+
+package cqlcopy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/inf.v0"
+
+	"github.com/gocql/gocql"
+)
+
+const proto = 4
+
+func native(typ gocql.Type) gocql.NativeType {
+	return gocql.NewNativeType(proto, typ)
+}
+
+func list(elem gocql.TypeInfo) gocql.CollectionType {
+	return gocql.CollectionType{NativeType: native(gocql.TypeList), Elem: elem}
+}
+
+var (
+	point = gocql.UDTTypeInfo{
+		NativeType: native(gocql.TypeUDT),
+		KeySpace:   "ks",
+		Name:       "point",
+		Elements: []gocql.UDTField{
+			{Name: "x", Type: native(gocql.TypeInt)},
+			{Name: "Label", Type: native(gocql.TypeVarchar)},
+		},
+	}
+	vector = gocql.VectorType{
+		NativeType: gocql.NewCustomType(proto, gocql.TypeCustom, "org.apache.cassandra.db.marshal.VectorType"),
+		SubType:    native(gocql.TypeFloat),
+		Dimensions: 2,
+	}
+	uuid = gocql.ParseUUIDMust("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+)
+
+// copyTests are values of every type with their CSV field and JSON value.
+var copyTests = []struct {
+	info  gocql.TypeInfo
+	value any
+	csv   string
+	json  string
+}{
+	{native(gocql.TypeVarchar), `it's "x", y`, `it's "x", y`, `"it's \"x\", y"`},
+	{native(gocql.TypeBlob), []byte{0xca, 0xfe}, "0xcafe", `"0xcafe"`},
+	{native(gocql.TypeBoolean), true, "true", "true"},
+	{native(gocql.TypeTinyInt), int8(-3), "-3", "-3"},
+	{native(gocql.TypeBigInt), int64(math.MaxInt64), "9223372036854775807", "9223372036854775807"},
+	{native(gocql.TypeVarint), int64(12), "12", "12"},
+	{native(gocql.TypeDecimal), inf.NewDec(-1234, 3), "-1.234", "-1.234"},
+	{native(gocql.TypeFloat), float32(0.5), "0.5", "0.5"},
+	{native(gocql.TypeDouble), math.Inf(1), "Infinity", `"Infinity"`},
+	{native(gocql.TypeUUID), uuid, uuid.String(), `"` + uuid.String() + `"`},
+	{native(gocql.TypeInet), "10.0.0.1", "10.0.0.1", `"10.0.0.1"`},
+	{native(gocql.TypeTimestamp), time.Date(2024, 1, 2, 3, 4, 5, 6e6, time.UTC), "2024-01-02 03:04:05.006+0000", `"2024-01-02 03:04:05.006+0000"`},
+	{native(gocql.TypeDate), time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), "2024-01-02", `"2024-01-02"`},
+	{native(gocql.TypeTime), 13*time.Hour + 5*time.Millisecond, "13:00:00.005000000", `"13:00:00.005000000"`},
+	{native(gocql.TypeDuration), gocql.Duration{Months: -1, Days: -2, Nanoseconds: -3}, "-1mo2d3ns", `"-1mo2d3ns"`},
+	{list(native(gocql.TypeVarchar)), []string{"a'b", "c"}, "['a''b', 'c']", `["a'b","c"]`},
+	{
+		gocql.CollectionType{NativeType: native(gocql.TypeSet), Elem: native(gocql.TypeBlob)},
+		[][]byte{{1}, {2}},
+		"{0x01, 0x02}",
+		`["0x01","0x02"]`,
+	},
+	{
+		gocql.CollectionType{NativeType: native(gocql.TypeMap), Key: native(gocql.TypeTimestamp), Elem: list(native(gocql.TypeInt))},
+		map[time.Time][]int{time.UnixMilli(0).UTC(): {1, 2}},
+		"{'1970-01-01 00:00:00.000+0000': [1, 2]}",
+		`{"1970-01-01 00:00:00.000+0000":[1,2]}`,
+	},
+	{
+		gocql.TupleTypeInfo{NativeType: native(gocql.TypeTuple), Elems: []gocql.TypeInfo{native(gocql.TypeInt), native(gocql.TypeVarchar)}},
+		[]any{1, nil},
+		"(1, null)",
+		"[1,null]",
+	},
+	{point, map[string]any{"x": 1, "Label": "a"}, `{"x": 1, "Label": 'a'}`, `{"x":1,"Label":"a"}`},
+	{vector, []float32{1, -2.5}, "[1, -2.5]", "[1,-2.5]"},
+	{gocql.NewCustomType(proto, gocql.TypeCustom, "com.example.Custom"), gocql.DirectMarshal{0xab}, "0xab", `"0xab"`},
+}
+
+// sameValue checks that got marshals to the same value as want.
+func sameValue(t *testing.T, info gocql.TypeInfo, got, want any) {
+	t.Helper()
+	if isCustom(info) {
+		g, _ := gocql.Marshal(info, got)
+		w, _ := gocql.Marshal(info, want)
+		if string(g) != string(w) {
+			t.Errorf("%s: got %x, want %x", info, g, w)
+		}
+		return
+	}
+	g, err := gocql.FormatCQLLiteral(info, got)
+	if err != nil {
+		t.Errorf("%s: formatting %#v: %v", info, got, err)
+		return
+	}
+	w, err := gocql.FormatCQLLiteral(info, want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g != w {
+		t.Errorf("%s: got %s, want %s", info, g, w)
+	}
+}
+
+func TestFormatParseField(t *testing.T) {
+	for _, test := range copyTests {
+		data, err := gocql.Marshal(test.info, test.value)
+		if err != nil {
+			t.Fatalf("%s: %v", test.info, err)
+		}
+		got, err := formatField(test.info, data)
+		if err != nil {
+			t.Errorf("%s: %v", test.info, err)
+			continue
+		}
+		if got != test.csv {
+			t.Errorf("%s: got %s, want %s", test.info, got, test.csv)
+		}
+		v, err := parseField(test.info, got)
+		if err != nil {
+			t.Errorf("%s: parsing %s: %v", test.info, got, err)
+			continue
+		}
+		sameValue(t, test.info, v, test.value)
+	}
+}
+
+func TestWriteParseJSON(t *testing.T) {
+	for _, test := range copyTests {
+		data, err := gocql.Marshal(test.info, test.value)
+		if err != nil {
+			t.Fatalf("%s: %v", test.info, err)
+		}
+		var b strings.Builder
+		if err := writeJSON(&b, test.info, data); err != nil {
+			t.Errorf("%s: %v", test.info, err)
+			continue
+		}
+		if got := b.String(); got != test.json {
+			t.Errorf("%s: got %s, want %s", test.info, got, test.json)
+		}
+		if !json.Valid([]byte(b.String())) {
+			t.Errorf("%s: invalid JSON %s", test.info, b.String())
+		}
+		v, err := parseJSON(test.info, json.RawMessage(b.String()))
+		if err != nil {
+			t.Errorf("%s: parsing %s: %v", test.info, b.String(), err)
+			continue
+		}
+		sameValue(t, test.info, v, test.value)
+	}
+}
+
+func TestParseFieldInvalid(t *testing.T) {
+	for _, test := range []struct {
+		info gocql.TypeInfo
+		s    string
+	}{
+		{native(gocql.TypeInt), "1.5"},
+		{native(gocql.TypeBlob), "cafe"},
+		{native(gocql.TypeBoolean), "yes"},
+		{native(gocql.TypeDuration), "1x"},
+		{native(gocql.TypeDuration), "5"},
+		{native(gocql.TypeTimestamp), "yesterday"},
+		{list(native(gocql.TypeInt)), "[1, 2"},
+		{list(native(gocql.TypeInt)), "[1 2]"},
+		{list(native(gocql.TypeInt)), "[1] x"},
+		{list(native(gocql.TypeVarchar)), "['a]"},
+		{point, "{y: 1}"},
+		{gocql.TupleTypeInfo{NativeType: native(gocql.TypeTuple), Elems: []gocql.TypeInfo{native(gocql.TypeInt)}}, "(1, 2)"},
+	} {
+		if v, err := parseField(test.info, test.s); err == nil {
+			t.Errorf("%s %q: expected error, got %#v", test.info, test.s, v)
+		}
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	for s, want := range map[string]gocql.Duration{
+		"1y2mo":       {Months: 14},
+		"3w1d":        {Days: 22},
+		"1h30m":       {Nanoseconds: int64(90 * time.Minute)},
+		"1s2ms3µs4ns": {Nanoseconds: int64(time.Second + 2*time.Millisecond + 3*time.Microsecond + 4)},
+		"-2us":        {Nanoseconds: -2000},
+	} {
+		got, err := parseDuration(s)
+		if err != nil {
+			t.Errorf("%s: %v", s, err)
+		} else if got != want {
+			t.Errorf("%s: got %+v, want %+v", s, got, want)
+		}
+	}
+}
+
+type fakeRows struct {
+	columns []gocql.ColumnInfo
+	rows    [][][]byte
+	err     error
+}
+
+func (r *fakeRows) Columns() []gocql.ColumnInfo { return r.columns }
+
+func (r *fakeRows) ScanRaw(fn func([][]byte) error) bool {
+	if r.err != nil || len(r.rows) == 0 {
+		return false
+	}
+	row := r.rows[0]
+	r.rows = r.rows[1:]
+	if err := fn(row); err != nil {
+		r.err = err
+		return false
+	}
+	return true
+}
+
+func (r *fakeRows) Close() error { return r.err }
+
+func newFakeRows(t *testing.T) *fakeRows {
+	t.Helper()
+	marshal := func(info gocql.TypeInfo, v any) []byte {
+		data, err := gocql.Marshal(info, v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+	return &fakeRows{
+		columns: []gocql.ColumnInfo{
+			{Name: "id", TypeInfo: native(gocql.TypeInt)},
+			{Name: "name", TypeInfo: native(gocql.TypeVarchar)},
+			{Name: "tags", TypeInfo: list(native(gocql.TypeVarchar))},
+		},
+		rows: [][][]byte{
+			{marshal(native(gocql.TypeInt), 1), []byte("a,b"), marshal(list(native(gocql.TypeVarchar)), []string{"x"})},
+			{marshal(native(gocql.TypeInt), 2), nil, nil},
+		},
+	}
+}
+
+func TestExportCSV(t *testing.T) {
+	var b strings.Builder
+	n, err := exportCSV(context.Background(), newFakeRows(t), &b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Errorf("got %d rows, want 2", n)
+	}
+	want := "id,name,tags\n1,\"a,b\",['x']\n2,,\n"
+	if b.String() != want {
+		t.Errorf("got %q, want %q", b.String(), want)
+	}
+
+	imp := &importer{markers: newFakeRows(t).columns}
+	values, err := imp.parseRecord([]string{"2", "", ""})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if values[0] != int64(2) || values[1] != nil || values[2] != nil {
+		t.Errorf("got %#v", values)
+	}
+	imp.opts.SkipNulls = true
+	if values, err = imp.parseRecord([]string{"2", "", ""}); err != nil {
+		t.Fatal(err)
+	}
+	if values[1] != gocql.UnsetValue {
+		t.Errorf("got %#v for a skipped null, want unset", values[1])
+	}
+	if _, err := imp.parseRecord([]string{"x", "", ""}); err == nil || !strings.Contains(err.Error(), "column id") {
+		t.Errorf("got error %v, want an error for column id", err)
+	}
+}
+
+func TestExportJSON(t *testing.T) {
+	var b strings.Builder
+	n, err := exportJSON(context.Background(), newFakeRows(t), &b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Errorf("got %d rows, want 2", n)
+	}
+	want := `{"id":1,"name":"a,b","tags":["x"]}` + "\n" + `{"id":2,"name":null,"tags":null}` + "\n"
+	if b.String() != want {
+		t.Errorf("got %q, want %q", b.String(), want)
+	}
+
+	imp := &importer{markers: newFakeRows(t).columns}
+	values, err := imp.parseObject(map[string]json.RawMessage{"id": json.RawMessage("2"), "name": json.RawMessage("null")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if values[0] != int64(2) || values[1] != nil || values[2] != gocql.UnsetValue {
+		t.Errorf("got %#v", values)
+	}
+	if _, err := imp.parseObject(map[string]json.RawMessage{"id": json.RawMessage("2"), "other": json.RawMessage("1")}); err == nil {
+		t.Error("expected error for an unknown column")
+	}
+}
+
+func TestExportCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var b strings.Builder
+	if _, err := exportCSV(ctx, newFakeRows(t), &b); !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v, want %v", err, context.Canceled)
+	}
+}
+
+type fakeWriter struct {
+	rows   [][]any
+	closed bool
+}
+
+func (w *fakeWriter) Write(ctx context.Context, values ...any) error {
+	w.rows = append(w.rows, values)
+	return nil
+}
+
+func (w *fakeWriter) Close() error {
+	w.closed = true
+	return nil
+}
+
+// fakeImporter returns an openImporter writing rows of the columns of
+// newFakeRows to w, which records the columns it is opened with.
+func fakeImporter(t *testing.T, w *fakeWriter, opts ImportOptions, opened *[]string) openImporter {
+	table := newFakeRows(t).columns
+	return func(columns []string) (*importer, error) {
+		*opened = columns
+		markers := make([]gocql.ColumnInfo, len(columns))
+		for i, name := range columns {
+			j := slices.IndexFunc(table, func(col gocql.ColumnInfo) bool { return col.Name == name })
+			if j < 0 {
+				return nil, fmt.Errorf("undefined column name %s", name)
+			}
+			markers[i] = table[j]
+		}
+		return &importer{w: w, markers: markers, opts: opts}, nil
+	}
+}
+
+func TestImportCSV(t *testing.T) {
+	ctx := context.Background()
+	for _, test := range []struct {
+		name    string
+		input   string
+		opts    ImportOptions
+		columns []string
+		want    [][]any
+	}{
+		{
+			name:    "header",
+			input:   "name,id\na,1\n,2\n",
+			columns: []string{"name", "id"},
+			want:    [][]any{{"a", int64(1)}, {nil, int64(2)}},
+		},
+		{
+			name:    "columns",
+			input:   "1,a\n",
+			opts:    ImportOptions{Columns: []string{"id", "name"}},
+			columns: []string{"id", "name"},
+			want:    [][]any{{int64(1), "a"}},
+		},
+		{
+			name:    "null",
+			input:   "id,name\n1,NULL\n2,\n",
+			opts:    ImportOptions{Null: "NULL"},
+			columns: []string{"id", "name"},
+			want:    [][]any{{int64(1), nil}, {int64(2), ""}},
+		},
+		{
+			name:    "skip nulls",
+			input:   "id,name\n1,\n",
+			opts:    ImportOptions{SkipNulls: true},
+			columns: []string{"id", "name"},
+			want:    [][]any{{int64(1), gocql.UnsetValue}},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				w      fakeWriter
+				opened []string
+			)
+			n, err := importCSV(ctx, strings.NewReader(test.input), test.opts, fakeImporter(t, &w, test.opts, &opened))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n != int64(len(test.want)) || !w.closed {
+				t.Errorf("got %d rows, closed %v, want %d rows, closed", n, w.closed, len(test.want))
+			}
+			if !slices.Equal(opened, test.columns) {
+				t.Errorf("got columns %v, want %v", opened, test.columns)
+			}
+			if !reflect.DeepEqual(w.rows, test.want) {
+				t.Errorf("got rows %#v, want %#v", w.rows, test.want)
+			}
+		})
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		for input, want := range map[string]string{
+			"":                      "reading header",
+			"id,other\n1,2\n":       "undefined column name other",
+			"id,name\n1,a\nx,b\n":   "line 3: column id",
+			"id,name\n1,a\n1,a,b\n": "wrong number of fields",
+		} {
+			var (
+				w      fakeWriter
+				opened []string
+			)
+			_, err := importCSV(ctx, strings.NewReader(input), ImportOptions{}, fakeImporter(t, &w, ImportOptions{}, &opened))
+			if err == nil || !strings.Contains(err.Error(), want) {
+				t.Errorf("%q: got error %v, want %q", input, err, want)
+			}
+		}
+	})
+}
+
+func TestImportJSON(t *testing.T) {
+	ctx := context.Background()
+	input := `{"name":"a","id":1}` + "\n" + `{"id":2,"name":null}` + "\n" + `{"id":3}` + "\n"
+	for _, test := range []struct {
+		name    string
+		opts    ImportOptions
+		columns []string
+		want    [][]any
+	}{
+		{
+			name:    "first object",
+			columns: []string{"id", "name"},
+			want:    [][]any{{int64(1), "a"}, {int64(2), nil}, {int64(3), gocql.UnsetValue}},
+		},
+		{
+			name:    "columns",
+			opts:    ImportOptions{Columns: []string{"name", "id", "tags"}},
+			columns: []string{"name", "id", "tags"},
+			want: [][]any{
+				{"a", int64(1), gocql.UnsetValue},
+				{nil, int64(2), gocql.UnsetValue},
+				{gocql.UnsetValue, int64(3), gocql.UnsetValue},
+			},
+		},
+		{
+			name:    "skip nulls",
+			opts:    ImportOptions{SkipNulls: true},
+			columns: []string{"id", "name"},
+			want:    [][]any{{int64(1), "a"}, {int64(2), gocql.UnsetValue}, {int64(3), gocql.UnsetValue}},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				w      fakeWriter
+				opened []string
+			)
+			n, err := importJSON(ctx, strings.NewReader(input), test.opts, fakeImporter(t, &w, test.opts, &opened))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n != int64(len(test.want)) || !w.closed {
+				t.Errorf("got %d rows, closed %v, want %d rows, closed", n, w.closed, len(test.want))
+			}
+			if !slices.Equal(opened, test.columns) {
+				t.Errorf("got columns %v, want %v", opened, test.columns)
+			}
+			if !reflect.DeepEqual(w.rows, test.want) {
+				t.Errorf("got rows %#v, want %#v", w.rows, test.want)
+			}
+		})
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		for input, want := range map[string]string{
+			`{}`:                          "no columns to import",
+			`{"id":1}` + "\n" + `{"x":2}`: "object 2: unknown column x",
+			`{"id":"x"}`:                  "object 1: column id",
+			`{"id":1`:                     "unexpected EOF",
+		} {
+			var (
+				w      fakeWriter
+				opened []string
+			)
+			_, err := importJSON(ctx, strings.NewReader(input), ImportOptions{}, fakeImporter(t, &w, ImportOptions{}, &opened))
+			if err == nil || !strings.Contains(err.Error(), want) {
+				t.Errorf("%q: got error %v, want %q", input, err, want)
+			}
+		}
+	})
+}
+
+// TestExportImport checks that the rows imported from an export are the
+// exported rows.
+func TestExportImport(t *testing.T) {
+	ctx := context.Background()
+	for name, format := range map[string]struct {
+		export func(context.Context, rawRows, io.Writer) (int64, error)
+		imp    func(context.Context, io.Reader, ImportOptions, openImporter) (int64, error)
+	}{
+		"CSV":  {exportCSV, importCSV},
+		"JSON": {exportJSON, importJSON},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var b strings.Builder
+			if _, err := format.export(ctx, newFakeRows(t), &b); err != nil {
+				t.Fatal(err)
+			}
+			var (
+				w      fakeWriter
+				opened []string
+			)
+			if _, err := format.imp(ctx, strings.NewReader(b.String()), ImportOptions{}, fakeImporter(t, &w, ImportOptions{}, &opened)); err != nil {
+				t.Fatal(err)
+			}
+
+			exported := newFakeRows(t)
+			if len(w.rows) != len(exported.rows) {
+				t.Fatalf("imported %d rows, want %d", len(w.rows), len(exported.rows))
+			}
+			for i, row := range w.rows {
+				for j, v := range row {
+					col := exported.columns[j]
+					if opened[j] != col.Name {
+						t.Fatalf("imported column %s, want %s", opened[j], col.Name)
+					}
+					data, err := gocql.Marshal(col.TypeInfo, v)
+					if err != nil {
+						t.Fatalf("row %d column %s: %v", i, col.Name, err)
+					}
+					if want := exported.rows[i][j]; !bytes.Equal(data, want) || (data == nil) != (want == nil) {
+						t.Errorf("row %d column %s: got %x, want %x", i, col.Name, data, want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestInsertStatement(t *testing.T) {
+	got := insertStatement("ks.t", []string{"id", `We"ird`})
+	want := `INSERT INTO ks.t ("id", "We""ird") VALUES (?, ?)`
+	if got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
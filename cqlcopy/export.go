@@ -0,0 +1,243 @@
+package cqlcopy
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/gocql/gocql"
+)
+
+// rawRows is the part of *gocql.Iter exports read rows with.
+type rawRows interface {
+	Columns() []gocql.ColumnInfo
+	ScanRaw(fn func(cols [][]byte) error) bool
+	Close() error
+}
+
+// ExportCSV writes the rows of iter to w as CSV, with a header record of the
+// column names, and closes iter. It returns the number of rows written.
+func ExportCSV(ctx context.Context, iter *gocql.Iter, w io.Writer) (int64, error) {
+	return exportCSV(ctx, iter, w)
+}
+
+func exportCSV(ctx context.Context, iter rawRows, w io.Writer) (int64, error) {
+	cw := csv.NewWriter(w)
+	columns := iter.Columns()
+	record := make([]string, len(columns))
+	for i, col := range columns {
+		record[i] = col.Name
+	}
+	if err := cw.Write(record); err != nil {
+		iter.Close()
+		return 0, err
+	}
+
+	var rows int64
+	for iter.ScanRaw(func(cols [][]byte) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		for i, col := range cols {
+			record[i] = ""
+			if col != nil {
+				s, err := formatField(columns[i].TypeInfo, col)
+				if err != nil {
+					return fmt.Errorf("cqlcopy: %w", columnError(columns[i], err))
+				}
+				record[i] = s
+			}
+		}
+		rows++
+		return cw.Write(record)
+	}) {
+	}
+	if err := iter.Close(); err != nil {
+		return rows, err
+	}
+	cw.Flush()
+	return rows, cw.Error()
+}
+
+// ExportJSON writes the rows of iter to w as JSON objects, one per line,
+// keyed by column name, and closes iter. It returns the number of rows
+// written.
+func ExportJSON(ctx context.Context, iter *gocql.Iter, w io.Writer) (int64, error) {
+	return exportJSON(ctx, iter, w)
+}
+
+func exportJSON(ctx context.Context, iter rawRows, w io.Writer) (int64, error) {
+	bw := bufio.NewWriter(w)
+	columns := iter.Columns()
+	var b strings.Builder
+
+	var rows int64
+	for iter.ScanRaw(func(cols [][]byte) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		b.Reset()
+		b.WriteByte('{')
+		for i, col := range cols {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			writeJSONString(&b, columns[i].Name)
+			b.WriteByte(':')
+			if err := writeJSON(&b, columns[i].TypeInfo, col); err != nil {
+				return fmt.Errorf("cqlcopy: %w", columnError(columns[i], err))
+			}
+		}
+		b.WriteString("}\n")
+		rows++
+		_, err := bw.WriteString(b.String())
+		return err
+	}) {
+	}
+	if err := iter.Close(); err != nil {
+		return rows, err
+	}
+	return rows, bw.Flush()
+}
+
+func writeJSONString(b *strings.Builder, s string) {
+	// Marshaling a string can not fail.
+	data, _ := json.Marshal(s)
+	b.Write(data)
+}
+
+// rawKey is a map key holding its serialized value.
+type rawKey string
+
+func (k *rawKey) UnmarshalCQL(_ gocql.TypeInfo, data []byte) error {
+	*k = rawKey(data)
+	return nil
+}
+
+// rawUDT holds the serialized values of the fields of a user-defined type.
+type rawUDT map[string][]byte
+
+func (u rawUDT) UnmarshalUDT(name string, _ gocql.TypeInfo, data []byte) error {
+	u[name] = data
+	return nil
+}
+
+// writeJSON writes data, the serialized value of info, as JSON.
+func writeJSON(b *strings.Builder, info gocql.TypeInfo, data []byte) error {
+	if data == nil {
+		b.WriteString("null")
+		return nil
+	}
+
+	switch info := info.(type) {
+	case gocql.CollectionType:
+		if info.Type() == gocql.TypeMap {
+			var m map[rawKey]gocql.BorrowedUnmarshal
+			if err := gocql.Unmarshal(info, data, &m); err != nil {
+				return err
+			}
+			keys := make([]string, 0, len(m))
+			values := make(map[string][]byte, len(m))
+			for k, v := range m {
+				key, err := formatField(info.Key, []byte(k))
+				if err != nil {
+					return err
+				}
+				keys = append(keys, key)
+				values[key] = v
+			}
+			slices.Sort(keys)
+			b.WriteByte('{')
+			for i, key := range keys {
+				if i > 0 {
+					b.WriteByte(',')
+				}
+				writeJSONString(b, key)
+				b.WriteByte(':')
+				if err := writeJSON(b, info.Elem, values[key]); err != nil {
+					return err
+				}
+			}
+			b.WriteByte('}')
+			return nil
+		}
+		var elems []gocql.BorrowedUnmarshal
+		if err := gocql.Unmarshal(info, data, &elems); err != nil {
+			return err
+		}
+		return writeJSONArray(b, elems, func(int) gocql.TypeInfo { return info.Elem })
+	case gocql.VectorType:
+		var elems []gocql.BorrowedUnmarshal
+		if err := gocql.Unmarshal(info, data, &elems); err != nil {
+			return err
+		}
+		return writeJSONArray(b, elems, func(int) gocql.TypeInfo { return info.SubType })
+	case gocql.TupleTypeInfo:
+		elems := make([]gocql.BorrowedUnmarshal, len(info.Elems))
+		dest := make([]any, len(elems))
+		for i := range elems {
+			dest[i] = &elems[i]
+		}
+		if err := gocql.Unmarshal(info, data, dest); err != nil {
+			return err
+		}
+		return writeJSONArray(b, elems, func(i int) gocql.TypeInfo { return info.Elems[i] })
+	case gocql.UDTTypeInfo:
+		fields := make(rawUDT, len(info.Elements))
+		if err := gocql.Unmarshal(info, data, fields); err != nil {
+			return err
+		}
+		b.WriteByte('{')
+		for i, e := range info.Elements {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			writeJSONString(b, e.Name)
+			b.WriteByte(':')
+			if err := writeJSON(b, e.Type, fields[e.Name]); err != nil {
+				return err
+			}
+		}
+		b.WriteByte('}')
+		return nil
+	}
+
+	s, err := formatField(info, data)
+	if err != nil {
+		return err
+	}
+	switch info.Type() {
+	case gocql.TypeBoolean, gocql.TypeTinyInt, gocql.TypeSmallInt, gocql.TypeInt, gocql.TypeBigInt,
+		gocql.TypeCounter, gocql.TypeVarint, gocql.TypeDecimal:
+		b.WriteString(s)
+	case gocql.TypeFloat, gocql.TypeDouble:
+		// NaN and infinities have no JSON number representation.
+		if s == "NaN" || strings.HasSuffix(s, "Infinity") {
+			writeJSONString(b, s)
+		} else {
+			b.WriteString(s)
+		}
+	default:
+		writeJSONString(b, s)
+	}
+	return nil
+}
+
+func writeJSONArray(b *strings.Builder, elems []gocql.BorrowedUnmarshal, info func(int) gocql.TypeInfo) error {
+	b.WriteByte('[')
+	for i, elem := range elems {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		if err := writeJSON(b, info(i), elem); err != nil {
+			return err
+		}
+	}
+	b.WriteByte(']')
+	return nil
+}
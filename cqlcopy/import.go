@@ -0,0 +1,338 @@
+package cqlcopy
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/gocql/gocql"
+	"github.com/gocql/gocql/internal/cqlident"
+)
+
+func columnError(col gocql.ColumnInfo, err error) error {
+	return fmt.Errorf("column %s: %w", col.Name, err)
+}
+
+// rowWriter is the part of *gocql.BulkWriter imports write rows with.
+type rowWriter interface {
+	Write(ctx context.Context, values ...any) error
+	Close() error
+}
+
+// importer writes the rows read by ImportCSV and ImportJSON.
+type importer struct {
+	w       rowWriter
+	markers []gocql.ColumnInfo
+	opts    ImportOptions
+	rows    int64
+}
+
+// insertStatement returns the statement inserting columns into table. table
+// is used as is and columns are quoted.
+func insertStatement(table string, columns []string) string {
+	quoted := make([]string, len(columns))
+	for i, col := range columns {
+		quoted[i] = cqlident.Quote(col)
+	}
+	return fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
+		table, strings.Join(quoted, ", "), strings.Repeat(", ?", len(columns))[2:])
+}
+
+// openImporter returns the importer of the rows with columns.
+type openImporter func(columns []string) (*importer, error)
+
+// sessionImporter returns the openImporter writing rows of table with s.
+func sessionImporter(ctx context.Context, s *gocql.Session, table string, opts ImportOptions) openImporter {
+	return func(columns []string) (*importer, error) {
+		return newImporter(ctx, s, table, columns, opts)
+	}
+}
+
+func newImporter(ctx context.Context, s *gocql.Session, table string, columns []string, opts ImportOptions) (*importer, error) {
+	w, err := s.NewBulkWriter(ctx, insertStatement(table, columns), opts.Writer)
+	if err != nil {
+		return nil, err
+	}
+	return &importer{w: w, markers: w.BindMarkers(), opts: opts}, nil
+}
+
+func (imp *importer) write(ctx context.Context, values []any) error {
+	if err := imp.w.Write(ctx, values...); err != nil {
+		return err
+	}
+	imp.rows++
+	return nil
+}
+
+// close waits for the rows to be written. err, if not nil, is the error
+// that stopped the import, which is returned instead of write errors.
+func (imp *importer) close(err error) (int64, error) {
+	if closeErr := imp.w.Close(); err == nil {
+		err = closeErr
+	}
+	return imp.rows, err
+}
+
+// null returns the value nulls are written as.
+func (opts *ImportOptions) null() any {
+	if opts.SkipNulls {
+		return gocql.UnsetValue
+	}
+	return nil
+}
+
+// ImportCSV writes the CSV records read from r as rows of table, which may
+// be qualified by its keyspace and is used as is in the INSERT statement. The
+// rows are written concurrently, see gocql.BulkWriter. It returns the number
+// of rows read, once they are all written.
+func ImportCSV(ctx context.Context, s *gocql.Session, table string, r io.Reader, opts ImportOptions) (int64, error) {
+	return importCSV(ctx, r, opts, sessionImporter(ctx, s, table, opts))
+}
+
+func importCSV(ctx context.Context, r io.Reader, opts ImportOptions, open openImporter) (int64, error) {
+	cr := csv.NewReader(r)
+	cr.ReuseRecord = true
+	columns := opts.Columns
+	if len(columns) == 0 {
+		header, err := cr.Read()
+		if err != nil {
+			return 0, fmt.Errorf("cqlcopy: reading header: %w", err)
+		}
+		columns = slices.Clone(header)
+	} else {
+		cr.FieldsPerRecord = len(columns)
+	}
+
+	if len(columns) == 0 {
+		return 0, errors.New("cqlcopy: no columns to import")
+	}
+	imp, err := open(columns)
+	if err != nil {
+		return 0, err
+	}
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return imp.close(nil)
+		} else if err != nil {
+			return imp.close(err)
+		}
+		values, err := imp.parseRecord(record)
+		if err != nil {
+			line, _ := cr.FieldPos(0)
+			return imp.close(fmt.Errorf("cqlcopy: line %d: %w", line, err))
+		}
+		if err := imp.write(ctx, values); err != nil {
+			return imp.close(err)
+		}
+	}
+}
+
+// parseRecord parses the fields of record into the values of a row.
+func (imp *importer) parseRecord(record []string) ([]any, error) {
+	values := make([]any, len(record))
+	for i, field := range record {
+		if field == imp.opts.Null {
+			values[i] = imp.opts.null()
+			continue
+		}
+		v, err := parseField(imp.markers[i].TypeInfo, field)
+		if err != nil {
+			return nil, columnError(imp.markers[i], err)
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+// ImportJSON writes the JSON objects read from r, such as the lines written
+// by ExportJSON, as rows of table, like ImportCSV. Columns missing from an
+// object are written as unset values.
+func ImportJSON(ctx context.Context, s *gocql.Session, table string, r io.Reader, opts ImportOptions) (int64, error) {
+	return importJSON(ctx, r, opts, sessionImporter(ctx, s, table, opts))
+}
+
+func importJSON(ctx context.Context, r io.Reader, opts ImportOptions, open openImporter) (int64, error) {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	var object map[string]json.RawMessage
+	decode := func() error {
+		clear(object)
+		return dec.Decode(&object)
+	}
+
+	columns := opts.Columns
+	// pending is set when the first object was read to find the columns.
+	pending := false
+	if len(columns) == 0 {
+		if err := decode(); err == io.EOF {
+			return 0, nil
+		} else if err != nil {
+			return 0, err
+		}
+		for col := range object {
+			columns = append(columns, col)
+		}
+		slices.Sort(columns)
+		pending = true
+	}
+
+	if len(columns) == 0 {
+		return 0, errors.New("cqlcopy: no columns to import")
+	}
+	imp, err := open(columns)
+	if err != nil {
+		return 0, err
+	}
+	for {
+		if !pending {
+			if err := decode(); err == io.EOF {
+				return imp.close(nil)
+			} else if err != nil {
+				return imp.close(err)
+			}
+		}
+		pending = false
+		values, err := imp.parseObject(object)
+		if err != nil {
+			return imp.close(fmt.Errorf("cqlcopy: object %d: %w", imp.rows+1, err))
+		}
+		if err := imp.write(ctx, values); err != nil {
+			return imp.close(err)
+		}
+	}
+}
+
+// parseObject parses the fields of object into the values of a row.
+func (imp *importer) parseObject(object map[string]json.RawMessage) ([]any, error) {
+	values := make([]any, len(imp.markers))
+	found := 0
+	for i, marker := range imp.markers {
+		raw, ok := object[marker.Name]
+		if !ok {
+			values[i] = gocql.UnsetValue
+			continue
+		}
+		found++
+		v, err := parseJSON(marker.TypeInfo, raw)
+		if err != nil {
+			return nil, columnError(marker, err)
+		}
+		if v == nil {
+			v = imp.opts.null()
+		}
+		values[i] = v
+	}
+	if found < len(object) {
+		for name := range object {
+			if !slices.ContainsFunc(imp.markers, func(m gocql.ColumnInfo) bool { return m.Name == name }) {
+				return nil, fmt.Errorf("unknown column %s", name)
+			}
+		}
+	}
+	return values, nil
+}
+
+// parseJSON parses raw, written by writeJSON, into a value that can be
+// marshaled as info.
+func parseJSON(info gocql.TypeInfo, raw json.RawMessage) (any, error) {
+	if bytes.Equal(raw, []byte("null")) {
+		return nil, nil
+	}
+
+	switch info := info.(type) {
+	case gocql.CollectionType:
+		if info.Type() == gocql.TypeMap {
+			var object map[string]json.RawMessage
+			if err := json.Unmarshal(raw, &object); err != nil {
+				return nil, err
+			}
+			m := make(map[marshaledKey]any, len(object))
+			for k, v := range object {
+				kv, err := parseField(info.Key, k)
+				if err != nil {
+					return nil, err
+				}
+				key, err := mapKey(info, kv)
+				if err != nil {
+					return nil, err
+				}
+				if m[key], err = parseJSON(info.Elem, v); err != nil {
+					return nil, err
+				}
+			}
+			return m, nil
+		}
+		return parseJSONArray(raw, func(int) gocql.TypeInfo { return info.Elem })
+	case gocql.VectorType:
+		return parseJSONArray(raw, func(int) gocql.TypeInfo { return info.SubType })
+	case gocql.TupleTypeInfo:
+		elems, err := parseJSONArray(raw, func(i int) gocql.TypeInfo {
+			if i < len(info.Elems) {
+				return info.Elems[i]
+			}
+			return nil
+		})
+		if err == nil && len(elems) != len(info.Elems) {
+			return nil, fmt.Errorf("got %d tuple elements, want %d", len(elems), len(info.Elems))
+		}
+		return elems, err
+	case gocql.UDTTypeInfo:
+		var object map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &object); err != nil {
+			return nil, err
+		}
+		m := make(map[string]any, len(object))
+		for _, e := range info.Elements {
+			if v, ok := object[e.Name]; ok {
+				var err error
+				if m[e.Name], err = parseJSON(e.Type, v); err != nil {
+					return nil, err
+				}
+			}
+		}
+		if len(m) != len(object) {
+			return nil, fmt.Errorf("unknown fields of %s in %s", info.Name, raw)
+		}
+		return m, nil
+	}
+
+	// Numbers and booleans are parsed from their JSON text, other values
+	// from JSON strings.
+	s := string(raw)
+	if raw[0] == '"' {
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return nil, err
+		}
+	}
+	if isCustom(info) {
+		b, err := parseBlob(s)
+		return gocql.DirectMarshal(b), err
+	}
+	return parseScalar(info, s)
+}
+
+func parseJSONArray(raw json.RawMessage, info func(int) gocql.TypeInfo) ([]any, error) {
+	var array []json.RawMessage
+	if err := json.Unmarshal(raw, &array); err != nil {
+		return nil, err
+	}
+	elems := make([]any, len(array))
+	for i, elem := range array {
+		elemInfo := info(i)
+		if elemInfo == nil {
+			return nil, fmt.Errorf("unexpected element %s", elem)
+		}
+		var err error
+		if elems[i], err = parseJSON(elemInfo, elem); err != nil {
+			return nil, err
+		}
+	}
+	return elems, nil
+}
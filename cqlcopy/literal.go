@@ -0,0 +1,214 @@
+package cqlcopy
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gocql/gocql"
+)
+
+// literalParser parses CQL literals of collections, tuples, user-defined
+// types and vectors, as formatted by gocql.FormatCQLLiteral, into values
+// that can be marshaled as their type.
+type literalParser struct {
+	s   string
+	pos int
+}
+
+func (p *literalParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("invalid literal %q: %s", p.s, fmt.Sprintf(format, args...))
+}
+
+func (p *literalParser) skipSpace() {
+	for p.pos < len(p.s) && strings.IndexByte(" \t\r\n", p.s[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+// consume skips spaces and c, reporting whether c was next.
+func (p *literalParser) consume(c byte) bool {
+	p.skipSpace()
+	if p.pos < len(p.s) && p.s[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// isDelim reports whether c ends an unquoted token.
+func isDelim(c byte) bool {
+	return strings.IndexByte(" \t\r\n,:[]{}()'\"", c) >= 0
+}
+
+// token returns the next quoted or unquoted token, unquoted, and whether it
+// was quoted with q.
+func (p *literalParser) token(q byte) (string, bool, error) {
+	p.skipSpace()
+	if p.pos == len(p.s) {
+		return "", false, p.errorf("unexpected end")
+	}
+	start := p.pos
+	if c := p.s[p.pos]; c == '\'' || c == '"' {
+		if c != q {
+			return "", false, p.errorf("unexpected %c at %d", c, p.pos)
+		}
+		var b strings.Builder
+		for p.pos++; p.pos < len(p.s); p.pos++ {
+			if p.s[p.pos] == c {
+				if p.pos+1 < len(p.s) && p.s[p.pos+1] == c {
+					b.WriteByte(c)
+					p.pos++
+					continue
+				}
+				p.pos++
+				return b.String(), true, nil
+			}
+			b.WriteByte(p.s[p.pos])
+		}
+		return "", false, p.errorf("unterminated string at %d", start)
+	}
+	for p.pos < len(p.s) && !isDelim(p.s[p.pos]) {
+		p.pos++
+	}
+	if p.pos == start {
+		return "", false, p.errorf("unexpected %c at %d", p.s[p.pos], p.pos)
+	}
+	return p.s[start:p.pos], false, nil
+}
+
+// null consumes null, reporting whether it was next.
+func (p *literalParser) null() bool {
+	p.skipSpace()
+	end := p.pos + len("null")
+	if end <= len(p.s) && strings.EqualFold(p.s[p.pos:end], "null") && (end == len(p.s) || isDelim(p.s[end])) {
+		p.pos = end
+		return true
+	}
+	return false
+}
+
+// sequence parses elements with elem between open and close, separated by
+// commas.
+func (p *literalParser) sequence(open, close byte, elem func() error) error {
+	if !p.consume(open) {
+		return p.errorf("expected %c at %d", open, p.pos)
+	}
+	if p.consume(close) {
+		return nil
+	}
+	for {
+		if err := elem(); err != nil {
+			return err
+		}
+		if p.consume(close) {
+			return nil
+		}
+		if !p.consume(',') {
+			return p.errorf("expected , or %c at %d", close, p.pos)
+		}
+	}
+}
+
+// value parses a value of type info.
+func (p *literalParser) value(info gocql.TypeInfo) (any, error) {
+	if p.null() {
+		return nil, nil
+	}
+
+	switch info := info.(type) {
+	case gocql.CollectionType:
+		switch info.Type() {
+		case gocql.TypeList, gocql.TypeSet:
+			open, close := byte('['), byte(']')
+			if info.Type() == gocql.TypeSet {
+				open, close = '{', '}'
+			}
+			var elems []any
+			err := p.sequence(open, close, func() error {
+				v, err := p.value(info.Elem)
+				elems = append(elems, v)
+				return err
+			})
+			if elems == nil {
+				elems = []any{}
+			}
+			return elems, err
+		case gocql.TypeMap:
+			m := make(map[marshaledKey]any)
+			err := p.sequence('{', '}', func() error {
+				k, err := p.value(info.Key)
+				if err != nil {
+					return err
+				}
+				if !p.consume(':') {
+					return p.errorf("expected : at %d", p.pos)
+				}
+				v, err := p.value(info.Elem)
+				if err != nil {
+					return err
+				}
+				key, err := mapKey(info, k)
+				m[key] = v
+				return err
+			})
+			return m, err
+		}
+	case gocql.TupleTypeInfo:
+		var elems []any
+		err := p.sequence('(', ')', func() error {
+			if len(elems) == len(info.Elems) {
+				return p.errorf("too many tuple elements")
+			}
+			v, err := p.value(info.Elems[len(elems)])
+			elems = append(elems, v)
+			return err
+		})
+		if err == nil && len(elems) != len(info.Elems) {
+			return nil, p.errorf("got %d tuple elements, want %d", len(elems), len(info.Elems))
+		}
+		return elems, err
+	case gocql.UDTTypeInfo:
+		m := make(map[string]any)
+		err := p.sequence('{', '}', func() error {
+			name, quoted, err := p.token('"')
+			if err != nil {
+				return err
+			}
+			if !quoted {
+				name = strings.ToLower(name)
+			}
+			var field gocql.TypeInfo
+			for _, e := range info.Elements {
+				if e.Name == name {
+					field = e.Type
+				}
+			}
+			if field == nil {
+				return p.errorf("unknown field %q of %s", name, info.Name)
+			}
+			if !p.consume(':') {
+				return p.errorf("expected : at %d", p.pos)
+			}
+			m[name], err = p.value(field)
+			return err
+		})
+		return m, err
+	case gocql.VectorType:
+		var elems []any
+		err := p.sequence('[', ']', func() error {
+			v, err := p.value(info.SubType)
+			elems = append(elems, v)
+			return err
+		})
+		return elems, err
+	}
+
+	tok, _, err := p.token('\'')
+	if err != nil {
+		return nil, err
+	}
+	if isCustom(info) {
+		return parseBlob(tok)
+	}
+	return parseScalar(info, tok)
+}
@@ -0,0 +1,276 @@
+package gocql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gocql/gocql/tablets"
+)
+
+// DiagnosticsSnapshot is a view of the internal state of a session, for
+// diagnosing it, returned by Session.Diagnostics. It is meant to be
+// serialized to JSON, and its layout may change between versions.
+type DiagnosticsSnapshot struct {
+	Time      time.Time         `json:"time"`
+	SessionID string            `json:"session_id"`
+	Hosts     []HostDiagnostics `json:"hosts"`
+	// ControlConnection is nil when the session has no control connection.
+	ControlConnection *ControlConnectionDiagnostics `json:"control_connection,omitempty"`
+	Schema            SchemaDiagnostics             `json:"schema"`
+	// TokenRingSize is the number of tokens of the hosts.
+	TokenRingSize int                 `json:"token_ring_size"`
+	Tablets       []TabletDiagnostics `json:"tablets,omitempty"`
+	// PreparedStatements is the number of entries of the prepared statement
+	// cache.
+	PreparedStatements  int `json:"prepared_statements"`
+	EventBusSubscribers int `json:"event_bus_subscribers"`
+	// DriverConfig is the effective configuration of the driver, as reported
+	// to the server unless ClusterConfig.DisableDriverConfigReporting is set.
+	DriverConfig json.RawMessage `json:"driver_config,omitempty"`
+	// Metrics is nil unless ClusterConfig.Metrics is set.
+	Metrics *MetricsSnapshot `json:"metrics,omitempty"`
+}
+
+// HostDiagnostics is the state of a host and of its connection pool.
+type HostDiagnostics struct {
+	HostID        string `json:"host_id"`
+	Address       string `json:"address"`
+	DataCenter    string `json:"data_center"`
+	Rack          string `json:"rack"`
+	State         string `json:"state"`
+	Version       string `json:"version"`
+	SchemaVersion string `json:"schema_version,omitempty"`
+	// Shards is the number of shards of the host, or 0 if it is not
+	// sharded.
+	Shards int `json:"shards"`
+	// ShardConnections is the number of connections to every shard of the
+	// host, including excess connections.
+	ShardConnections  []int `json:"shard_connections,omitempty"`
+	Connections       int   `json:"connections"`
+	ExcessConnections int   `json:"excess_connections"`
+	InFlight          int   `json:"in_flight"`
+	AvailableStreams  int   `json:"available_streams"`
+	// Latency is nil unless the host selection policy is, or falls back to,
+	// a LatencyAwarePolicy which measured the host.
+	Latency *LatencyDiagnostics `json:"latency,omitempty"`
+}
+
+// LatencyDiagnostics is the latency of a host measured by a
+// LatencyAwarePolicy.
+type LatencyDiagnostics struct {
+	// Average is the exponentially weighted moving average of the latency
+	// of the host.
+	Average         time.Duration `json:"average"`
+	Measurements    int           `json:"measurements"`
+	LastMeasurement time.Time     `json:"last_measurement"`
+	// Demoted reports whether the host is tried after the other hosts
+	// because it is slower than the fastest host by more than the exclusion
+	// threshold of the policy.
+	Demoted bool                      `json:"demoted"`
+	Shards  []ShardLatencyDiagnostics `json:"shards,omitempty"`
+}
+
+// ShardLatencyDiagnostics is the latency of a shard of a host measured by a
+// LatencyAwarePolicy.
+type ShardLatencyDiagnostics struct {
+	Shard        int           `json:"shard"`
+	Average      time.Duration `json:"average"`
+	Measurements int           `json:"measurements"`
+}
+
+// ControlConnectionDiagnostics is the state of the control connection.
+type ControlConnectionDiagnostics struct {
+	HostID  string `json:"host_id"`
+	Address string `json:"address"`
+	// Shard is the shard of the connection, or -1 if the host is not
+	// sharded.
+	Shard int `json:"shard"`
+}
+
+// SchemaDiagnostics is the schema agreement of the cluster, as seen by the
+// host of the control connection.
+type SchemaDiagnostics struct {
+	Agreement bool `json:"agreement"`
+	// Versions maps the schema versions of the cluster to the IDs of the
+	// hosts having them.
+	Versions map[string][]string `json:"versions,omitempty"`
+	// Error is the error the schema versions could not be queried with.
+	Error string `json:"error,omitempty"`
+}
+
+// TabletDiagnostics is the number of tablets of a table known to the
+// driver.
+type TabletDiagnostics struct {
+	Keyspace string `json:"keyspace"`
+	Table    string `json:"table"`
+	Tablets  int    `json:"tablets"`
+}
+
+// Diagnostics returns a snapshot of the internal state of the session. It
+// queries the schema versions of the cluster on the control connection,
+// within ClusterConfig.Timeout, and reads everything else from memory.
+func (s *Session) Diagnostics() DiagnosticsSnapshot {
+	snapshot := DiagnosticsSnapshot{
+		Time:      time.Now(),
+		SessionID: s.id,
+		Metrics:   s.Metrics(),
+	}
+
+	var hosts []*HostInfo
+	if s.hostSource != nil {
+		hosts = s.hostSource.getHostsList()
+	}
+	var latencies map[UUID]*LatencyDiagnostics
+	if policy := findLatencyAwarePolicy(s.policy); policy != nil {
+		latencies = policy.latencyDiagnostics(snapshot.Time)
+	}
+	for _, host := range hosts {
+		snapshot.TokenRingSize += len(host.Tokens())
+		h := s.hostDiagnostics(host)
+		h.Latency = latencies[host.hostUUID()]
+		snapshot.Hosts = append(snapshot.Hosts, h)
+	}
+	slices.SortFunc(snapshot.Hosts, func(a, b HostDiagnostics) int {
+		return strings.Compare(a.Address, b.Address)
+	})
+
+	isScyllaConn := false
+	if s.control != nil {
+		if ch := s.control.getConn(); ch != nil {
+			snapshot.ControlConnection = &ControlConnectionDiagnostics{
+				HostID:  ch.host.HostID(),
+				Address: ch.host.ConnectAddressAndPort(),
+				Shard:   -1,
+			}
+			if conn, ok := ch.conn.(*Conn); ok {
+				snapshot.ControlConnection.Shard = attemptShard(conn)
+				isScyllaConn = conn.isScyllaConn()
+				snapshot.Schema = s.schemaDiagnostics(conn)
+			}
+		}
+	}
+	if snapshot.ControlConnection == nil {
+		snapshot.Schema.Error = "no control connection"
+	}
+
+	if s.metadataDescriber != nil && s.metadataDescriber.metadata != nil {
+		s.metadataDescriber.forEachTablet(func(keyspace, table string, entries tablets.TabletEntryList) bool {
+			snapshot.Tablets = append(snapshot.Tablets, TabletDiagnostics{
+				Keyspace: keyspace,
+				Table:    table,
+				Tablets:  len(entries),
+			})
+			return true
+		})
+		slices.SortFunc(snapshot.Tablets, func(a, b TabletDiagnostics) int {
+			if c := strings.Compare(a.Keyspace, b.Keyspace); c != 0 {
+				return c
+			}
+			return strings.Compare(a.Table, b.Table)
+		})
+	}
+
+	if s.stmtsLRU != nil {
+		s.stmtsLRU.mu.Lock()
+		snapshot.PreparedStatements = s.stmtsLRU.lru.Len()
+		s.stmtsLRU.mu.Unlock()
+	}
+	if s.eventBus != nil {
+		snapshot.EventBusSubscribers = s.eventBus.SubscriberCount()
+	}
+	if s.driverConfigReporter != nil {
+		if report, err := s.driverConfigReporter.buildReport(isScyllaConn); err == nil {
+			snapshot.DriverConfig = json.RawMessage(report)
+		}
+	}
+	return snapshot
+}
+
+func (s *Session) hostDiagnostics(host *HostInfo) HostDiagnostics {
+	host.mu.RLock()
+	schemaVersion := host.schemaVersion
+	host.mu.RUnlock()
+
+	h := HostDiagnostics{
+		HostID:        host.HostID(),
+		Address:       host.ConnectAddressAndPort(),
+		DataCenter:    host.DataCenter(),
+		Rack:          host.Rack(),
+		State:         host.State().String(),
+		Version:       host.Version().String(),
+		SchemaVersion: schemaVersion,
+	}
+	if s.pool == nil {
+		return h
+	}
+	if pool, ok := s.pool.getPool(host); ok {
+		h.Shards = pool.GetShardCount()
+		h.ShardConnections = pool.ShardConnections()
+		h.Connections = pool.Size()
+		h.ExcessConnections = pool.GetExcessConnectionCount()
+		h.InFlight = pool.InFlight()
+		h.AvailableStreams = pool.AvailableStreams()
+	}
+	return h
+}
+
+// schemaDiagnostics queries the schema versions of the cluster on conn.
+func (s *Session) schemaDiagnostics(conn *Conn) SchemaDiagnostics {
+	ctx := context.Background()
+	if s.cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.cfg.Timeout)
+		defer cancel()
+	}
+
+	diagnostics := SchemaDiagnostics{Versions: make(map[string][]string)}
+	var localVersion string
+	iter := conn.querySystem(ctx, "SELECT schema_version FROM system.local WHERE key='local'")
+	iter.Scan(&localVersion)
+	if err := iter.Close(); err != nil {
+		return SchemaDiagnostics{Error: err.Error()}
+	}
+	if localVersion != "" {
+		diagnostics.Versions[localVersion] = append(diagnostics.Versions[localVersion], conn.host.HostID())
+	}
+
+	query := "SELECT host_id, schema_version FROM system.peers"
+	if conn.getIsSchemaV2() {
+		query = "SELECT host_id, schema_version FROM system.peers_v2"
+	}
+	iter = conn.querySystem(ctx, query)
+	var hostID, version UUID
+	for iter.Scan(&hostID, &version) {
+		if !version.IsEmpty() {
+			diagnostics.Versions[version.String()] = append(diagnostics.Versions[version.String()], hostID.String())
+		}
+	}
+	if err := iter.Close(); err != nil {
+		return SchemaDiagnostics{Error: err.Error()}
+	}
+	diagnostics.Agreement = len(diagnostics.Versions) == 1
+	return diagnostics
+}
+
+// DiagnosticsHandler returns an http.Handler serving the diagnostics of the
+// session as JSON, see Session.Diagnostics.
+func (s *Session) DiagnosticsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var b bytes.Buffer
+		enc := json.NewEncoder(&b)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(s.Diagnostics()); err != nil {
+			http.Error(w, "gocql: encoding diagnostics: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := b.WriteTo(w); err != nil {
+			slogger(s.logger).Debug("failed to write diagnostics response", logError(err))
+		}
+	})
+}
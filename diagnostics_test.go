@@ -0,0 +1,107 @@
+//go:build unit
+// +build unit
+
+package gocql
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql/internal/lru"
+)
+
+func TestSessionDiagnostics(t *testing.T) {
+	t.Parallel()
+
+	sharded := (&HostInfo{
+		hostId:         UUID{1},
+		connectAddress: []byte{127, 0, 0, 1},
+		port:           9042,
+		dataCenter:     "dc1",
+		rack:           "rack1",
+		tokens:         []string{"1", "2"},
+		schemaVersion:  "v1",
+	}).setState(NodeUp)
+	unsharded := (&HostInfo{
+		hostId:         UUID{2},
+		connectAddress: []byte{127, 0, 0, 2},
+		port:           9042,
+		tokens:         []string{"3"},
+	}).setState(NodeDown)
+
+	picker := &scyllaConnPicker{
+		logger:                     &testLogger{},
+		disableShardAwarePortUntil: new(atomic.Pointer[time.Time]),
+		conns:                      make([]*Conn, 3),
+		nrShards:                   3,
+		msbIgnore:                  12,
+		excessConnsLimitRate:       1,
+	}
+	for _, shard := range []int{0, 2} {
+		if err := picker.Put(mockConnForPicker(shard, 3)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	policy := LatencyAwarePolicy(RoundRobinHostPolicy(), LatencyAwarePolicyOptions{})
+	policy.(*latencyAwarePolicy).add(sharded, 2, time.Millisecond, time.Now())
+
+	stmts := &preparedLRU{lru: lru.New[stmtCacheKey](10)}
+	stmts.add(stmts.keyFor(UUID{1}, "ks", "SELECT 1"), &inflightPrepare{})
+	s := &Session{
+		id: "session",
+		hostSource: &ringDescriber{hosts: map[string]*HostInfo{
+			sharded.HostID():   sharded,
+			unsharded.HostID(): unsharded,
+		}},
+		pool: &policyConnPool{hostConnPools: map[UUID]*hostConnPool{
+			sharded.hostUUID(): {host: sharded, connPicker: picker},
+		}},
+		stmtsLRU: stmts,
+		policy:   policy,
+	}
+
+	d := s.Diagnostics()
+	if d.SessionID != "session" || d.TokenRingSize != 3 || d.PreparedStatements != 1 {
+		t.Fatalf("diagnostics = %+v, want session id, 3 tokens and 1 prepared statement", d)
+	}
+	if d.ControlConnection != nil || d.Schema.Error == "" {
+		t.Errorf("control connection = %+v, schema = %+v, want none", d.ControlConnection, d.Schema)
+	}
+	if len(d.Hosts) != 2 {
+		t.Fatalf("hosts = %+v, want 2 hosts", d.Hosts)
+	}
+
+	h := d.Hosts[0]
+	if h.HostID != sharded.HostID() || h.Address != "127.0.0.1:9042" || h.DataCenter != "dc1" || h.Rack != "rack1" ||
+		h.State != "UP" || h.SchemaVersion != "v1" {
+		t.Errorf("sharded host = %+v", h)
+	}
+	if h.Shards != 3 || h.Connections != 2 || len(h.ShardConnections) != 3 ||
+		h.ShardConnections[0] != 1 || h.ShardConnections[1] != 0 || h.ShardConnections[2] != 1 {
+		t.Errorf("sharded host pool = %+v, want 2 connections to shards 0 and 2 of 3", h)
+	}
+	if h.Latency == nil || h.Latency.Average != time.Millisecond || len(h.Latency.Shards) != 1 || h.Latency.Shards[0].Shard != 2 {
+		t.Errorf("sharded host latency = %+v, want 1ms measured on shard 2", h.Latency)
+	}
+	if h := d.Hosts[1]; h.State != "DOWN" || h.Shards != 0 || h.Connections != 0 || h.ShardConnections != nil || h.Latency != nil {
+		t.Errorf("unsharded host = %+v, want down without a pool", h)
+	}
+
+	rec := httptest.NewRecorder()
+	s.DiagnosticsHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/diagnostics", nil))
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("content type = %q, want application/json", ct)
+	}
+	var served DiagnosticsSnapshot
+	if err := json.Unmarshal(rec.Body.Bytes(), &served); err != nil {
+		t.Fatal(err)
+	}
+	if len(served.Hosts) != 2 || served.Hosts[0].ShardConnections[2] != 1 {
+		t.Errorf("served diagnostics = %+v", served)
+	}
+}
@@ -165,6 +165,29 @@
 //	cluster := gocql.NewCluster("192.168.1.1", "192.168.1.2", "192.168.1.3")
 //	cluster.PoolConfig.HostSelectionPolicy = gocql.TokenAwareHostPolicy(gocql.RackAwareRoundRobinPolicy("dc1", "rack1"))
 //
+// # Remote datacenter failover
+//
+// By default, both policies fail over to the hosts of all remote datacenters alike. To fail over to the closest
+// datacenters first, to a few hosts of each, and never for queries at LOCAL_* consistency:
+//
+//	cluster.PoolConfig.HostSelectionPolicy = gocql.TokenAwareHostPolicy(gocql.DCAwareRoundRobinPolicy("dc1",
+//		gocql.HostPolicyOptionPreferredRemoteDCs("dc2", "dc3"),
+//		gocql.HostPolicyOptionMaxHostsPerRemoteDC(2),
+//		gocql.HostPolicyOptionNoRemoteDCsForLocalConsistency,
+//	), gocql.NonLocalReplicasFallback())
+//
+// The consistency is checked for every attempt, so a retry policy downgrading a query to LOCAL_ONE does not retry it
+// on a remote host. HostPolicyOptionDisableDCFailover disables the failover altogether.
+//
+// # Latency awareness
+//
+// LatencyAwarePolicy wraps a policy and tries hosts much slower than the fastest one last, for example while they
+// are slowed down by compaction. Used as the fallback of TokenAwareHostPolicy, it orders the replicas too:
+//
+//	cluster.PoolConfig.HostSelectionPolicy = gocql.TokenAwareHostPolicy(
+//		gocql.LatencyAwarePolicy(gocql.DCAwareRoundRobinPolicy("dc1"), gocql.LatencyAwarePolicyOptions{}),
+//	)
+//
 // # AWS-specific considerations
 //
 // When using rack-aware policies with AWS, note that Availability Zone (AZ) names like "us-east-1a" are not consistent
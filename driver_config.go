@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"math"
 	"reflect"
+	"slices"
 	"time"
 )
 
@@ -807,11 +808,32 @@ func effectiveExpBackoff(configured, fallback time.Duration) time.Duration {
 }
 
 func buildLoadBalancingReport(policy HostSelectionPolicy) queryLoadBalancingReport {
+	latencyAware := findLatencyAwarePolicy(policy) != nil
 	policy = unwrapHostSelectionPolicy(policy)
-	return queryLoadBalancingReport{
+	report := queryLoadBalancingReport{
 		Policy:         buildLoadBalancingPolicyReport(policy),
 		NodePreference: buildNodeLocationPreferenceReport(policy),
 	}
+	// A LatencyAwarePolicy wrapping the token-aware policy, rather than
+	// being its fallback, orders the hosts by latency as well.
+	if tokenAware, ok := report.Policy.(loadBalancingTokenAwareReport); ok && latencyAware {
+		report.Policy = withAdaptiveOrderingSignal(tokenAware, "latency")
+	}
+	return report
+}
+
+// withAdaptiveOrderingSignal returns report with signal among the signals
+// of its adaptive ordering.
+func withAdaptiveOrderingSignal(report loadBalancingTokenAwareReport, signal string) loadBalancingTokenAwareReport {
+	if report.AdaptiveOrdering == nil {
+		report.AdaptiveOrdering = &adaptiveOrderingReport{}
+	}
+	if !slices.Contains(report.AdaptiveOrdering.Signals, signal) {
+		report.AdaptiveOrdering = &adaptiveOrderingReport{
+			Signals: append(slices.Clone(report.AdaptiveOrdering.Signals), signal),
+		}
+	}
+	return report
 }
 
 func buildLoadBalancingPolicyReport(policy HostSelectionPolicy) any {
@@ -841,6 +863,11 @@ func buildLoadBalancingPolicyReport(policy HostSelectionPolicy) any {
 		// reads.
 		report.AdaptiveOrdering = &adaptiveOrderingReport{Signals: []string{"in-flight-requests"}}
 	}
+	if tap.latencyAware != nil {
+		// A LatencyAwarePolicy fallback moves replicas and fallback hosts
+		// slower than the fastest host last: see demoteReplicas.
+		report = withAdaptiveOrderingSignal(report, "latency")
+	}
 	return report
 }
 
@@ -853,18 +880,25 @@ const maxPolicyUnwrapDepth = 16
 // selection policy so the report describes the policy the caller actually
 // configured rather than the wrapper around it.
 //
-// SingleHostReadyPolicy is the only such wrapper, and it is transparent to
-// routing: it forwards every selection decision to the policy it holds and
-// adds only a readiness signal. Left wrapped, both the load-balancing
-// discriminant and the DC/rack preference behind it would be lost, which is
+// SingleHostReadyPolicy forwards every selection decision to the policy it
+// holds and adds only a readiness signal. LatencyAwarePolicy only reorders the
+// hosts of the policy it holds, which is reported as the "latency"
+// adaptive-ordering signal instead. Left wrapped, both the load-balancing
+// discriminant and the DC/rack preference behind them would be lost, which is
 // everything the group exists to report.
 func unwrapHostSelectionPolicy(p HostSelectionPolicy) HostSelectionPolicy {
 	for range maxPolicyUnwrapDepth {
-		wrapper, ok := p.(*singleHostReadyPolicy)
-		if !ok || isNilPolicy(p) {
+		if isNilPolicy(p) {
+			return p
+		}
+		switch wrapper := p.(type) {
+		case *singleHostReadyPolicy:
+			p = wrapper.HostSelectionPolicy
+		case *latencyAwarePolicy:
+			p = wrapper.HostSelectionPolicy
+		default:
 			return p
 		}
-		p = wrapper.HostSelectionPolicy
 	}
 	return p
 }
@@ -883,10 +917,11 @@ func fallbackToNonPreferredNodesAllowed(tap *tokenAwareHostPolicy) bool {
 	if tap.nonLocalReplicasFallback {
 		return true
 	}
-	if isNilPolicy(tap.fallback) {
+	fallback := unwrapHostSelectionPolicy(tap.fallback)
+	if isNilPolicy(fallback) {
 		return true
 	}
-	switch p := tap.fallback.(type) {
+	switch p := fallback.(type) {
 	case *dcAwareRR:
 		// The preference is the whole local DC, and disabling failover confines
 		// requests to exactly that.
@@ -922,7 +957,7 @@ func buildNodeLocationPreferenceReport(policy HostSelectionPolicy) any {
 	}
 	target := policy
 	if tap, ok := policy.(*tokenAwareHostPolicy); ok {
-		target = tap.fallback
+		target = unwrapHostSelectionPolicy(tap.fallback)
 	}
 	if isNilPolicy(target) {
 		return nil
@@ -25,12 +25,14 @@
 package gocql
 
 import (
+	"fmt"
+	"log/slog"
 	"net"
+	"strconv"
 	"sync"
 	"time"
 
 	"github.com/gocql/gocql/events"
-	"github.com/gocql/gocql/internal/debug"
 	frm "github.com/gocql/gocql/internal/frame"
 )
 
@@ -102,7 +104,8 @@ func (e *eventDebouncer) debounce(frame frame) {
 	if len(e.events) < eventBufferSize {
 		e.events = append(e.events, frame)
 	} else {
-		e.logger.Printf("%s: buffer full, dropping event frame: %s", e.name, frame)
+		slogger(e.logger).Warn("event buffer full, dropping event frame",
+			slog.String("debouncer", e.name), slog.Any("frame", frame))
 	}
 
 	e.mu.Unlock()
@@ -114,14 +117,12 @@ func (s *Session) publishEvent(event events.Event) {
 	}
 
 	if !s.eventBus.PublishEvent(event) {
-		s.logger.Printf("can't publish event, eventbus is full, increase Cluster.EventBusConfig.InputEventsQueueSize; event is dropped")
+		slogger(s.logger).Warn("can't publish event, eventbus is full, increase Cluster.EventBusConfig.InputEventsQueueSize; event is dropped")
 	}
 }
 
 func (s *Session) handleEvent(frame frame) {
-	if debug.Enabled {
-		s.logger.Printf("gocql: handling frame: %v\n", frame)
-	}
+	slogger(s.logger).Debug("handling event frame", slog.Any("frame", frame))
 
 	if event := events.FrameToEvent(frame); event != nil {
 		s.publishEvent(event)
@@ -137,7 +138,7 @@ func (s *Session) handleEvent(frame frame) {
 	case *frm.ClientRoutesChanged:
 		break
 	default:
-		s.logger.Printf("gocql: invalid event frame (%T): %v\n", f, f)
+		slogger(s.logger).Error("invalid event frame", slog.String("type", fmt.Sprintf("%T", f)), slog.Any("frame", f))
 	}
 }
 
@@ -215,9 +216,8 @@ func (s *Session) handleNodeEvent(frames []frame) {
 	}
 
 	for _, f := range sEvents {
-		if debug.Enabled {
-			s.logger.Printf("gocql: dispatching status change event: %+v\n", f)
-		}
+		slogger(s.logger).Debug("dispatching status change event", slog.String("change", f.change),
+			logAddress(net.JoinHostPort(f.host.String(), strconv.Itoa(f.port))))
 
 		// ignore events we received if they were disabled
 		// see https://github.com/apache/cassandra-gocql-driver/issues/1591
@@ -235,9 +235,7 @@ func (s *Session) handleNodeEvent(frames []frame) {
 }
 
 func (s *Session) handleNodeUp(eventIp net.IP, eventPort int) {
-	if debug.Enabled {
-		s.logger.Printf("gocql: Session.handleNodeUp: %s:%d\n", eventIp.String(), eventPort)
-	}
+	slogger(s.logger).Debug("node up", logAddress(net.JoinHostPort(eventIp.String(), strconv.Itoa(eventPort))))
 
 	host, ok := s.hostSource.getHostByIP(eventIp.String())
 	if !ok {
@@ -262,9 +260,7 @@ func (s *Session) startPoolFill(host *HostInfo) {
 }
 
 func (s *Session) handleNodeConnected(host *HostInfo) {
-	if debug.Enabled {
-		s.logger.Printf("gocql: Session.handleNodeConnected: %s:%d\n", host.ConnectAddress(), host.Port())
-	}
+	slogger(s.logger).Debug("node connected", logHostID(host), logAddress(host.ConnectAddressAndPort()))
 
 	host.setState(NodeUp)
 
@@ -274,9 +270,7 @@ func (s *Session) handleNodeConnected(host *HostInfo) {
 }
 
 func (s *Session) handleNodeDown(ip net.IP, port int) {
-	if debug.Enabled {
-		s.logger.Printf("gocql: Session.handleNodeDown: %s:%d\n", ip.String(), port)
-	}
+	slogger(s.logger).Debug("node down", logAddress(net.JoinHostPort(ip.String(), strconv.Itoa(port))))
 
 	host, ok := s.hostSource.getHostByIP(ip.String())
 	if ok {
@@ -91,7 +91,8 @@ func NewSingleHostQueryExecutor(cfg *ClusterConfig) (e SingleHostQueryExecutor,
 	for _, host := range hosts {
 		conn, err = e.control.session.dial(e.control.session.ctx, host, conncfg, e.control)
 		if err != nil {
-			e.control.session.logger.Printf("gocql: unable to dial control conn %v:%v: %v\n", host.ConnectAddress(), host.Port(), err)
+			slogger(e.control.session.logger).Warn("unable to dial control connection",
+				logHostID(host), logAddress(host.ConnectAddressAndPort()), logError(err))
 			continue
 		}
 		err = e.control.setupConn(conn)
@@ -99,7 +100,8 @@ func NewSingleHostQueryExecutor(cfg *ClusterConfig) (e SingleHostQueryExecutor,
 			conn.finalizeConnection()
 			break
 		}
-		e.control.session.logger.Printf("gocql: unable setup control conn %v:%v: %v\n", host.ConnectAddress(), host.Port(), err)
+		slogger(e.control.session.logger).Warn("unable to set up control connection",
+			logHostID(host), logAddress(host.ConnectAddressAndPort()), logError(err))
 		conn.Close()
 		conn = nil
 	}
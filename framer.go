@@ -19,6 +19,8 @@ package gocql
 import (
 	"sync"
 	"sync/atomic"
+
+	"github.com/gocql/gocql/internal/debug"
 )
 
 // framerPool owns one sync.Pool plus the adaptive buffer-sizing state for one
@@ -162,6 +164,9 @@ func (cf *connFramers) releaseRead(c *Conn, f *framer) {
 	f.header = nil
 	f.traceID = nil
 	f.customPayload = nil
+	// Rows read with Iter.ScanRaw or BorrowedUnmarshal refer to this buffer
+	// and must not be read once it can be reused.
+	debug.Poison(f.readBuffer[:cap(f.readBuffer)])
 	if !cf.readPool.enabled.Load() {
 		return
 	}
@@ -0,0 +1,53 @@
+// Package cqlident quotes CQL identifiers.
+package cqlident
+
+import "strings"
+
+// reserved holds the reserved CQL keywords, which must be quoted to be
+// used as identifiers, as listed in appendix A of the CQL reference.
+var reserved = map[string]bool{
+	"add": true, "allow": true, "alter": true, "and": true, "apply": true,
+	"asc": true, "authorize": true, "batch": true, "begin": true, "by": true,
+	"columnfamily": true, "create": true, "default": true, "delete": true,
+	"desc": true, "describe": true, "drop": true, "entries": true,
+	"execute": true, "from": true, "full": true, "grant": true, "if": true,
+	"in": true, "index": true, "infinity": true, "insert": true, "into": true,
+	"is": true, "keyspace": true, "limit": true, "materialized": true,
+	"mbean": true, "mbeans": true, "modify": true, "nan": true,
+	"norecursive": true, "not": true, "null": true, "of": true, "on": true,
+	"or": true, "order": true, "primary": true, "rename": true,
+	"replace": true, "revoke": true, "schema": true, "select": true,
+	"set": true, "table": true, "to": true, "token": true, "truncate": true,
+	"unlogged": true, "unset": true, "update": true, "use": true,
+	"using": true, "view": true, "where": true, "with": true,
+}
+
+// IsUnquoted reports whether name can be used as an identifier without
+// quotes and without being lower-cased by the server.
+func IsUnquoted(name string) bool {
+	if name == "" || name[0] < 'a' || name[0] > 'z' {
+		return false
+	}
+	for i := 1; i < len(name); i++ {
+		c := name[i]
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '_' {
+			return false
+		}
+	}
+	return !reserved[name]
+}
+
+// Quote returns name as a quoted CQL identifier, so that it is used
+// verbatim by the server.
+func Quote(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// QuoteIfNeeded returns name as a CQL identifier, quoting it only if it is
+// not a valid unquoted identifier.
+func QuoteIfNeeded(name string) string {
+	if IsUnquoted(name) {
+		return name
+	}
+	return Quote(name)
+}
@@ -0,0 +1,25 @@
+//go:build unit
+// +build unit
+
+package cqlident
+
+import "testing"
+
+func TestQuoteIfNeeded(t *testing.T) {
+	for name, want := range map[string]string{
+		"user_id":    "user_id",
+		"UserId":     `"UserId"`,
+		"select":     `"select"`,
+		"default":    `"default"`,
+		"1st":        `"1st"`,
+		`we"ird`:     `"we""ird"`,
+		"with space": `"with space"`,
+	} {
+		if got := QuoteIfNeeded(name); got != want {
+			t.Errorf("QuoteIfNeeded(%q): got %s, want %s", name, got, want)
+		}
+	}
+	if got := Quote("user_id"); got != `"user_id"` {
+		t.Errorf("Quote: got %s, want \"user_id\"", got)
+	}
+}
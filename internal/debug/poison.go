@@ -0,0 +1,17 @@
+package debug
+
+// PoisonByte is what Poison overwrites buffers with.
+const PoisonByte = 0xdb
+
+// Poison overwrites b with PoisonByte if Enabled. It is called on buffers
+// that are handed out without copying once they must no longer be read, so
+// that code still holding on to them reads garbage instead of data that
+// merely happens to be intact.
+func Poison(b []byte) {
+	if !Enabled {
+		return
+	}
+	for i := range b {
+		b[i] = PoisonByte
+	}
+}
@@ -0,0 +1,104 @@
+//go:build unit
+// +build unit
+
+package gocql
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/gocql/gocql/internal/debug"
+	"github.com/gocql/gocql/internal/tests/mock"
+)
+
+// wantBorrowedReleased checks that b, borrowed from a previous row, was
+// poisoned if and only if this is a debug build.
+func wantBorrowedReleased(t *testing.T, b, orig []byte) {
+	t.Helper()
+	poisoned := bytes.Equal(b, bytes.Repeat([]byte{debug.PoisonByte}, len(b)))
+	if debug.Enabled && !poisoned {
+		t.Errorf("borrowed %x not poisoned after the iterator advanced", b)
+	} else if !debug.Enabled && !bytes.Equal(b, orig) {
+		t.Errorf("borrowed %x modified outside of debug builds, want %x", b, orig)
+	}
+}
+
+func TestIterScanRaw(t *testing.T) {
+	framer := &mock.MockFramer{Data: [][]byte{
+		marshalTestInt(t, 1), marshalTestInt(t, 2), nil,
+	}}
+	iter := newIntRowsIter(t, framer, 3, nil)
+
+	var got [][]byte
+	var first []byte
+	for iter.ScanRaw(func(cols [][]byte) error {
+		if len(cols) != 1 {
+			t.Fatalf("got %d columns, want 1", len(cols))
+		}
+		if first == nil {
+			first = cols[0]
+		}
+		got = append(got, bytes.Clone(cols[0]))
+		return nil
+	}) {
+	}
+	if err := iter.Close(); err != nil {
+		t.Fatal(err)
+	}
+	want := [][]byte{marshalTestInt(t, 1), marshalTestInt(t, 2), nil}
+	if len(got) != len(want) {
+		t.Fatalf("got %d rows, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if !bytes.Equal(got[i], want[i]) || (got[i] == nil) != (want[i] == nil) {
+			t.Errorf("row %d: got %x, want %x", i, got[i], want[i])
+		}
+	}
+	wantBorrowedReleased(t, first, marshalTestInt(t, 1))
+}
+
+func TestIterScanRawError(t *testing.T) {
+	framer := &mock.MockFramer{Data: [][]byte{marshalTestInt(t, 1), marshalTestInt(t, 2)}}
+	iter := newIntRowsIter(t, framer, 2, nil)
+
+	errStop := errors.New("stop")
+	if iter.ScanRaw(func([][]byte) error { return errStop }) {
+		t.Fatal("expected ScanRaw to fail when fn fails")
+	}
+	if iter.ScanRaw(func([][]byte) error { return nil }) {
+		t.Fatal("expected ScanRaw to fail after an error")
+	}
+	if err := iter.Close(); !errors.Is(err, errStop) {
+		t.Fatalf("got error %v, want %v", err, errStop)
+	}
+}
+
+func TestRowRawAndBorrowedUnmarshal(t *testing.T) {
+	framer := &mock.MockFramer{Data: [][]byte{marshalTestInt(t, 1), marshalTestInt(t, 2)}}
+	iter := newIntRowsIter(t, framer, 2, nil)
+	iter.meta.columns[0].TypeInfo = NativeType{typ: TypeBlob, proto: 4}
+
+	var borrowed []BorrowedUnmarshal
+	for row, err := range iter.Rows() {
+		if err != nil {
+			t.Fatal(err)
+		}
+		raw := row.Raw()
+		if len(raw.Columns) != 1 || len(raw.Values) != 1 {
+			t.Fatalf("got %d columns and %d values, want 1", len(raw.Columns), len(raw.Values))
+		}
+		var b BorrowedUnmarshal
+		if err := row.Scan(&b); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(b, raw.Values[0]) || &b[0] != &raw.Values[0][0] {
+			t.Fatalf("BorrowedUnmarshal %x does not refer to the raw value %x", b, raw.Values[0])
+		}
+		borrowed = append(borrowed, b)
+	}
+	if len(borrowed) != 2 {
+		t.Fatalf("got %d rows, want 2", len(borrowed))
+	}
+	wantBorrowedReleased(t, borrowed[0], marshalTestInt(t, 1))
+}
@@ -24,6 +24,7 @@ package gocql
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/gocql/gocql/internal/tests/mock"
@@ -124,3 +125,163 @@ func TestIterScannerColumnCountChangeAcrossPageTurn(t *testing.T) {
 		t.Fatalf("third row: got d=%d e=%d, want 4 and 5", d, e)
 	}
 }
+
+func newIntRowsIter(t *testing.T, framer framerInterface, numRows int, next *nextIter) *Iter {
+	t.Helper()
+	return &Iter{
+		meta: resultMetadata{
+			columns: []ColumnInfo{
+				{Name: "a", TypeInfo: NativeType{typ: TypeInt, proto: 4}},
+			},
+			actualColCount: 1,
+		},
+		framer:  framer,
+		numRows: numRows,
+		next:    next,
+	}
+}
+
+func marshalTestInt(t *testing.T, v int32) []byte {
+	t.Helper()
+	b, err := Marshal(NativeType{typ: TypeInt, proto: 4}, v)
+	if err != nil {
+		t.Fatalf("unexpected error from reference Marshal: %v", err)
+	}
+	return b
+}
+
+func TestIterRows(t *testing.T) {
+	t.Run("AcrossPages", func(t *testing.T) {
+		nextFramer := &trackingMockFramer{MockFramer: mock.MockFramer{Data: [][]byte{
+			marshalTestInt(t, 3),
+		}}}
+		conn := &pagingTestConn{
+			executeQueryFunc: func(_ context.Context, _ *Query) *Iter {
+				return newIntRowsIter(t, nextFramer, 1, nil)
+			},
+		}
+		baseQry := newWarningTestQuery()
+		baseQry.conn = conn
+
+		firstFramer := &trackingMockFramer{MockFramer: mock.MockFramer{Data: [][]byte{
+			marshalTestInt(t, 1), marshalTestInt(t, 2),
+		}}}
+		iter := newIntRowsIter(t, firstFramer, 2, newNextIter(baseQry, 2))
+
+		var got []int32
+		for row, err := range iter.Rows() {
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var a int32
+			if err := row.Scan(&a); err != nil {
+				t.Fatalf("scan: %v", err)
+			}
+			got = append(got, a)
+		}
+
+		if len(got) != 3 || got[0] != 1 || got[1] != 2 || got[2] != 3 {
+			t.Fatalf("got rows %v, want [1 2 3]", got)
+		}
+		if !firstFramer.released || !nextFramer.released {
+			t.Fatal("expected every page framer to be released")
+		}
+	})
+
+	t.Run("EarlyBreakClosesIter", func(t *testing.T) {
+		framer := &trackingMockFramer{MockFramer: mock.MockFramer{Data: [][]byte{
+			marshalTestInt(t, 1), marshalTestInt(t, 2),
+		}}}
+		iter := newIntRowsIter(t, framer, 2, nil)
+
+		for range iter.Rows() {
+			break
+		}
+
+		if !framer.released {
+			t.Fatal("expected framer to be released after breaking out of the loop")
+		}
+		if iter.Scan(new(int32)) {
+			t.Fatal("expected the iter to be closed after breaking out of the loop")
+		}
+	})
+
+	t.Run("YieldsError", func(t *testing.T) {
+		wantErr := errors.New("query failed")
+		iter := &Iter{err: wantErr}
+
+		var calls int
+		for row, err := range iter.Rows() {
+			calls++
+			if row != nil {
+				t.Fatalf("expected a nil row alongside the error, got %v", row)
+			}
+			if !errors.Is(err, wantErr) {
+				t.Fatalf("got error %v, want %v", err, wantErr)
+			}
+		}
+		if calls != 1 {
+			t.Fatalf("expected the error to be yielded once, got %d calls", calls)
+		}
+	})
+
+	t.Run("NilIter", func(t *testing.T) {
+		var iter *Iter
+		for range iter.Rows() {
+			t.Fatal("expected no rows from a nil iter")
+		}
+	})
+}
+
+func TestIterAll(t *testing.T) {
+	t.Run("FreshValuesPerRow", func(t *testing.T) {
+		framer := &trackingMockFramer{MockFramer: mock.MockFramer{Data: [][]byte{
+			marshalTestInt(t, 1), marshalTestInt(t, 2),
+		}}}
+		iter := newIntRowsIter(t, framer, 2, nil)
+
+		var rows []RowData
+		for row, err := range iter.All() {
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			rows = append(rows, row)
+		}
+
+		if len(rows) != 2 {
+			t.Fatalf("got %d rows, want 2", len(rows))
+		}
+		for i, want := range []int{1, 2} {
+			if len(rows[i].Columns) != 1 || rows[i].Columns[0] != "a" {
+				t.Fatalf("row %d: got columns %v, want [a]", i, rows[i].Columns)
+			}
+			if got := *rows[i].Values[0].(*int); got != want {
+				t.Fatalf("row %d: got %d, want %d", i, got, want)
+			}
+		}
+		if !framer.released {
+			t.Fatal("expected framer to be released")
+		}
+	})
+
+	t.Run("ScanErrorIsYieldedAndRecorded", func(t *testing.T) {
+		framer := &trackingMockFramer{MockFramer: mock.MockFramer{Data: [][]byte{
+			{0x01}, // too short for an int
+		}}}
+		iter := newIntRowsIter(t, framer, 1, nil)
+
+		var gotErr error
+		for _, err := range iter.All() {
+			gotErr = err
+		}
+		if gotErr == nil {
+			t.Fatal("expected an unmarshal error to be yielded")
+		}
+		if err := iter.Close(); err != gotErr {
+			t.Fatalf("expected Close to report the yielded error, got %v", err)
+		}
+		if !framer.released {
+			t.Fatal("expected framer to be released")
+		}
+	})
+}
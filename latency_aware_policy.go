@@ -0,0 +1,377 @@
+package gocql
+
+import (
+	"math"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// LatencyAwarePolicyOptions configures a LatencyAwarePolicy. The zero value
+// of every option selects its default.
+type LatencyAwarePolicyOptions struct {
+	// ExclusionThreshold is how many times slower than the fastest host a
+	// host must be to be demoted. Defaults to 2.
+	ExclusionThreshold float64
+	// Scale is the time over which older measurements lose their weight in
+	// the average latency of a host. Defaults to 100ms.
+	Scale time.Duration
+	// RetryPeriod is how long a demoted host stays demoted without being
+	// measured. A host is only measured when it is queried, so after this
+	// period its average is ignored and it is queried as any other host
+	// again, until new measurements demote it again. Defaults to 10s.
+	RetryPeriod time.Duration
+	// UpdateRate is how often the latency of the fastest host is computed.
+	// Defaults to 100ms.
+	UpdateRate time.Duration
+	// MinMeasurements is the number of measurements of a host needed before
+	// it can be demoted, and before its average is a candidate for the
+	// fastest host. Defaults to 50.
+	MinMeasurements int
+}
+
+// LatencyAwarePolicy wraps a HostSelectionPolicy and moves the hosts it picks
+// whose average latency exceeds ExclusionThreshold times the one of the
+// fastest host after all the other picked hosts, so that a host slowed down by
+// garbage collection or compaction gets less traffic. Demoted hosts are still
+// queried when all the other picked hosts have been tried, and are retried
+// after RetryPeriod.
+//
+// Latencies are measured from the attempts of queries and batches that
+// completed or timed out, as exponentially weighted moving averages for every
+// host and every shard of a host. Session.Diagnostics reports them.
+//
+// To keep routing queries to their replicas, use it as the fallback of
+// TokenAwareHostPolicy:
+//
+//	cluster.PoolConfig.HostSelectionPolicy = gocql.TokenAwareHostPolicy(
+//		gocql.LatencyAwarePolicy(gocql.DCAwareRoundRobinPolicy("dc1"), gocql.LatencyAwarePolicyOptions{}),
+//	)
+//
+// Replicas are then ordered by latency too. The policy must not be shared
+// between sessions.
+func LatencyAwarePolicy(child HostSelectionPolicy, opts LatencyAwarePolicyOptions) HostSelectionPolicy {
+	if opts.ExclusionThreshold <= 0 {
+		opts.ExclusionThreshold = 2
+	}
+	if opts.Scale <= 0 {
+		opts.Scale = 100 * time.Millisecond
+	}
+	if opts.RetryPeriod <= 0 {
+		opts.RetryPeriod = 10 * time.Second
+	}
+	if opts.UpdateRate <= 0 {
+		opts.UpdateRate = 100 * time.Millisecond
+	}
+	if opts.MinMeasurements <= 0 {
+		opts.MinMeasurements = 50
+	}
+	return &latencyAwarePolicy{
+		HostSelectionPolicy: child,
+		opts:                opts,
+		hosts:               make(map[UUID]*hostLatencyAverages),
+	}
+}
+
+type latencyAwarePolicy struct {
+	HostSelectionPolicy
+	opts LatencyAwarePolicyOptions
+
+	mu    sync.RWMutex
+	hosts map[UUID]*hostLatencyAverages
+
+	// best is the average latency of the fastest host in nanoseconds, or 0
+	// if no host has enough recent measurements, computed at most every
+	// UpdateRate, as of the unix nanoseconds in lastUpdate.
+	best       atomic.Int64
+	lastUpdate atomic.Int64
+}
+
+// latencyAverage is an exponentially weighted moving average of latencies.
+type latencyAverage struct {
+	average      float64
+	measurements int
+	last         time.Time
+}
+
+// add adds latency measured at now to the average. The weight of the
+// previous average decreases with the time elapsed since the previous
+// measurement, relative to scale.
+func (a *latencyAverage) add(latency time.Duration, now time.Time, scale time.Duration) {
+	a.measurements++
+	if a.measurements == 1 {
+		a.average = float64(latency)
+		a.last = now
+		return
+	}
+	elapsed := now.Sub(a.last)
+	if elapsed <= 0 {
+		return
+	}
+	scaled := float64(elapsed) / float64(scale)
+	prevWeight := math.Log(scaled+1) / scaled
+	a.average = (1-prevWeight)*float64(latency) + prevWeight*a.average
+	a.last = now
+}
+
+type hostLatencyAverages struct {
+	mu     sync.Mutex
+	host   latencyAverage
+	shards map[int]*latencyAverage
+}
+
+// findLatencyAwarePolicy returns the LatencyAwarePolicy of policy, looking
+// through SingleHostReadyPolicy and the fallback of TokenAwareHostPolicy, or
+// nil if there is none.
+func findLatencyAwarePolicy(policy HostSelectionPolicy) *latencyAwarePolicy {
+	for range maxPolicyUnwrapDepth {
+		if isNilPolicy(policy) {
+			return nil
+		}
+		switch p := policy.(type) {
+		case *latencyAwarePolicy:
+			return p
+		case *singleHostReadyPolicy:
+			policy = p.HostSelectionPolicy
+		case *tokenAwareHostPolicy:
+			policy = p.fallback
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+// observeLatency adds the latency of an attempt on host, and on its shard if
+// shard is not negative. Attempts which failed other than with a timeout are
+// ignored, as their latency is not the one of the host serving a request.
+func (p *latencyAwarePolicy) observeLatency(host *HostInfo, shard int, latency time.Duration, err error) {
+	if err != nil {
+		switch metricsErrorTypes[metricsErrorType(err)] {
+		case MetricsErrorClientTimeout, MetricsErrorReadTimeout, MetricsErrorWriteTimeout:
+		default:
+			return
+		}
+	}
+	p.add(host, shard, latency, time.Now())
+}
+
+func (p *latencyAwarePolicy) add(host *HostInfo, shard int, latency time.Duration, now time.Time) {
+	id := host.hostUUID()
+	p.mu.RLock()
+	h, ok := p.hosts[id]
+	p.mu.RUnlock()
+	if !ok {
+		p.mu.Lock()
+		if h, ok = p.hosts[id]; !ok {
+			h = &hostLatencyAverages{}
+			p.hosts[id] = h
+		}
+		p.mu.Unlock()
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.host.add(latency, now, p.opts.Scale)
+	if shard < 0 {
+		return
+	}
+	if h.shards == nil {
+		h.shards = make(map[int]*latencyAverage)
+	}
+	s, ok := h.shards[shard]
+	if !ok {
+		s = &latencyAverage{}
+		h.shards[shard] = s
+	}
+	s.add(latency, now, p.opts.Scale)
+}
+
+// scored reports whether an average is used to demote hosts at now: it
+// must have enough measurements, the last of which within RetryPeriod.
+func (p *latencyAwarePolicy) scored(a latencyAverage, now time.Time) bool {
+	return a.measurements >= p.opts.MinMeasurements && now.Sub(a.last) <= p.opts.RetryPeriod
+}
+
+// updateBest computes the latency of the fastest host if it was last
+// computed more than UpdateRate before now.
+func (p *latencyAwarePolicy) updateBest(now time.Time) {
+	last := p.lastUpdate.Load()
+	if now.UnixNano()-last < int64(p.opts.UpdateRate) || !p.lastUpdate.CompareAndSwap(last, now.UnixNano()) {
+		return
+	}
+
+	best := math.Inf(1)
+	p.mu.RLock()
+	for _, h := range p.hosts {
+		h.mu.Lock()
+		if p.scored(h.host, now) {
+			best = min(best, h.host.average)
+		}
+		h.mu.Unlock()
+	}
+	p.mu.RUnlock()
+
+	if math.IsInf(best, 1) {
+		best = 0
+	}
+	p.best.Store(int64(best))
+}
+
+// demoted reports whether host is demoted at now.
+func (p *latencyAwarePolicy) demoted(host *HostInfo, now time.Time) bool {
+	best := p.best.Load()
+	if best <= 0 {
+		return false
+	}
+	p.mu.RLock()
+	h, ok := p.hosts[host.hostUUID()]
+	p.mu.RUnlock()
+	if !ok {
+		return false
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return p.scored(h.host, now) && h.host.average > p.opts.ExclusionThreshold*float64(best)
+}
+
+// demoteReplicas moves the demoted hosts of replicas after the other ones,
+// preserving their relative order.
+func (p *latencyAwarePolicy) demoteReplicas(replicas []*HostInfo) {
+	now := time.Now()
+	p.updateBest(now)
+	if p.best.Load() <= 0 || len(replicas) <= 1 {
+		return
+	}
+
+	var buf [9]*HostInfo
+	demoted := buf[:0]
+	n := 0
+	for _, h := range replicas {
+		if p.demoted(h, now) {
+			demoted = append(demoted, h)
+		} else {
+			replicas[n] = h
+			n++
+		}
+	}
+	copy(replicas[n:], demoted)
+}
+
+func (p *latencyAwarePolicy) Pick(qry ExecutableQuery) NextHost {
+	now := time.Now()
+	p.updateBest(now)
+
+	next := p.HostSelectionPolicy.Pick(qry)
+	var (
+		demoted []SelectedHost
+		i       int
+	)
+	return func() SelectedHost {
+		if next != nil {
+			for host := next(); host != nil; host = next() {
+				if p.demoted(host.Info(), now) {
+					demoted = append(demoted, host)
+					continue
+				}
+				return host
+			}
+			next = nil
+		}
+		if i < len(demoted) {
+			i++
+			return demoted[i-1]
+		}
+		return nil
+	}
+}
+
+func (p *latencyAwarePolicy) RemoveHost(host *HostInfo) {
+	p.mu.Lock()
+	delete(p.hosts, host.hostUUID())
+	p.mu.Unlock()
+
+	p.HostSelectionPolicy.RemoveHost(host)
+}
+
+func (p *latencyAwarePolicy) AddHosts(hosts []*HostInfo) {
+	if bulk, ok := p.HostSelectionPolicy.(interface{ AddHosts([]*HostInfo) }); ok {
+		bulk.AddHosts(hosts)
+		return
+	}
+	for _, host := range hosts {
+		p.HostSelectionPolicy.AddHost(host)
+	}
+}
+
+func (p *latencyAwarePolicy) Reset() {
+	p.mu.Lock()
+	clear(p.hosts)
+	p.mu.Unlock()
+	p.best.Store(0)
+	p.lastUpdate.Store(0)
+
+	p.HostSelectionPolicy.Reset()
+}
+
+// HostTier returns the tier of host in the wrapped policy, so that
+// TokenAwareHostPolicy prioritizes replicas the same way with and without
+// this policy.
+func (p *latencyAwarePolicy) HostTier(host *HostInfo) uint {
+	if tierer, ok := p.HostSelectionPolicy.(HostTierer); ok {
+		return tierer.HostTier(host)
+	}
+	if p.HostSelectionPolicy.IsLocal(host) {
+		return 0
+	}
+	return 1
+}
+
+func (p *latencyAwarePolicy) MaxHostTier() uint {
+	if tierer, ok := p.HostSelectionPolicy.(HostTierer); ok {
+		return tierer.MaxHostTier()
+	}
+	return 1
+}
+
+func (p *latencyAwarePolicy) allowsHost(qry ExecutableQuery, host *HostInfo) bool {
+	if filter, ok := p.HostSelectionPolicy.(hostFailoverFilter); ok {
+		return filter.allowsHost(qry, host)
+	}
+	return true
+}
+
+// latencyDiagnostics returns the latency of the hosts measured at now, by
+// host ID.
+func (p *latencyAwarePolicy) latencyDiagnostics(now time.Time) map[UUID]*LatencyDiagnostics {
+	best := p.best.Load()
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	diagnostics := make(map[UUID]*LatencyDiagnostics, len(p.hosts))
+	for id, h := range p.hosts {
+		h.mu.Lock()
+		d := &LatencyDiagnostics{
+			Average:         time.Duration(h.host.average),
+			Measurements:    h.host.measurements,
+			LastMeasurement: h.host.last,
+			Demoted: best > 0 && p.scored(h.host, now) &&
+				h.host.average > p.opts.ExclusionThreshold*float64(best),
+		}
+		for shard, s := range h.shards {
+			d.Shards = append(d.Shards, ShardLatencyDiagnostics{
+				Shard:        shard,
+				Average:      time.Duration(s.average),
+				Measurements: s.measurements,
+			})
+		}
+		h.mu.Unlock()
+		slices.SortFunc(d.Shards, func(a, b ShardLatencyDiagnostics) int {
+			return a.Shard - b.Shard
+		})
+		diagnostics[id] = d
+	}
+	return diagnostics
+}
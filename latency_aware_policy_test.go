@@ -0,0 +1,120 @@
+//go:build unit
+// +build unit
+
+package gocql
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLatencyAwarePolicyDemotesSlowHosts(t *testing.T) {
+	t.Parallel()
+
+	policy := LatencyAwarePolicy(RoundRobinHostPolicy(), LatencyAwarePolicyOptions{
+		MinMeasurements: 2,
+		RetryPeriod:     time.Minute,
+	})
+	p := policy.(*latencyAwarePolicy)
+
+	hosts := make([]*HostInfo, 3)
+	for i := range hosts {
+		hosts[i] = (&HostInfo{hostId: UUID{byte(i + 1)}, connectAddress: []byte{127, 0, 0, byte(i + 1)}}).setState(NodeUp)
+		policy.AddHost(hosts[i])
+	}
+	slow := hosts[1]
+
+	now := time.Now()
+	for i, host := range hosts {
+		latency := time.Millisecond
+		if host == slow {
+			latency = 10 * time.Millisecond
+		}
+		p.add(host, i, latency, now.Add(-2*time.Millisecond))
+		p.add(host, i, latency, now.Add(-time.Millisecond))
+	}
+	// Errors other than timeouts are not measured.
+	p.observeLatency(hosts[0], 0, time.Hour, errors.New("unavailable"))
+
+	for range len(hosts) {
+		iter := policy.Pick(nil)
+		var picked []*HostInfo
+		for host := iter(); host != nil; host = iter() {
+			picked = append(picked, host.Info())
+		}
+		if len(picked) != 3 || picked[2] != slow {
+			t.Fatalf("picked %v, want the slow host %v last", picked, slow)
+		}
+	}
+
+	replicas := []*HostInfo{slow, hosts[0], hosts[2]}
+	p.demoteReplicas(replicas)
+	if replicas[0] != hosts[0] || replicas[1] != hosts[2] || replicas[2] != slow {
+		t.Errorf("replicas = %v, want the slow host last", replicas)
+	}
+
+	if p.demoted(slow, now.Add(2*time.Minute)) {
+		t.Error("slow host is demoted after the retry period")
+	}
+
+	diagnostics := p.latencyDiagnostics(now)
+	d := diagnostics[slow.hostUUID()]
+	if d == nil || !d.Demoted || d.Measurements != 2 || d.Average != 10*time.Millisecond {
+		t.Fatalf("slow host diagnostics = %+v", d)
+	}
+	if len(d.Shards) != 1 || d.Shards[0].Shard != 1 || d.Shards[0].Measurements != 2 {
+		t.Errorf("slow host shards = %+v, want 2 measurements of shard 1", d.Shards)
+	}
+	if d := diagnostics[hosts[0].hostUUID()]; d == nil || d.Demoted || d.Average != time.Millisecond {
+		t.Errorf("fast host diagnostics = %+v", d)
+	}
+
+	policy.RemoveHost(slow)
+	if _, ok := p.latencyDiagnostics(now)[slow.hostUUID()]; ok {
+		t.Error("removed host is still measured")
+	}
+}
+
+func TestLatencyAverage(t *testing.T) {
+	t.Parallel()
+
+	var a latencyAverage
+	start := time.Now()
+	a.add(10*time.Millisecond, start, 100*time.Millisecond)
+	a.add(20*time.Millisecond, start.Add(10*time.Millisecond), 100*time.Millisecond)
+	if a.average <= float64(10*time.Millisecond) || a.average >= float64(15*time.Millisecond) {
+		t.Errorf("average after a close measurement = %v, want it to keep most of its weight", time.Duration(a.average))
+	}
+
+	a.add(20*time.Millisecond, start.Add(time.Hour), 100*time.Millisecond)
+	if a.average < float64(19*time.Millisecond) {
+		t.Errorf("average after a distant measurement = %v, want it close to the measurement", time.Duration(a.average))
+	}
+}
+
+func TestFindLatencyAwarePolicy(t *testing.T) {
+	t.Parallel()
+
+	latencyAware := LatencyAwarePolicy(DCAwareRoundRobinPolicy("dc1"), LatencyAwarePolicyOptions{})
+	tokenAware := TokenAwareHostPolicy(latencyAware)
+	if got := findLatencyAwarePolicy(SingleHostReadyPolicy(tokenAware)); got != latencyAware {
+		t.Fatalf("findLatencyAwarePolicy() = %v, want the fallback of the token aware policy", got)
+	}
+	if got := tokenAware.(*tokenAwareHostPolicy).latencyAware; got != latencyAware {
+		t.Errorf("token aware policy orders replicas with %v, want the latency aware fallback", got)
+	}
+	if got := findLatencyAwarePolicy(TokenAwareHostPolicy(RoundRobinHostPolicy())); got != nil {
+		t.Errorf("findLatencyAwarePolicy() = %v, want nil", got)
+	}
+
+	report := buildLoadBalancingReport(tokenAware)
+	policy, ok := report.Policy.(loadBalancingTokenAwareReport)
+	if !ok || policy.AdaptiveOrdering == nil || len(policy.AdaptiveOrdering.Signals) != 1 ||
+		policy.AdaptiveOrdering.Signals[0] != "latency" {
+		t.Errorf("load balancing policy report = %+v, want the latency signal", report.Policy)
+	}
+	if preference, ok := report.NodePreference.(nodeLocationDCReport); !ok || preference.LocalDC != "dc1" {
+		t.Errorf("node preference = %+v, want dc1", report.NodePreference)
+	}
+}
@@ -28,8 +28,14 @@ import (
 	"bytes"
 	"fmt"
 	"log"
+	"log/slog"
+	"strings"
+
+	"github.com/gocql/gocql/internal/debug"
 )
 
+// StdLogger is the unstructured logger of the driver, see
+// ClusterConfig.Logger and ClusterConfig.StructuredLogger.
 type StdLogger interface {
 	Print(v ...any)
 	Printf(format string, v ...any)
@@ -58,3 +64,106 @@ type defaultLogger struct{}
 func (l *defaultLogger) Print(v ...any)                 { log.Print(v...) }
 func (l *defaultLogger) Printf(format string, v ...any) { log.Printf(format, v...) }
 func (l *defaultLogger) Println(v ...any)               { log.Println(v...) }
+
+// The keys of the attributes of the records logged with
+// ClusterConfig.StructuredLogger.
+const (
+	LogKeyHostID   = "host_id"
+	LogKeyAddress  = "address"
+	LogKeyShard    = "shard"
+	LogKeyKeyspace = "keyspace"
+	LogKeyError    = "error"
+)
+
+func logHostID(host *HostInfo) slog.Attr {
+	return slog.String(LogKeyHostID, host.HostID())
+}
+
+func logAddress(addr string) slog.Attr {
+	return slog.String(LogKeyAddress, addr)
+}
+
+func logShard(shard int) slog.Attr {
+	return slog.Int(LogKeyShard, shard)
+}
+
+func logKeyspace(keyspace string) slog.Attr {
+	return slog.String(LogKeyKeyspace, keyspace)
+}
+
+func logError(err error) slog.Attr {
+	return slog.Any(LogKeyError, err)
+}
+
+// structuredLogger is the StdLogger of a session configured with
+// ClusterConfig.StructuredLogger. Records logged through its StdLogger
+// methods have the info level and no attributes.
+type structuredLogger struct {
+	logger *slog.Logger
+}
+
+func (l structuredLogger) Print(v ...any) {
+	l.logger.Info(strings.TrimSuffix(fmt.Sprint(v...), "\n"))
+}
+
+func (l structuredLogger) Printf(format string, v ...any) {
+	l.logger.Info(strings.TrimSuffix(fmt.Sprintf(format, v...), "\n"))
+}
+
+func (l structuredLogger) Println(v ...any) {
+	l.logger.Info(strings.TrimSuffix(fmt.Sprintln(v...), "\n"))
+}
+
+// textLogger is a StdLogger along with the logger writing records in the
+// text format of slog through it, built once by ClusterConfig.logger so
+// that slogger does not build a handler for every record.
+type textLogger struct {
+	StdLogger
+	logger *slog.Logger
+}
+
+var discardLogger = slog.New(slog.DiscardHandler)
+
+// slogger returns the structured logger of l: the logger of
+// ClusterConfig.StructuredLogger, or a logger writing the records in the
+// text format of slog through l. Debug records are only written through
+// l when the driver is built with the gocql_debug tag.
+func slogger(l StdLogger) *slog.Logger {
+	switch l := l.(type) {
+	case structuredLogger:
+		return l.logger
+	case textLogger:
+		return l.logger
+	case nil, nopLogger, *nopLogger:
+		return discardLogger
+	}
+	return newTextSlogger(l)
+}
+
+func newTextSlogger(l StdLogger) *slog.Logger {
+	level := slog.LevelInfo
+	if debug.Enabled {
+		level = slog.LevelDebug
+	}
+	return slog.New(slog.NewTextHandler(stdLoggerWriter{l}, &slog.HandlerOptions{
+		Level: level,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			// StdLoggers have their own timestamps.
+			if len(groups) == 0 && a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+}
+
+// stdLoggerWriter writes the records formatted by a slog.TextHandler, one
+// per Write, through a StdLogger.
+type stdLoggerWriter struct {
+	logger StdLogger
+}
+
+func (w stdLoggerWriter) Write(p []byte) (int, error) {
+	w.logger.Print("gocql: " + strings.TrimSuffix(string(p), "\n"))
+	return len(p), nil
+}
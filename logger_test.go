@@ -0,0 +1,105 @@
+//go:build unit
+// +build unit
+
+package gocql
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/gocql/gocql/internal/debug"
+)
+
+func TestSloggerStdLogger(t *testing.T) {
+	t.Parallel()
+
+	log := &testLogger{}
+	logger := slogger(log)
+	logger.Debug("debug record")
+	logger.Warn("unable to dial", logAddress("127.0.0.1:9042"), logError(errors.New("refused")))
+
+	want := `gocql: level=WARN msg="unable to dial" address=127.0.0.1:9042 error=refused`
+	if debug.Enabled {
+		want = `gocql: level=DEBUG msg="debug record"` + want
+	}
+	if got := log.String(); got != want {
+		t.Fatalf("logged %q, want %q", got, want)
+	}
+
+	if logger := slogger(nopLogger{}); logger.Enabled(t.Context(), slog.LevelError) {
+		t.Fatal("logger of nopLogger is enabled")
+	}
+}
+
+func TestClusterConfigLogger(t *testing.T) {
+	t.Parallel()
+
+	log := &testLogger{}
+	cfg := NewCluster()
+	cfg.Logger = log
+
+	logger := cfg.logger()
+	if slogger(logger) != slogger(logger) {
+		t.Fatal("slogger() builds a new logger for every call")
+	}
+	logger.Printf("gocql: unstructured %d", 1)
+	slogger(logger).Warn("structured")
+	if want := `gocql: unstructured 1gocql: level=WARN msg=structured`; log.String() != want {
+		t.Fatalf("logged %q, want %q", log.String(), want)
+	}
+
+	host := &HostInfo{hostId: UUID{1}, connectAddress: []byte{127, 0, 0, 1}, port: 9042}
+	pool := newHostConnPool(&Session{logger: logger}, host, 1, "ks")
+	if pool.slogger() != pool.slogger() {
+		t.Fatal("hostConnPool.slogger() builds a new logger for every call")
+	}
+}
+
+func TestClusterConfigStructuredLogger(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	structured := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	cfg := NewCluster()
+	cfg.StructuredLogger = structured
+
+	logger := cfg.logger()
+	if got := slogger(logger); got != structured {
+		t.Fatalf("slogger() = %p, want the structured logger %p", got, structured)
+	}
+
+	logger.Printf("gocql: unstructured %d\n", 1)
+	host := &HostInfo{hostId: UUID{1}, connectAddress: []byte{127, 0, 0, 1}, port: 9042}
+	pool := &hostConnPool{host: host, keyspace: "ks", logger: logger}
+	pool.slogger().Error("failed to connect to host", logError(errors.New("refused")))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("logged %d records, want 2:\n%s", len(lines), buf.String())
+	}
+	var records [2]map[string]any
+	for i, line := range lines {
+		if err := json.Unmarshal([]byte(line), &records[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if records[0]["level"] != "INFO" || records[0]["msg"] != "gocql: unstructured 1" {
+		t.Errorf("unstructured record = %v, want INFO without trailing newline", records[0])
+	}
+	for key, want := range map[string]string{
+		"level":        "ERROR",
+		LogKeyHostID:   host.HostID(),
+		LogKeyAddress:  "127.0.0.1:9042",
+		LogKeyKeyspace: "ks",
+		LogKeyError:    "refused",
+	} {
+		if got := records[1][key]; got != want {
+			t.Errorf("pool record %s = %v, want %q", key, got, want)
+		}
+	}
+}
@@ -118,12 +118,31 @@ func (d *DirectUnmarshal) UnmarshalCQL(_ TypeInfo, data []byte) error {
 	return nil
 }
 
+// BorrowedUnmarshal is like DirectUnmarshal but does not copy the data: it
+// refers to the response buffer the value was read from. When scanning rows
+// it is only valid until the iterator advances to the next row, after which
+// the buffer may be reused, and must be copied to be retained.
+//
+// It is meant for high-throughput readers that process large values, such as
+// blobs, in place. Build with the gocql_debug tag to overwrite borrowed data
+// once it is no longer valid, to catch it being retained.
+type BorrowedUnmarshal []byte
+
+func (d *BorrowedUnmarshal) UnmarshalCQL(_ TypeInfo, data []byte) error {
+	*d = data
+	return nil
+}
+
 // Marshal returns the CQL encoding of the value for the Cassandra
 // internal type described by the info parameter.
 //
 // nil is serialized as CQL null.
 // If value implements Marshaler, its MarshalCQL method is called to marshal the data.
 // If value is a pointer, the pointed-to value is marshaled.
+// The database/sql Null types, such as sql.NullString and sql.Null[T], are
+// marshaled as null unless Valid. Values of other types not supported below
+// are marshaled through their driver.Valuer implementation, if any.
+// Codecs of ClusterConfig.Codecs are only consulted by sessions, not by Marshal.
 //
 // Supported conversions are as follows, other type combinations may be added in the future:
 //
@@ -153,6 +172,8 @@ func (d *DirectUnmarshal) UnmarshalCQL(_ TypeInfo, data []byte) error {
 //	varint                      | big.Int            |
 //	varint                      | string             | value of number in decimal notation
 //	inet                        | net.IP             |
+//	inet                        | netip.Addr         | the zero Addr is null, zones are rejected
+//	inet                        | netip.AddrPort     | the port is not stored
 //	inet                        | string             | IPv4 or IPv6 address string
 //	tuple                       | slice, array       |
 //	tuple                       | struct             | fields are marshaled in order of declaration
@@ -170,17 +191,27 @@ func (d *DirectUnmarshal) UnmarshalCQL(_ TypeInfo, data []byte) error {
 // The marshal/unmarshal error provides a list of supported types when an unsupported type is attempted.
 
 func Marshal(info TypeInfo, value any) ([]byte, error) {
+	return marshal(nil, info, value)
+}
+
+func marshal(codecs *CodecRegistry, info TypeInfo, value any) ([]byte, error) {
 	if info.Version() < protoVersion1 {
 		panic("protocol version not set")
 	}
 
+	if codecs != nil {
+		if m := codecs.marshaler(reflect.TypeOf(value), info.Type()); m != nil {
+			return m(info, value)
+		}
+	}
+
 	if valueRef := reflect.ValueOf(value); valueRef.Kind() == reflect.Ptr {
 		if valueRef.IsNil() {
 			return nil, nil
 		} else if v, ok := value.(Marshaler); ok {
 			return v.MarshalCQL(info)
 		} else {
-			return Marshal(info, valueRef.Elem().Interface())
+			return marshal(codecs, info, valueRef.Elem().Interface())
 		}
 	}
 
@@ -188,6 +219,16 @@ func Marshal(info TypeInfo, value any) ([]byte, error) {
 		return v.MarshalCQL(info)
 	}
 
+	data, err := marshalBuiltin(codecs, info, value)
+	if err != nil {
+		if sqlData, ok, sqlErr := marshalSQL(codecs, info, value); ok {
+			return sqlData, sqlErr
+		}
+	}
+	return data, err
+}
+
+func marshalBuiltin(codecs *CodecRegistry, info TypeInfo, value any) ([]byte, error) {
 	switch info.Type() {
 	case TypeVarchar:
 		return marshalVarchar(value)
@@ -220,9 +261,9 @@ func Marshal(info TypeInfo, value any) ([]byte, error) {
 	case TypeTimestamp:
 		return marshalTimestamp(value)
 	case TypeList, TypeSet:
-		return marshalList(info, value)
+		return marshalList(codecs, info, value)
 	case TypeMap:
-		return marshalMap(info, value)
+		return marshalMap(codecs, info, value)
 	case TypeUUID:
 		return marshalUUID(value)
 	case TypeTimeUUID:
@@ -232,16 +273,16 @@ func Marshal(info TypeInfo, value any) ([]byte, error) {
 	case TypeInet:
 		return marshalInet(value)
 	case TypeTuple:
-		return marshalTuple(info, value)
+		return marshalTuple(codecs, info, value)
 	case TypeUDT:
-		return marshalUDT(info, value)
+		return marshalUDT(codecs, info, value)
 	case TypeDate:
 		return marshalDate(value)
 	case TypeDuration:
 		return marshalDuration(value)
 	case TypeCustom:
 		if vector, ok := info.(VectorType); ok {
-			return marshalVector(vector, value)
+			return marshalVector(codecs, vector, value)
 		}
 	}
 
@@ -257,6 +298,12 @@ func Marshal(info TypeInfo, value any) ([]byte, error) {
 // unmarshal the data.
 // If value is a pointer to pointer, it is set to nil if the CQL value is
 // null. Otherwise, nulls are unmarshalled as zero value.
+// The database/sql Null types, such as sql.NullString and sql.Null[T], are
+// set to their zero value, which is not Valid, if the CQL value is null.
+// Values of other types not supported below are unmarshaled through their
+// sql.Scanner implementation, if any, which is passed the CQL value as one of
+// the driver.Value types, or as a string for uuid, inet, varint and decimal.
+// Codecs of ClusterConfig.Codecs are only consulted by sessions, not by Unmarshal.
 //
 // Supported conversions are as follows, other type combinations may be added in the future:
 //
@@ -281,6 +328,8 @@ func Marshal(info TypeInfo, value any) ([]byte, error) {
 //	uuid, timeuuid                          | *gocql.UUID             |
 //	timeuuid                                | *time.Time              | timestamp of the UUID
 //	inet                                    | *net.IP                 |
+//	inet                                    | *netip.Addr             | IPv4-mapped addresses are unmapped
+//	inet                                    | *netip.AddrPort         | the port is kept
 //	inet                                    | *string                 | IPv4 or IPv6 address string
 //	tuple                                   | *slice, *array          |
 //	tuple                                   | *struct                 | struct fields are set in order of declaration
@@ -291,14 +340,36 @@ func Marshal(info TypeInfo, value any) ([]byte, error) {
 //	date                                    | *string                 | formatted with 2006-01-02 format
 //	duration                                | *gocql.Duration         |
 func Unmarshal(info TypeInfo, data []byte, value any) error {
+	return unmarshal(nil, info, data, value)
+}
+
+func unmarshal(codecs *CodecRegistry, info TypeInfo, data []byte, value any) error {
+	if codecs != nil {
+		if t := reflect.TypeOf(value); t != nil && t.Kind() == reflect.Ptr {
+			if u := codecs.unmarshaler(t.Elem(), info.Type()); u != nil {
+				return u(info, data, value)
+			}
+		}
+	}
+
 	if v, ok := value.(Unmarshaler); ok {
 		return v.UnmarshalCQL(info, data)
 	}
 
 	if isNullableValue(value) {
-		return unmarshalNullable(info, data, value)
+		return unmarshalNullable(codecs, info, data, value)
 	}
 
+	if err := unmarshalBuiltin(codecs, info, data, value); err != nil {
+		if ok, sqlErr := unmarshalSQL(codecs, info, data, value); ok {
+			return sqlErr
+		}
+		return err
+	}
+	return nil
+}
+
+func unmarshalBuiltin(codecs *CodecRegistry, info TypeInfo, data []byte, value any) error {
 	switch info.Type() {
 	case TypeVarchar:
 		return unmarshalVarchar(data, value)
@@ -333,9 +404,9 @@ func Unmarshal(info TypeInfo, data []byte, value any) error {
 	case TypeTimestamp:
 		return unmarshalTimestamp(data, value)
 	case TypeList, TypeSet:
-		return unmarshalList(info, data, value)
+		return unmarshalList(codecs, info, data, value)
 	case TypeMap:
-		return unmarshalMap(info, data, value)
+		return unmarshalMap(codecs, info, data, value)
 	case TypeTimeUUID:
 		return unmarshalTimeUUID(data, value)
 	case TypeUUID:
@@ -343,16 +414,16 @@ func Unmarshal(info TypeInfo, data []byte, value any) error {
 	case TypeInet:
 		return unmarshalInet(data, value)
 	case TypeTuple:
-		return unmarshalTuple(info, data, value)
+		return unmarshalTuple(codecs, info, data, value)
 	case TypeUDT:
-		return unmarshalUDT(info, data, value)
+		return unmarshalUDT(codecs, info, data, value)
 	case TypeDate:
 		return unmarshalDate(data, value)
 	case TypeDuration:
 		return unmarshalDuration(data, value)
 	case TypeCustom:
 		if vector, ok := info.(VectorType); ok {
-			return unmarshalVector(vector, data, value)
+			return unmarshalVector(codecs, vector, data, value)
 		}
 	}
 
@@ -382,7 +453,7 @@ func isNullData(info TypeInfo, data []byte) bool {
 	return data == nil
 }
 
-func unmarshalNullable(info TypeInfo, data []byte, value any) error {
+func unmarshalNullable(codecs *CodecRegistry, info TypeInfo, data []byte, value any) error {
 	valueRef := reflect.ValueOf(value)
 
 	if isNullData(info, data) {
@@ -393,7 +464,7 @@ func unmarshalNullable(info TypeInfo, data []byte, value any) error {
 
 	newValue := reflect.New(valueRef.Type().Elem().Elem())
 	valueRef.Elem().Set(newValue)
-	return Unmarshal(info, data, newValue.Interface())
+	return unmarshal(codecs, info, data, newValue.Interface())
 }
 
 func marshalVarchar(value any) ([]byte, error) {
@@ -708,7 +779,7 @@ func writeCollectionSize(info CollectionType, n int, buf *bytes.Buffer) error {
 	return nil
 }
 
-func marshalList(info TypeInfo, value any) ([]byte, error) {
+func marshalList(codecs *CodecRegistry, info TypeInfo, value any) ([]byte, error) {
 	listInfo, ok := info.(CollectionType)
 	if !ok {
 		return nil, marshalErrorf("marshal: can not marshal non collection type into list")
@@ -737,7 +808,7 @@ func marshalList(info TypeInfo, value any) ([]byte, error) {
 		}
 
 		for i := 0; i < n; i++ {
-			item, err := Marshal(listInfo.Elem, rv.Index(i).Interface())
+			item, err := marshal(codecs, listInfo.Elem, rv.Index(i).Interface())
 			if err != nil {
 				return nil, err
 			}
@@ -760,7 +831,7 @@ func marshalList(info TypeInfo, value any) ([]byte, error) {
 			for i := 0; i < len(keys); i++ {
 				keys[i] = rkeys[i].Interface()
 			}
-			return marshalList(listInfo, keys)
+			return marshalList(codecs, listInfo, keys)
 		}
 	}
 	return nil, marshalErrorf("can not marshal %T into %s", value, info)
@@ -775,7 +846,7 @@ func readCollectionSize(info CollectionType, data []byte) (size, read int, err e
 	return
 }
 
-func unmarshalList(info TypeInfo, data []byte, value any) error {
+func unmarshalList(codecs *CodecRegistry, info TypeInfo, data []byte, value any) error {
 	listInfo, ok := info.(CollectionType)
 	if !ok {
 		return unmarshalErrorf("unmarshal: can not unmarshal none collection type into list")
@@ -852,7 +923,7 @@ func unmarshalList(info TypeInfo, data []byte, value any) error {
 				unmarshalData = data[:m]
 				data = data[m:]
 			}
-			if err := Unmarshal(listInfo.Elem, unmarshalData, rv.Index(i).Addr().Interface()); err != nil {
+			if err := unmarshal(codecs, listInfo.Elem, unmarshalData, rv.Index(i).Addr().Interface()); err != nil {
 				return err
 			}
 		}
@@ -861,7 +932,7 @@ func unmarshalList(info TypeInfo, data []byte, value any) error {
 	return unmarshalErrorf("can not unmarshal %s into %T. Accepted types: *slice, *array, *any.", info, value)
 }
 
-func marshalVector(info VectorType, value any) ([]byte, error) {
+func marshalVector(codecs *CodecRegistry, info VectorType, value any) ([]byte, error) {
 	if value == nil {
 		return nil, nil
 	} else if _, ok := value.(unsetColumn); ok {
@@ -873,7 +944,7 @@ func marshalVector(info VectorType, value any) ([]byte, error) {
 	if info.Dimensions > 0 {
 		switch info.SubType.Type() {
 		case TypeDouble:
-			if v, ok := value.([]float64); ok {
+			if v, ok := value.([]float64); ok && codecs.marshaler(reflect.TypeFor[float64](), TypeDouble) == nil {
 				if v == nil {
 					return nil, nil
 				}
@@ -883,7 +954,7 @@ func marshalVector(info VectorType, value any) ([]byte, error) {
 				return marshalVectorFloat64(info.Dimensions, v)
 			}
 		case TypeFloat:
-			if v, ok := value.([]float32); ok {
+			if v, ok := value.([]float32); ok && codecs.marshaler(reflect.TypeFor[float32](), TypeFloat) == nil {
 				if v == nil {
 					return nil, nil
 				}
@@ -919,7 +990,7 @@ func marshalVector(info VectorType, value any) ([]byte, error) {
 			}
 		}
 		for i := 0; i < n; i++ {
-			item, err := Marshal(info.SubType, rv.Index(i).Interface())
+			item, err := marshal(codecs, info.SubType, rv.Index(i).Interface())
 			if err != nil {
 				return nil, err
 			}
@@ -978,13 +1049,13 @@ func unmarshalVectorFloat32(data []byte, vec []float32) {
 	}
 }
 
-func unmarshalVector(info VectorType, data []byte, value any) error {
+func unmarshalVector(codecs *CodecRegistry, info VectorType, data []byte, value any) error {
 	// Fast paths for *[]float64/*[]float32 — skip reflect/per-element dispatch.
 	// nil/empty and dim=0 fall through to the generic path.
 	if info.Dimensions > 0 && data != nil {
 		switch info.SubType.Type() {
 		case TypeDouble:
-			if dst, ok := value.(*[]float64); ok {
+			if dst, ok := value.(*[]float64); ok && codecs.unmarshaler(reflect.TypeFor[float64](), TypeDouble) == nil {
 				expected := info.Dimensions * 8
 				if len(data) != expected {
 					return unmarshalErrorf("unmarshal vector<double>: expected %d bytes, got %d", expected, len(data))
@@ -995,7 +1066,7 @@ func unmarshalVector(info VectorType, data []byte, value any) error {
 				return nil
 			}
 		case TypeFloat:
-			if dst, ok := value.(*[]float32); ok {
+			if dst, ok := value.(*[]float32); ok && codecs.unmarshaler(reflect.TypeFor[float32](), TypeFloat) == nil {
 				expected := info.Dimensions * 4
 				if len(data) != expected {
 					return unmarshalErrorf("unmarshal vector<float>: expected %d bytes, got %d", expected, len(data))
@@ -1080,7 +1151,7 @@ func unmarshalVector(info VectorType, data []byte, value any) error {
 				unmarshalData = data[:elemSize]
 				data = data[elemSize:]
 			}
-			err := Unmarshal(info.SubType, unmarshalData, rv.Index(i).Addr().Interface())
+			err := unmarshal(codecs, info.SubType, unmarshalData, rv.Index(i).Addr().Interface())
 			if err != nil {
 				return unmarshalErrorf("failed to unmarshal %s into %T: %s", info.SubType, unmarshalData, err.Error())
 			}
@@ -1184,7 +1255,7 @@ func computeUnsignedVIntSize(v uint64) int {
 	return (639 - lead0*9) >> 6
 }
 
-func marshalMap(info TypeInfo, value any) ([]byte, error) {
+func marshalMap(codecs *CodecRegistry, info TypeInfo, value any) ([]byte, error) {
 	mapInfo, ok := info.(CollectionType)
 	if !ok {
 		return nil, marshalErrorf("marshal: can not marshal none collection type into map")
@@ -1216,7 +1287,7 @@ func marshalMap(info TypeInfo, value any) ([]byte, error) {
 
 	keys := rv.MapKeys()
 	for _, key := range keys {
-		item, err := Marshal(mapInfo.Key, key.Interface())
+		item, err := marshal(codecs, mapInfo.Key, key.Interface())
 		if err != nil {
 			return nil, err
 		}
@@ -1230,7 +1301,7 @@ func marshalMap(info TypeInfo, value any) ([]byte, error) {
 		}
 		buf.Write(item)
 
-		item, err = Marshal(mapInfo.Elem, rv.MapIndex(key).Interface())
+		item, err = marshal(codecs, mapInfo.Elem, rv.MapIndex(key).Interface())
 		if err != nil {
 			return nil, err
 		}
@@ -1247,7 +1318,7 @@ func marshalMap(info TypeInfo, value any) ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
-func unmarshalMap(info TypeInfo, data []byte, value any) error {
+func unmarshalMap(codecs *CodecRegistry, info TypeInfo, data []byte, value any) error {
 	mapInfo, ok := info.(CollectionType)
 	if !ok {
 		return unmarshalErrorf("unmarshal: can not unmarshal none collection type into map")
@@ -1316,7 +1387,7 @@ func unmarshalMap(info TypeInfo, data []byte, value any) error {
 			unmarshalData = data[:m]
 			data = data[m:]
 		}
-		if err := Unmarshal(mapInfo.Key, unmarshalData, key.Interface()); err != nil {
+		if err := unmarshal(codecs, mapInfo.Key, unmarshalData, key.Interface()); err != nil {
 			return err
 		}
 
@@ -1336,7 +1407,7 @@ func unmarshalMap(info TypeInfo, data []byte, value any) error {
 			unmarshalData = data[:m]
 			data = data[m:]
 		}
-		if err := Unmarshal(mapInfo.Elem, unmarshalData, val.Interface()); err != nil {
+		if err := unmarshal(codecs, mapInfo.Elem, unmarshalData, val.Interface()); err != nil {
 			return err
 		}
 
@@ -1425,7 +1496,7 @@ func unmarshalInet(data []byte, value any) error {
 	return nil
 }
 
-func marshalTuple(info TypeInfo, value any) ([]byte, error) {
+func marshalTuple(codecs *CodecRegistry, info TypeInfo, value any) ([]byte, error) {
 	tuple := info.(TupleTypeInfo)
 	switch v := value.(type) {
 	case unsetColumn:
@@ -1442,7 +1513,7 @@ func marshalTuple(info TypeInfo, value any) ([]byte, error) {
 				continue
 			}
 
-			data, err := Marshal(tuple.Elems[i], elem)
+			data, err := marshal(codecs, tuple.Elems[i], elem)
 			if err != nil {
 				return nil, err
 			}
@@ -1474,7 +1545,7 @@ func marshalTuple(info TypeInfo, value any) ([]byte, error) {
 				continue
 			}
 
-			data, err := Marshal(elem, field.Interface())
+			data, err := marshal(codecs, elem, field.Interface())
 			if err != nil {
 				return nil, err
 			}
@@ -1500,7 +1571,7 @@ func marshalTuple(info TypeInfo, value any) ([]byte, error) {
 				continue
 			}
 
-			data, err := Marshal(elem, item.Interface())
+			data, err := marshal(codecs, elem, item.Interface())
 			if err != nil {
 				return nil, err
 			}
@@ -1529,7 +1600,7 @@ func readBytes(p []byte) ([]byte, []byte) {
 // currently only support unmarshal into a list of values, this makes it possible
 // to support tuples without changing the query API. In the future this can be extend
 // to allow unmarshalling into custom tuple types.
-func unmarshalTuple(info TypeInfo, data []byte, value any) error {
+func unmarshalTuple(codecs *CodecRegistry, info TypeInfo, data []byte, value any) error {
 	if v, ok := value.(Unmarshaler); ok {
 		return v.UnmarshalCQL(info, data)
 	}
@@ -1543,7 +1614,7 @@ func unmarshalTuple(info TypeInfo, data []byte, value any) error {
 			if len(data) >= 4 {
 				p, data = readBytes(data)
 			}
-			err := Unmarshal(elem, p, v[i])
+			err := unmarshal(codecs, elem, p, v[i])
 			if err != nil {
 				return err
 			}
@@ -1573,11 +1644,18 @@ func unmarshalTuple(info TypeInfo, data []byte, value any) error {
 				p, data = readBytes(data)
 			}
 
+			if u := codecs.unmarshaler(rv.Field(i).Type(), elem.Type()); u != nil {
+				if err := u(elem, p, rv.Field(i).Addr().Interface()); err != nil {
+					return err
+				}
+				continue
+			}
+
 			v, err := elem.NewWithError()
 			if err != nil {
 				return err
 			}
-			if err := Unmarshal(elem, p, v); err != nil {
+			if err := unmarshal(codecs, elem, p, v); err != nil {
 				return err
 			}
 
@@ -1610,11 +1688,18 @@ func unmarshalTuple(info TypeInfo, data []byte, value any) error {
 				p, data = readBytes(data)
 			}
 
+			if u := codecs.unmarshaler(rv.Index(i).Type(), elem.Type()); u != nil {
+				if err := u(elem, p, rv.Index(i).Addr().Interface()); err != nil {
+					return err
+				}
+				continue
+			}
+
 			v, err := elem.NewWithError()
 			if err != nil {
 				return err
 			}
-			if err := Unmarshal(elem, p, v); err != nil {
+			if err := unmarshal(codecs, elem, p, v); err != nil {
 				return err
 			}
 
@@ -1659,7 +1744,7 @@ type UDTUnmarshaler interface {
 	UnmarshalUDT(name string, info TypeInfo, data []byte) error
 }
 
-func marshalUDT(info TypeInfo, value any) ([]byte, error) {
+func marshalUDT(codecs *CodecRegistry, info TypeInfo, value any) ([]byte, error) {
 	udt := info.(UDTTypeInfo)
 
 	switch v := value.(type) {
@@ -1687,7 +1772,7 @@ func marshalUDT(info TypeInfo, value any) ([]byte, error) {
 
 			if ok {
 				var err error
-				data, err = Marshal(e.Type, val)
+				data, err = marshal(codecs, e.Type, val)
 				if err != nil {
 					return nil, err
 				}
@@ -1731,7 +1816,7 @@ func marshalUDT(info TypeInfo, value any) ([]byte, error) {
 		var data []byte
 		if f.IsValid() && f.CanInterface() {
 			var err error
-			data, err = Marshal(e.Type, f.Interface())
+			data, err = marshal(codecs, e.Type, f.Interface())
 			if err != nil {
 				return nil, err
 			}
@@ -1743,7 +1828,7 @@ func marshalUDT(info TypeInfo, value any) ([]byte, error) {
 	return buf, nil
 }
 
-func unmarshalUDT(info TypeInfo, data []byte, value any) error {
+func unmarshalUDT(codecs *CodecRegistry, info TypeInfo, data []byte, value any) error {
 	switch v := value.(type) {
 	case Unmarshaler:
 		return v.UnmarshalCQL(info, data)
@@ -1773,14 +1858,14 @@ func unmarshalUDT(info TypeInfo, data []byte, value any) error {
 				return nil
 			}
 			var m map[string]any
-			if err := unmarshalUDTIntoMap(info.(UDTTypeInfo), data, &m); err != nil {
+			if err := unmarshalUDTIntoMap(codecs, info.(UDTTypeInfo), data, &m); err != nil {
 				return err
 			}
 			*v = m
 			return nil
 		}
 	case *map[string]any:
-		return unmarshalUDTIntoMap(info.(UDTTypeInfo), data, v)
+		return unmarshalUDTIntoMap(codecs, info.(UDTTypeInfo), data, v)
 	}
 
 	rv := reflect.ValueOf(value)
@@ -1838,7 +1923,7 @@ func unmarshalUDT(info TypeInfo, data []byte, value any) error {
 		}
 
 		fk := f.Addr().Interface()
-		if err := Unmarshal(e.Type, p, fk); err != nil {
+		if err := unmarshal(codecs, e.Type, p, fk); err != nil {
 			return err
 		}
 	}
@@ -1847,7 +1932,7 @@ func unmarshalUDT(info TypeInfo, data []byte, value any) error {
 }
 
 // unmarshalUDTIntoMap unmarshals UDT data into a *map[string]any.
-func unmarshalUDTIntoMap(udt UDTTypeInfo, data []byte, dstMap *map[string]any) error {
+func unmarshalUDTIntoMap(codecs *CodecRegistry, udt UDTTypeInfo, data []byte, dstMap *map[string]any) error {
 	if data == nil {
 		*dstMap = nil
 		return nil
@@ -1874,7 +1959,7 @@ func unmarshalUDTIntoMap(udt UDTTypeInfo, data []byte, dstMap *map[string]any) e
 		var p []byte
 		p, data = readBytes(data)
 
-		if err := Unmarshal(e.Type, p, val.Interface()); err != nil {
+		if err := unmarshal(codecs, e.Type, p, val.Interface()); err != nil {
 			return err
 		}
 		m[e.Name] = val.Elem().Interface()
@@ -0,0 +1,136 @@
+package gocql
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"math/big"
+	"reflect"
+	"strings"
+	"time"
+
+	"gopkg.in/inf.v0"
+)
+
+// sqlNullFields returns the value and Valid fields of rv if it is one of the
+// database/sql Null types, such as sql.NullString or sql.Null[T].
+func sqlNullFields(rv reflect.Value) (val, valid reflect.Value, ok bool) {
+	if !rv.IsValid() {
+		return val, valid, false
+	}
+	t := rv.Type()
+	if t.Kind() != reflect.Struct || t.PkgPath() != "database/sql" || !strings.HasPrefix(t.Name(), "Null") ||
+		t.NumField() != 2 || t.Field(1).Name != "Valid" || t.Field(1).Type.Kind() != reflect.Bool {
+		return val, valid, false
+	}
+	return rv.Field(0), rv.Field(1), true
+}
+
+// marshalSQL marshals database/sql Null types and driver.Valuer
+// implementations, which the built-in conversions do not support. It
+// reports whether value is one of them.
+func marshalSQL(codecs *CodecRegistry, info TypeInfo, value any) ([]byte, bool, error) {
+	if val, valid, ok := sqlNullFields(reflect.ValueOf(value)); ok {
+		if !valid.Bool() {
+			return nil, true, nil
+		}
+		data, err := marshal(codecs, info, val.Interface())
+		return data, true, err
+	}
+
+	v, ok := value.(driver.Valuer)
+	if !ok {
+		return nil, false, nil
+	}
+	dv, err := v.Value()
+	if err != nil {
+		return nil, true, marshalErrorf("can not marshal %T into %s: %v", value, info, err)
+	}
+	if dv == nil {
+		return nil, true, nil
+	}
+	if _, ok := dv.(driver.Valuer); ok {
+		// Avoid recursing forever on a Valuer returning itself.
+		return nil, true, marshalErrorf("can not marshal %T into %s: Value returned %T", value, info, dv)
+	}
+	data, err := marshal(codecs, info, dv)
+	return data, true, err
+}
+
+// unmarshalSQL unmarshals into database/sql Null types and sql.Scanner
+// implementations, which the built-in conversions do not support. It
+// reports whether value points to one of them.
+func unmarshalSQL(codecs *CodecRegistry, info TypeInfo, data []byte, value any) (bool, error) {
+	if rv := reflect.ValueOf(value); rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		if val, valid, ok := sqlNullFields(rv.Elem()); ok {
+			if data == nil {
+				rv.Elem().Set(reflect.Zero(rv.Elem().Type()))
+				return true, nil
+			}
+			if err := unmarshal(codecs, info, data, val.Addr().Interface()); err != nil {
+				return true, err
+			}
+			valid.SetBool(true)
+			return true, nil
+		}
+	}
+
+	s, ok := value.(sql.Scanner)
+	if !ok {
+		return false, nil
+	}
+	src, err := scannerValue(info, data)
+	if err != nil {
+		return true, err
+	}
+	if err := s.Scan(src); err != nil {
+		return true, unmarshalErrorf("can not unmarshal %s into %T: %v", info, value, err)
+	}
+	return true, nil
+}
+
+// scannerValue returns data of info as the value passed to sql.Scanner.Scan.
+func scannerValue(info TypeInfo, data []byte) (driver.Value, error) {
+	if data == nil {
+		return nil, nil
+	}
+
+	switch info.Type() {
+	case TypeVarchar, TypeText, TypeAscii, TypeInet, TypeUUID, TypeTimeUUID:
+		var s string
+		err := Unmarshal(info, data, &s)
+		return s, err
+	case TypeBlob:
+		var b []byte
+		err := Unmarshal(info, data, &b)
+		return b, err
+	case TypeBoolean:
+		var b bool
+		err := Unmarshal(info, data, &b)
+		return b, err
+	case TypeTinyInt, TypeSmallInt, TypeInt, TypeBigInt, TypeCounter, TypeTime:
+		var i int64
+		err := Unmarshal(info, data, &i)
+		return i, err
+	case TypeFloat:
+		var f float32
+		err := Unmarshal(info, data, &f)
+		return float64(f), err
+	case TypeDouble:
+		var f float64
+		err := Unmarshal(info, data, &f)
+		return f, err
+	case TypeTimestamp, TypeDate:
+		var t time.Time
+		err := Unmarshal(info, data, &t)
+		return t, err
+	case TypeVarint:
+		var i big.Int
+		err := Unmarshal(info, data, &i)
+		return i.String(), err
+	case TypeDecimal:
+		var d inf.Dec
+		err := Unmarshal(info, data, &d)
+		return d.String(), err
+	}
+	return nil, unmarshalErrorf("can not unmarshal %s into sql.Scanner", info)
+}
@@ -0,0 +1,144 @@
+//go:build unit
+// +build unit
+
+package gocql
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type sqlPoint struct {
+	X, Y int
+}
+
+func (p sqlPoint) Value() (driver.Value, error) {
+	return fmt.Sprintf("%d,%d", p.X, p.Y), nil
+}
+
+func (p *sqlPoint) Scan(src any) error {
+	s, ok := src.(string)
+	if !ok {
+		return fmt.Errorf("unexpected %T", src)
+	}
+	_, err := fmt.Sscanf(s, "%d,%d", &p.X, &p.Y)
+	return err
+}
+
+func TestMarshalSQLNull(t *testing.T) {
+	text := NativeType{proto: protoVersion4, typ: TypeText}
+	bigint := NativeType{proto: protoVersion4, typ: TypeBigInt}
+	int32Type := NativeType{proto: protoVersion4, typ: TypeInt}
+	timestamp := NativeType{proto: protoVersion4, typ: TypeTimestamp}
+	uuidType := NativeType{proto: protoVersion4, typ: TypeUUID}
+	ts := time.UnixMilli(time.Now().UnixMilli()).UTC()
+
+	tests := []struct {
+		info  TypeInfo
+		value any
+		dest  any
+	}{
+		{text, sql.NullString{String: "a", Valid: true}, new(sql.NullString)},
+		{text, sql.NullString{}, new(sql.NullString)},
+		{bigint, sql.NullInt64{Int64: 42, Valid: true}, new(sql.NullInt64)},
+		{int32Type, sql.NullInt32{Int32: 42, Valid: true}, new(sql.NullInt32)},
+		{timestamp, sql.NullTime{Time: ts, Valid: true}, new(sql.NullTime)},
+		{int32Type, sql.Null[int32]{V: 42, Valid: true}, new(sql.Null[int32])},
+		{uuidType, sql.Null[UUID]{V: TimeUUID(), Valid: true}, new(sql.Null[UUID])},
+		{uuidType, sql.Null[UUID]{}, new(sql.Null[UUID])},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%T", test.value), func(t *testing.T) {
+			data, err := Marshal(test.info, test.value)
+			if err != nil {
+				t.Fatalf("marshal: %v", err)
+			}
+			if valid := reflect.ValueOf(test.value).Field(1).Bool(); valid == (data == nil) {
+				t.Fatalf("marshal: got %x for Valid %v", data, valid)
+			}
+			// Start from a Valid destination to check nulls reset it.
+			reflect.ValueOf(test.dest).Elem().Field(1).SetBool(true)
+			if err := Unmarshal(test.info, data, test.dest); err != nil {
+				t.Fatalf("unmarshal: %v", err)
+			}
+			if got := reflect.ValueOf(test.dest).Elem().Interface(); !reflect.DeepEqual(got, test.value) {
+				t.Errorf("got %+v, want %+v", got, test.value)
+			}
+		})
+	}
+}
+
+func TestUnmarshalSQLNullNullable(t *testing.T) {
+	text := NativeType{proto: protoVersion4, typ: TypeText}
+
+	var p *sql.NullString
+	if err := Unmarshal(text, []byte("a"), &p); err != nil {
+		t.Fatal(err)
+	}
+	if p == nil || *p != (sql.NullString{String: "a", Valid: true}) {
+		t.Errorf("got %+v", p)
+	}
+	if err := Unmarshal(text, nil, &p); err != nil {
+		t.Fatal(err)
+	}
+	if p != nil {
+		t.Errorf("expected nil for null, got %+v", p)
+	}
+}
+
+func TestMarshalSQLValuerScanner(t *testing.T) {
+	text := NativeType{proto: protoVersion4, typ: TypeText}
+
+	data, err := Marshal(text, sqlPoint{X: 1, Y: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "1,2" {
+		t.Errorf("marshal: got %q", data)
+	}
+
+	var p sqlPoint
+	if err := Unmarshal(text, data, &p); err != nil {
+		t.Fatal(err)
+	}
+	if p != (sqlPoint{X: 1, Y: 2}) {
+		t.Errorf("unmarshal: got %+v", p)
+	}
+
+	list := CollectionType{NativeType: NativeType{proto: protoVersion4, typ: TypeList}, Elem: text}
+	if err := Unmarshal(list, data, &p); err == nil {
+		t.Error("expected error scanning a list")
+	}
+}
+
+func TestScannerValue(t *testing.T) {
+	tests := []struct {
+		typ   Type
+		value any
+		want  driver.Value
+	}{
+		{TypeSmallInt, int16(7), int64(7)},
+		{TypeFloat, float32(1.5), float64(1.5)},
+		{TypeVarint, int64(12), "12"},
+		{TypeUUID, UUID{1}, UUID{1}.String()},
+	}
+	for _, test := range tests {
+		info := NativeType{proto: protoVersion4, typ: test.typ}
+		data, err := Marshal(info, test.value)
+		if err != nil {
+			t.Fatalf("%s: %v", info, err)
+		}
+		got, err := scannerValue(info, data)
+		if err != nil {
+			t.Fatalf("%s: %v", info, err)
+		}
+		if got != test.want {
+			t.Errorf("%s: got %#v, want %#v", info, got, test.want)
+		}
+	}
+}
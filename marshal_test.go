@@ -34,6 +34,7 @@ import (
 	"math"
 	"math/big"
 	"net"
+	"net/netip"
 	"reflect"
 	"strings"
 	"testing"
@@ -1101,14 +1102,14 @@ func TestUnmarshalVectorZeroDimensions(t *testing.T) {
 
 	t.Run("nil_data", func(t *testing.T) {
 		var result []float32
-		if err := unmarshalVector(info, nil, &result); err != nil {
+		if err := unmarshalVector(nil, info, nil, &result); err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
 	})
 
 	t.Run("empty_data", func(t *testing.T) {
 		var result []float32
-		if err := unmarshalVector(info, []byte{}, &result); err != nil {
+		if err := unmarshalVector(nil, info, []byte{}, &result); err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
 		if result == nil {
@@ -1121,7 +1122,7 @@ func TestUnmarshalVectorZeroDimensions(t *testing.T) {
 
 	t.Run("nonempty_data_errors", func(t *testing.T) {
 		var result []float32
-		err := unmarshalVector(info, []byte{0x01, 0x02}, &result)
+		err := unmarshalVector(nil, info, []byte{0x01, 0x02}, &result)
 		if err == nil {
 			t.Fatal("expected error for non-empty data with 0 dimensions")
 		}
@@ -1132,14 +1133,14 @@ func TestUnmarshalVectorZeroDimensions(t *testing.T) {
 
 	t.Run("empty_data_into_zero_length_array", func(t *testing.T) {
 		var result [0]float32
-		if err := unmarshalVector(info, []byte{}, &result); err != nil {
+		if err := unmarshalVector(nil, info, []byte{}, &result); err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
 	})
 
 	t.Run("empty_data_into_nonzero_length_array_errors", func(t *testing.T) {
 		var result [5]float32
-		err := unmarshalVector(info, []byte{}, &result)
+		err := unmarshalVector(nil, info, []byte{}, &result)
 		if err == nil {
 			t.Fatal("expected error for 0-dimension vector into non-zero-length array")
 		}
@@ -1150,7 +1151,7 @@ func TestUnmarshalVectorZeroDimensions(t *testing.T) {
 
 	t.Run("empty_data_into_interface", func(t *testing.T) {
 		var result any
-		if err := unmarshalVector(info, []byte{}, &result); err != nil {
+		if err := unmarshalVector(nil, info, []byte{}, &result); err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
 	})
@@ -1326,7 +1327,7 @@ func TestUnmarshalListReflect_NegativeSize_ReturnsError(t *testing.T) {
 			t.Fatalf("unmarshalList panicked on negative size instead of returning an error: %v", r)
 		}
 	}()
-	if err := unmarshalList(info, data, &dst); err == nil {
+	if err := unmarshalList(nil, info, data, &dst); err == nil {
 		t.Fatal("expected error for negative list size, got nil")
 	}
 }
@@ -1349,7 +1350,7 @@ func TestUnmarshalListReflect_OversizedCount_RejectedBeforeAlloc(t *testing.T) {
 	data := []byte{0x00, 0x00, 0x00, 0x01}
 
 	var dst Strings
-	err := unmarshalList(info, data, &dst)
+	err := unmarshalList(nil, info, data, &dst)
 	if err == nil {
 		t.Fatal("expected error for oversized list count, got nil")
 	}
@@ -1378,7 +1379,7 @@ func TestUnmarshalMapReflect_OversizedCount_RejectedBeforeAlloc(t *testing.T) {
 	data := []byte{0x00, 0x00, 0x00, 0x01}
 
 	var dst StringMap
-	err := unmarshalMap(info, data, &dst)
+	err := unmarshalMap(nil, info, data, &dst)
 	if err == nil {
 		t.Fatal("expected error for oversized map count, got nil")
 	}
@@ -1389,3 +1390,53 @@ func TestUnmarshalMapReflect_OversizedCount_RejectedBeforeAlloc(t *testing.T) {
 		t.Fatalf("expected dst to remain nil, got %#v", dst)
 	}
 }
+
+func TestMarshalNetipNested(t *testing.T) {
+	inet := NativeType{proto: protoVersion4, typ: TypeInet}
+	addrs := []netip.Addr{netip.MustParseAddr("192.168.0.1"), netip.MustParseAddr("fe80::1")}
+
+	list := CollectionType{NativeType: NativeType{proto: protoVersion4, typ: TypeList}, Elem: inet}
+	data, err := Marshal(list, addrs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var gotList []netip.Addr
+	if err := Unmarshal(list, data, &gotList); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(gotList, addrs) {
+		t.Errorf("list: got %v, want %v", gotList, addrs)
+	}
+
+	type host struct {
+		Addr *netip.Addr `cql:"addr"`
+	}
+	udt := UDTTypeInfo{
+		NativeType: NativeType{proto: protoVersion4, typ: TypeUDT},
+		Name:       "host",
+		Elements:   []UDTField{{Name: "addr", Type: inet}},
+	}
+	data, err = Marshal(udt, host{Addr: &addrs[1]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var gotHost host
+	if err := Unmarshal(udt, data, &gotHost); err != nil {
+		t.Fatal(err)
+	}
+	if gotHost.Addr == nil || *gotHost.Addr != addrs[1] {
+		t.Errorf("udt: got %v, want %v", gotHost.Addr, addrs[1])
+	}
+
+	// Box the type info once so only the decoding is measured.
+	var info TypeInfo = inet
+	data, _ = Marshal(info, addrs[1])
+	var addr netip.Addr
+	if allocs := testing.AllocsPerRun(100, func() {
+		if err := Unmarshal(info, data, &addr); err != nil {
+			t.Fatal(err)
+		}
+	}); allocs != 0 {
+		t.Errorf("unmarshal into netip.Addr allocated %v times", allocs)
+	}
+}
@@ -60,7 +60,7 @@ func TestMarshalVectorFloat64_RoundTrip(t *testing.T) {
 	info := makeDoubleVectorType(dim)
 	vec := []float64{1.1, 2.2, 3.3, 4.4, 5.5}
 
-	data, err := marshalVector(info, vec)
+	data, err := marshalVector(nil, info, vec)
 	if err != nil {
 		t.Fatalf("marshalVector: %v", err)
 	}
@@ -69,7 +69,7 @@ func TestMarshalVectorFloat64_RoundTrip(t *testing.T) {
 	}
 
 	var result []float64
-	if err := unmarshalVector(info, data, &result); err != nil {
+	if err := unmarshalVector(nil, info, data, &result); err != nil {
 		t.Fatalf("unmarshalVector: %v", err)
 	}
 	if !reflect.DeepEqual(vec, result) {
@@ -82,7 +82,7 @@ func TestMarshalVectorFloat32_RoundTrip(t *testing.T) {
 	info := makeFloat32VectorType(dim)
 	vec := []float32{1.1, 2.2, 3.3, 4.4, 5.5}
 
-	data, err := marshalVector(info, vec)
+	data, err := marshalVector(nil, info, vec)
 	if err != nil {
 		t.Fatalf("marshalVector: %v", err)
 	}
@@ -91,7 +91,7 @@ func TestMarshalVectorFloat32_RoundTrip(t *testing.T) {
 	}
 
 	var result []float32
-	if err := unmarshalVector(info, data, &result); err != nil {
+	if err := unmarshalVector(nil, info, data, &result); err != nil {
 		t.Fatalf("unmarshalVector: %v", err)
 	}
 	if !reflect.DeepEqual(vec, result) {
@@ -112,7 +112,7 @@ func TestVectorFloat_ByteCompatibility(t *testing.T) {
 		}
 
 		info := makeDoubleVectorType(dim)
-		data, err := marshalVector(info, vec)
+		data, err := marshalVector(nil, info, vec)
 		if err != nil {
 			t.Fatalf("marshalVector: %v", err)
 		}
@@ -129,7 +129,7 @@ func TestVectorFloat_ByteCompatibility(t *testing.T) {
 		}
 
 		info := makeFloat32VectorType(dim)
-		data, err := marshalVector(info, vec)
+		data, err := marshalVector(nil, info, vec)
 		if err != nil {
 			t.Fatalf("marshalVector: %v", err)
 		}
@@ -150,7 +150,7 @@ func TestVectorFloat_SliceReuse(t *testing.T) {
 
 		// First unmarshal allocates.
 		var result []float64
-		if err := unmarshalVector(info, data, &result); err != nil {
+		if err := unmarshalVector(nil, info, data, &result); err != nil {
 			t.Fatalf("unmarshalVector (first): %v", err)
 		}
 		if len(result) != dim {
@@ -161,7 +161,7 @@ func TestVectorFloat_SliceReuse(t *testing.T) {
 		ptr := &result[0]
 
 		// Second unmarshal should reuse the same backing array.
-		if err := unmarshalVector(info, data, &result); err != nil {
+		if err := unmarshalVector(nil, info, data, &result); err != nil {
 			t.Fatalf("unmarshalVector (second): %v", err)
 		}
 		if &result[0] != ptr {
@@ -177,12 +177,12 @@ func TestVectorFloat_SliceReuse(t *testing.T) {
 		}
 
 		var result []float32
-		if err := unmarshalVector(info, data, &result); err != nil {
+		if err := unmarshalVector(nil, info, data, &result); err != nil {
 			t.Fatalf("unmarshalVector (first): %v", err)
 		}
 		ptr := &result[0]
 
-		if err := unmarshalVector(info, data, &result); err != nil {
+		if err := unmarshalVector(nil, info, data, &result); err != nil {
 			t.Fatalf("unmarshalVector (second): %v", err)
 		}
 		if &result[0] != ptr {
@@ -200,7 +200,7 @@ func TestVectorFloat_SliceReuse(t *testing.T) {
 		// Pre-allocate with excess capacity.
 		result := make([]float64, 0, dim+10)
 		ptr := &result[:1][0] // get pointer to backing array
-		if err := unmarshalVector(info, data, &result); err != nil {
+		if err := unmarshalVector(nil, info, data, &result); err != nil {
 			t.Fatalf("unmarshalVector: %v", err)
 		}
 		if len(result) != dim {
@@ -220,7 +220,7 @@ func TestVectorFloat_SliceReuse(t *testing.T) {
 
 		result := make([]float32, 0, dim+10)
 		ptr := &result[:1][0]
-		if err := unmarshalVector(info, data, &result); err != nil {
+		if err := unmarshalVector(nil, info, data, &result); err != nil {
 			t.Fatalf("unmarshalVector: %v", err)
 		}
 		if len(result) != dim {
@@ -236,7 +236,7 @@ func TestVectorFloat_NilData(t *testing.T) {
 	t.Run("float64_nil_data_nil_dst", func(t *testing.T) {
 		info := makeDoubleVectorType(3)
 		var result []float64
-		if err := unmarshalVector(info, nil, &result); err != nil {
+		if err := unmarshalVector(nil, info, nil, &result); err != nil {
 			t.Fatalf("unmarshalVector: %v", err)
 		}
 		if result != nil {
@@ -246,7 +246,7 @@ func TestVectorFloat_NilData(t *testing.T) {
 	t.Run("float64_nil_data_non_nil_dst", func(t *testing.T) {
 		info := makeDoubleVectorType(3)
 		result := []float64{1, 2, 3}
-		if err := unmarshalVector(info, nil, &result); err != nil {
+		if err := unmarshalVector(nil, info, nil, &result); err != nil {
 			t.Fatalf("unmarshalVector: %v", err)
 		}
 		if result != nil {
@@ -256,7 +256,7 @@ func TestVectorFloat_NilData(t *testing.T) {
 	t.Run("float32_nil_data_nil_dst", func(t *testing.T) {
 		info := makeFloat32VectorType(3)
 		var result []float32
-		if err := unmarshalVector(info, nil, &result); err != nil {
+		if err := unmarshalVector(nil, info, nil, &result); err != nil {
 			t.Fatalf("unmarshalVector: %v", err)
 		}
 		if result != nil {
@@ -266,7 +266,7 @@ func TestVectorFloat_NilData(t *testing.T) {
 	t.Run("float32_nil_data_non_nil_dst", func(t *testing.T) {
 		info := makeFloat32VectorType(3)
 		result := []float32{1, 2, 3}
-		if err := unmarshalVector(info, nil, &result); err != nil {
+		if err := unmarshalVector(nil, info, nil, &result); err != nil {
 			t.Fatalf("unmarshalVector: %v", err)
 		}
 		if result != nil {
@@ -279,7 +279,7 @@ func TestVectorFloat_NilSliceMarshal(t *testing.T) {
 	t.Run("float64_nil_slice", func(t *testing.T) {
 		info := makeDoubleVectorType(3)
 		var vec []float64
-		data, err := marshalVector(info, vec)
+		data, err := marshalVector(nil, info, vec)
 		if err != nil {
 			t.Fatalf("marshalVector: %v", err)
 		}
@@ -290,7 +290,7 @@ func TestVectorFloat_NilSliceMarshal(t *testing.T) {
 	t.Run("float32_nil_slice", func(t *testing.T) {
 		info := makeFloat32VectorType(3)
 		var vec []float32
-		data, err := marshalVector(info, vec)
+		data, err := marshalVector(nil, info, vec)
 		if err != nil {
 			t.Fatalf("marshalVector: %v", err)
 		}
@@ -350,7 +350,7 @@ func TestVectorFloat_DimensionMismatch(t *testing.T) {
 	t.Run("float64_marshal", func(t *testing.T) {
 		info := makeDoubleVectorType(3)
 		vec := []float64{1, 2} // wrong dimension
-		_, err := marshalVector(info, vec)
+		_, err := marshalVector(nil, info, vec)
 		if err == nil {
 			t.Fatal("expected error for dimension mismatch, got nil")
 		}
@@ -358,7 +358,7 @@ func TestVectorFloat_DimensionMismatch(t *testing.T) {
 	t.Run("float32_marshal", func(t *testing.T) {
 		info := makeFloat32VectorType(3)
 		vec := []float32{1, 2} // wrong dimension
-		_, err := marshalVector(info, vec)
+		_, err := marshalVector(nil, info, vec)
 		if err == nil {
 			t.Fatal("expected error for dimension mismatch, got nil")
 		}
@@ -367,7 +367,7 @@ func TestVectorFloat_DimensionMismatch(t *testing.T) {
 		info := makeDoubleVectorType(3)
 		data := make([]byte, 10) // not divisible by 8*3=24
 		var result []float64
-		err := unmarshalVector(info, data, &result)
+		err := unmarshalVector(nil, info, data, &result)
 		if err == nil {
 			t.Fatal("expected error for wrong data length, got nil")
 		}
@@ -376,7 +376,7 @@ func TestVectorFloat_DimensionMismatch(t *testing.T) {
 		info := makeFloat32VectorType(3)
 		data := make([]byte, 10) // not 4*3=12
 		var result []float32
-		err := unmarshalVector(info, data, &result)
+		err := unmarshalVector(nil, info, data, &result)
 		if err == nil {
 			t.Fatal("expected error for wrong data length, got nil")
 		}
@@ -387,7 +387,7 @@ func TestVectorFloat_EmptyVector(t *testing.T) {
 	t.Run("float64_dim0", func(t *testing.T) {
 		info := makeDoubleVectorType(0)
 		vec := []float64{}
-		data, err := marshalVector(info, vec)
+		data, err := marshalVector(nil, info, vec)
 		if err != nil {
 			t.Fatalf("marshalVector: %v", err)
 		}
@@ -399,7 +399,7 @@ func TestVectorFloat_EmptyVector(t *testing.T) {
 		}
 
 		var result []float64
-		if err := unmarshalVector(info, data, &result); err != nil {
+		if err := unmarshalVector(nil, info, data, &result); err != nil {
 			t.Fatalf("unmarshalVector: %v", err)
 		}
 		if len(result) != 0 {
@@ -409,7 +409,7 @@ func TestVectorFloat_EmptyVector(t *testing.T) {
 	t.Run("float32_dim0", func(t *testing.T) {
 		info := makeFloat32VectorType(0)
 		vec := []float32{}
-		data, err := marshalVector(info, vec)
+		data, err := marshalVector(nil, info, vec)
 		if err != nil {
 			t.Fatalf("marshalVector: %v", err)
 		}
@@ -418,7 +418,7 @@ func TestVectorFloat_EmptyVector(t *testing.T) {
 		}
 
 		var result []float32
-		if err := unmarshalVector(info, data, &result); err != nil {
+		if err := unmarshalVector(nil, info, data, &result); err != nil {
 			t.Fatalf("unmarshalVector: %v", err)
 		}
 		if len(result) != 0 {
@@ -439,7 +439,7 @@ func TestVectorFloat_PointerToSlice(t *testing.T) {
 
 		// Verify the data is correct by unmarshaling.
 		var result []float64
-		if err := unmarshalVector(info, data, &result); err != nil {
+		if err := unmarshalVector(nil, info, data, &result); err != nil {
 			t.Fatalf("unmarshalVector: %v", err)
 		}
 		if !reflect.DeepEqual(vec, result) {
@@ -455,7 +455,7 @@ func TestVectorFloat_PointerToSlice(t *testing.T) {
 		}
 
 		var result []float32
-		if err := unmarshalVector(info, data, &result); err != nil {
+		if err := unmarshalVector(nil, info, data, &result); err != nil {
 			t.Fatalf("unmarshalVector: %v", err)
 		}
 		if !reflect.DeepEqual(vec, result) {
@@ -469,13 +469,13 @@ func TestVectorFloat_SpecialValues(t *testing.T) {
 		negZero := math.Float64frombits(0x8000000000000000) // -0.0
 		info := makeDoubleVectorType(5)
 		vec := []float64{math.Inf(1), math.Inf(-1), math.MaxFloat64, math.SmallestNonzeroFloat64, negZero}
-		data, err := marshalVector(info, vec)
+		data, err := marshalVector(nil, info, vec)
 		if err != nil {
 			t.Fatalf("marshalVector: %v", err)
 		}
 
 		var result []float64
-		if err := unmarshalVector(info, data, &result); err != nil {
+		if err := unmarshalVector(nil, info, data, &result); err != nil {
 			t.Fatalf("unmarshalVector: %v", err)
 		}
 		// Compare bit patterns: reflect.DeepEqual uses == for floats, where
@@ -494,13 +494,13 @@ func TestVectorFloat_SpecialValues(t *testing.T) {
 	t.Run("float64_nan", func(t *testing.T) {
 		info := makeDoubleVectorType(1)
 		vec := []float64{math.NaN()}
-		data, err := marshalVector(info, vec)
+		data, err := marshalVector(nil, info, vec)
 		if err != nil {
 			t.Fatalf("marshalVector: %v", err)
 		}
 
 		var result []float64
-		if err := unmarshalVector(info, data, &result); err != nil {
+		if err := unmarshalVector(nil, info, data, &result); err != nil {
 			t.Fatalf("unmarshalVector: %v", err)
 		}
 		if len(result) != 1 || !math.IsNaN(result[0]) {
@@ -511,13 +511,13 @@ func TestVectorFloat_SpecialValues(t *testing.T) {
 		negZero := math.Float32frombits(0x80000000) // -0.0
 		info := makeFloat32VectorType(5)
 		vec := []float32{float32(math.Inf(1)), float32(math.Inf(-1)), math.MaxFloat32, math.SmallestNonzeroFloat32, negZero}
-		data, err := marshalVector(info, vec)
+		data, err := marshalVector(nil, info, vec)
 		if err != nil {
 			t.Fatalf("marshalVector: %v", err)
 		}
 
 		var result []float32
-		if err := unmarshalVector(info, data, &result); err != nil {
+		if err := unmarshalVector(nil, info, data, &result); err != nil {
 			t.Fatalf("unmarshalVector: %v", err)
 		}
 		// Bitwise compare so the negative-zero element is actually verified
@@ -535,13 +535,13 @@ func TestVectorFloat_SpecialValues(t *testing.T) {
 	t.Run("float32_nan", func(t *testing.T) {
 		info := makeFloat32VectorType(1)
 		vec := []float32{float32(math.NaN())}
-		data, err := marshalVector(info, vec)
+		data, err := marshalVector(nil, info, vec)
 		if err != nil {
 			t.Fatalf("marshalVector: %v", err)
 		}
 
 		var result []float32
-		if err := unmarshalVector(info, data, &result); err != nil {
+		if err := unmarshalVector(nil, info, data, &result); err != nil {
 			t.Fatalf("unmarshalVector: %v", err)
 		}
 		if len(result) != 1 || !math.IsNaN(float64(result[0])) {
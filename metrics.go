@@ -0,0 +1,511 @@
+package gocql
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultLatencyBuckets are the default upper bounds of the buckets of the
+// request latency histograms of Session.Metrics.
+var DefaultLatencyBuckets = []time.Duration{
+	time.Millisecond,
+	2 * time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	20 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	200 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2 * time.Second,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+// MetricsConfig enables the built-in metrics of a session, which are read
+// with Session.Metrics and Session.MetricsHandler.
+type MetricsConfig struct {
+	// LatencyBuckets are the upper bounds of the buckets of the request
+	// latency histograms, in increasing order.
+	// Default: DefaultLatencyBuckets
+	LatencyBuckets []time.Duration
+}
+
+// The error types requests are counted by in MetricsSnapshot.Errors.
+const (
+	MetricsErrorClientTimeout = "client_timeout"
+	MetricsErrorCanceled      = "canceled"
+	MetricsErrorConnection    = "connection"
+	MetricsErrorReadTimeout   = "read_timeout"
+	MetricsErrorWriteTimeout  = "write_timeout"
+	MetricsErrorUnavailable   = "unavailable"
+	MetricsErrorOverloaded    = "overloaded"
+	MetricsErrorReadFailure   = "read_failure"
+	MetricsErrorWriteFailure  = "write_failure"
+	MetricsErrorSyntax        = "syntax"
+	MetricsErrorInvalid       = "invalid"
+	MetricsErrorUnauthorized  = "unauthorized"
+	MetricsErrorUnprepared    = "unprepared"
+	MetricsErrorServer        = "server"
+	MetricsErrorOther         = "other"
+)
+
+// metricsErrorTypes indexes the error counters of sessionMetrics.
+var metricsErrorTypes = [...]string{
+	MetricsErrorClientTimeout,
+	MetricsErrorCanceled,
+	MetricsErrorConnection,
+	MetricsErrorReadTimeout,
+	MetricsErrorWriteTimeout,
+	MetricsErrorUnavailable,
+	MetricsErrorOverloaded,
+	MetricsErrorReadFailure,
+	MetricsErrorWriteFailure,
+	MetricsErrorSyntax,
+	MetricsErrorInvalid,
+	MetricsErrorUnauthorized,
+	MetricsErrorUnprepared,
+	MetricsErrorServer,
+	MetricsErrorOther,
+}
+
+// metricsErrorType returns the index in metricsErrorTypes of the type of err.
+func metricsErrorType(err error) int {
+	errorType := func(name string) int {
+		return slices.Index(metricsErrorTypes[:], name)
+	}
+
+	switch {
+	case errors.Is(err, ErrTimeoutNoResponse), errors.Is(err, context.DeadlineExceeded):
+		return errorType(MetricsErrorClientTimeout)
+	case errors.Is(err, context.Canceled):
+		return errorType(MetricsErrorCanceled)
+	case errors.Is(err, ErrConnectionClosed), errors.Is(err, ErrNoStreams):
+		return errorType(MetricsErrorConnection)
+	}
+
+	// Server errors are either frame.ErrorFrame or one of the RequestErr
+	// types embedding it.
+	var errFrame interface{ GetCode() int }
+	if !errors.As(err, &errFrame) {
+		return errorType(MetricsErrorOther)
+	}
+	switch errFrame.GetCode() {
+	case ErrCodeReadTimeout:
+		return errorType(MetricsErrorReadTimeout)
+	case ErrCodeWriteTimeout:
+		return errorType(MetricsErrorWriteTimeout)
+	case ErrCodeUnavailable:
+		return errorType(MetricsErrorUnavailable)
+	case ErrCodeOverloaded, ErrCodeBootstrapping:
+		return errorType(MetricsErrorOverloaded)
+	case ErrCodeReadFailure:
+		return errorType(MetricsErrorReadFailure)
+	case ErrCodeWriteFailure, ErrCodeCDCWriteFailure:
+		return errorType(MetricsErrorWriteFailure)
+	case ErrCodeSyntax:
+		return errorType(MetricsErrorSyntax)
+	case ErrCodeInvalid, ErrCodeConfig, ErrCodeAlreadyExists:
+		return errorType(MetricsErrorInvalid)
+	case ErrCodeUnauthorized, ErrCodeCredentials:
+		return errorType(MetricsErrorUnauthorized)
+	case ErrCodeUnprepared:
+		return errorType(MetricsErrorUnprepared)
+	}
+	return errorType(MetricsErrorServer)
+}
+
+// latencyHistogram counts latencies in the buckets of sessionMetrics.bounds,
+// the last counter being of latencies above every bound.
+type latencyHistogram struct {
+	counts []atomic.Uint64
+	sum    atomic.Int64
+}
+
+func newLatencyHistogram(bounds []time.Duration) *latencyHistogram {
+	return &latencyHistogram{counts: make([]atomic.Uint64, len(bounds)+1)}
+}
+
+func (h *latencyHistogram) observe(bounds []time.Duration, latency time.Duration) {
+	i, _ := slices.BinarySearch(bounds, latency)
+	h.counts[i].Add(1)
+	h.sum.Add(int64(latency))
+}
+
+func (h *latencyHistogram) snapshot(bounds []time.Duration) LatencyHistogram {
+	snapshot := LatencyHistogram{
+		Bounds: bounds,
+		Counts: make([]uint64, len(h.counts)),
+		Sum:    time.Duration(h.sum.Load()),
+	}
+	for i := range h.counts {
+		snapshot.Counts[i] = h.counts[i].Load()
+		snapshot.Count += snapshot.Counts[i]
+	}
+	return snapshot
+}
+
+type hostLatency struct {
+	host    *HostInfo
+	latency *latencyHistogram
+}
+
+// sessionMetrics is the registry of the metrics of a session, set when
+// ClusterConfig.Metrics is.
+type sessionMetrics struct {
+	bounds []time.Duration
+
+	mu        sync.RWMutex
+	hosts     map[UUID]hostLatency
+	keyspaces map[string]*latencyHistogram
+
+	errors                [len(metricsErrorTypes)]atomic.Uint64
+	retries               atomic.Uint64
+	speculativeExecutions atomic.Uint64
+	preparedCacheHits     atomic.Uint64
+	preparedCacheMisses   atomic.Uint64
+}
+
+func newSessionMetrics(cfg *MetricsConfig) *sessionMetrics {
+	if cfg == nil {
+		return nil
+	}
+	bounds := cfg.LatencyBuckets
+	if len(bounds) == 0 {
+		bounds = DefaultLatencyBuckets
+	}
+	return &sessionMetrics{
+		bounds:    slices.Clone(bounds),
+		hosts:     make(map[UUID]hostLatency),
+		keyspaces: make(map[string]*latencyHistogram),
+	}
+}
+
+func (m *sessionMetrics) hostLatency(host *HostInfo) *latencyHistogram {
+	id := host.hostUUID()
+	m.mu.RLock()
+	h, ok := m.hosts[id]
+	m.mu.RUnlock()
+	if ok && h.host == host {
+		return h.latency
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if h, ok = m.hosts[id]; !ok {
+		h.latency = newLatencyHistogram(m.bounds)
+	}
+	// The HostInfo of a host is replaced when it changes address.
+	h.host = host
+	m.hosts[id] = h
+	return h.latency
+}
+
+// removeHost forgets the latency of the host with id, whose pool was
+// removed.
+func (m *sessionMetrics) removeHost(id UUID) {
+	m.mu.Lock()
+	delete(m.hosts, id)
+	m.mu.Unlock()
+}
+
+func (m *sessionMetrics) keyspaceLatency(keyspace string) *latencyHistogram {
+	m.mu.RLock()
+	h, ok := m.keyspaces[keyspace]
+	m.mu.RUnlock()
+	if ok {
+		return h
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if h, ok = m.keyspaces[keyspace]; !ok {
+		h = newLatencyHistogram(m.bounds)
+		m.keyspaces[keyspace] = h
+	}
+	return h
+}
+
+// observeAttempt records an attempt of a request to host.
+func (m *sessionMetrics) observeAttempt(host *HostInfo, keyspace string, latency time.Duration, err error) {
+	m.hostLatency(host).observe(m.bounds, latency)
+	m.keyspaceLatency(keyspace).observe(m.bounds, latency)
+	if err != nil {
+		m.errors[metricsErrorType(err)].Add(1)
+	}
+}
+
+func (m *sessionMetrics) observePrepare(cached bool) {
+	if cached {
+		m.preparedCacheHits.Add(1)
+	} else {
+		m.preparedCacheMisses.Add(1)
+	}
+}
+
+// LatencyHistogram is a histogram of request latencies.
+type LatencyHistogram struct {
+	// Bounds are the upper bounds of the buckets, in increasing order.
+	Bounds []time.Duration
+	// Counts are the number of requests in every bucket: Counts[i] requests
+	// took at most Bounds[i] and more than Bounds[i-1]. The last count, at
+	// index len(Bounds), is of the requests that took more than every bound.
+	Counts []uint64
+	// Count is the number of requests.
+	Count uint64
+	// Sum is the total latency of the requests.
+	Sum time.Duration
+}
+
+// HostMetrics are the metrics of a host.
+type HostMetrics struct {
+	HostID  string
+	Address string
+	// Latency is the latency of the requests to the host.
+	Latency LatencyHistogram
+	// Connections is the number of open connections of the pool of the
+	// host, see HostPoolInfo.
+	Connections int
+	// ExcessConnections is the number of excess shard connections held by
+	// the pool of the host.
+	ExcessConnections int
+	// InFlight is the number of requests in flight on the connections of
+	// the pool of the host.
+	InFlight int
+	// AvailableStreams is the number of streams available for new requests
+	// on the connections of the pool of the host.
+	AvailableStreams int
+}
+
+// KeyspaceMetrics are the metrics of a keyspace.
+type KeyspaceMetrics struct {
+	Keyspace string
+	// Latency is the latency of the requests to the keyspace.
+	Latency LatencyHistogram
+}
+
+// MetricsSnapshot is a snapshot of the metrics of a session. Requests are
+// counted once per attempt, so a request that was retried or executed
+// speculatively is counted once for every host it was sent to.
+type MetricsSnapshot struct {
+	// Hosts are the metrics of the hosts the session has a pool for or sent
+	// requests to, sorted by address. The latency of a host is reset
+	// when its pool is removed, as when it goes down or leaves the cluster.
+	Hosts []HostMetrics
+	// Keyspaces are the metrics of the keyspaces the session sent requests
+	// to, sorted by name.
+	Keyspaces []KeyspaceMetrics
+	// Retries is the number of times the retry policy retried a request.
+	Retries uint64
+	// SpeculativeExecutions is the number of speculative executions started.
+	SpeculativeExecutions uint64
+	// Timeouts is the number of requests that timed out, on the client or
+	// on the server.
+	Timeouts uint64
+	// Errors are the number of requests that failed, by error type, one of
+	// the MetricsError constants.
+	Errors map[string]uint64
+	// PreparedCacheHits and PreparedCacheMisses are the number of statements
+	// that were found and not found in the prepared statement cache.
+	PreparedCacheHits   uint64
+	PreparedCacheMisses uint64
+}
+
+// Metrics returns a snapshot of the metrics of the session, or nil unless
+// ClusterConfig.Metrics is set.
+func (s *Session) Metrics() *MetricsSnapshot {
+	m := s.metrics
+	if m == nil {
+		return nil
+	}
+
+	snapshot := &MetricsSnapshot{
+		Retries:               m.retries.Load(),
+		SpeculativeExecutions: m.speculativeExecutions.Load(),
+		Errors:                make(map[string]uint64),
+		PreparedCacheHits:     m.preparedCacheHits.Load(),
+		PreparedCacheMisses:   m.preparedCacheMisses.Load(),
+	}
+	for i, name := range metricsErrorTypes {
+		if n := m.errors[i].Load(); n > 0 {
+			snapshot.Errors[name] = n
+		}
+	}
+	snapshot.Timeouts = snapshot.Errors[MetricsErrorClientTimeout] +
+		snapshot.Errors[MetricsErrorReadTimeout] + snapshot.Errors[MetricsErrorWriteTimeout]
+
+	hosts := make(map[UUID]*HostMetrics)
+	hostMetrics := func(id UUID, host *HostInfo) *HostMetrics {
+		h, ok := hosts[id]
+		if !ok {
+			h = &HostMetrics{
+				HostID:  host.HostID(),
+				Address: host.ConnectAddressAndPort(),
+				Latency: LatencyHistogram{Bounds: m.bounds, Counts: make([]uint64, len(m.bounds)+1)},
+			}
+			hosts[id] = h
+		}
+		return h
+	}
+	m.mu.RLock()
+	for id, h := range m.hosts {
+		hostMetrics(id, h.host).Latency = h.latency.snapshot(m.bounds)
+	}
+	for keyspace, h := range m.keyspaces {
+		snapshot.Keyspaces = append(snapshot.Keyspaces, KeyspaceMetrics{
+			Keyspace: keyspace,
+			Latency:  h.snapshot(m.bounds),
+		})
+	}
+	m.mu.RUnlock()
+
+	if s.pool != nil {
+		s.pool.mu.RLock()
+		for id, pool := range s.pool.hostConnPools {
+			h := hostMetrics(id, pool.host)
+			h.Connections = pool.Size()
+			h.ExcessConnections = pool.GetExcessConnectionCount()
+			h.InFlight = pool.InFlight()
+			h.AvailableStreams = pool.AvailableStreams()
+		}
+		s.pool.mu.RUnlock()
+	}
+
+	for _, h := range hosts {
+		snapshot.Hosts = append(snapshot.Hosts, *h)
+	}
+	slices.SortFunc(snapshot.Hosts, func(a, b HostMetrics) int {
+		return strings.Compare(a.Address, b.Address)
+	})
+	slices.SortFunc(snapshot.Keyspaces, func(a, b KeyspaceMetrics) int {
+		return strings.Compare(a.Keyspace, b.Keyspace)
+	})
+	return snapshot
+}
+
+// MetricsHandler returns an http.Handler serving the metrics of the session
+// in the Prometheus text exposition format. It responds with 404 Not Found
+// unless ClusterConfig.Metrics is set.
+func (s *Session) MetricsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		snapshot := s.Metrics()
+		if snapshot == nil {
+			http.Error(w, "gocql: metrics are not enabled", http.StatusNotFound)
+			return
+		}
+		var b bytes.Buffer
+		if err := snapshot.writePrometheus(&b); err != nil {
+			http.Error(w, "gocql: writing metrics: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		if _, err := b.WriteTo(w); err != nil {
+			slogger(s.logger).Debug("failed to write metrics response", logError(err))
+		}
+	})
+}
+
+// prometheusWriter writes metrics in the Prometheus text exposition format.
+type prometheusWriter struct {
+	w *bufio.Writer
+}
+
+func (p prometheusWriter) header(name, typ, help string) {
+	fmt.Fprintf(p.w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+var prometheusLabelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// sample writes a sample of name with labels, given as name and value pairs.
+func (p prometheusWriter) sample(name string, value string, labels ...string) {
+	p.w.WriteString(name)
+	for i := 0; i < len(labels); i += 2 {
+		if i == 0 {
+			p.w.WriteByte('{')
+		} else {
+			p.w.WriteByte(',')
+		}
+		fmt.Fprintf(p.w, `%s="%s"`, labels[i], prometheusLabelEscaper.Replace(labels[i+1]))
+	}
+	if len(labels) > 0 {
+		p.w.WriteByte('}')
+	}
+	p.w.WriteByte(' ')
+	p.w.WriteString(value)
+	p.w.WriteByte('\n')
+}
+
+func (p prometheusWriter) counter(name, help string, value uint64) {
+	p.header(name, "counter", help)
+	p.sample(name, strconv.FormatUint(value, 10))
+}
+
+func (p prometheusWriter) histogram(name string, h LatencyHistogram, labels ...string) {
+	var count uint64
+	for i, bound := range h.Bounds {
+		count += h.Counts[i]
+		p.sample(name+"_bucket", strconv.FormatUint(count, 10),
+			slices.Concat(labels, []string{"le", strconv.FormatFloat(bound.Seconds(), 'g', -1, 64)})...)
+	}
+	p.sample(name+"_bucket", strconv.FormatUint(h.Count, 10), slices.Concat(labels, []string{"le", "+Inf"})...)
+	p.sample(name+"_sum", strconv.FormatFloat(h.Sum.Seconds(), 'g', -1, 64), labels...)
+	p.sample(name+"_count", strconv.FormatUint(h.Count, 10), labels...)
+}
+
+func (m *MetricsSnapshot) writePrometheus(w io.Writer) error {
+	p := prometheusWriter{w: bufio.NewWriter(w)}
+
+	p.header("gocql_host_request_duration_seconds", "histogram", "Latency of the requests to a host.")
+	for _, h := range m.Hosts {
+		p.histogram("gocql_host_request_duration_seconds", h.Latency, "host_id", h.HostID, "address", h.Address)
+	}
+	p.header("gocql_keyspace_request_duration_seconds", "histogram", "Latency of the requests to a keyspace.")
+	for _, k := range m.Keyspaces {
+		p.histogram("gocql_keyspace_request_duration_seconds", k.Latency, "keyspace", k.Keyspace)
+	}
+
+	gauges := []struct {
+		name, help string
+		value      func(*HostMetrics) int
+	}{
+		{"gocql_pool_connections", "Number of open connections to a host.",
+			func(h *HostMetrics) int { return h.Connections }},
+		{"gocql_pool_excess_connections", "Number of excess shard connections to a host.",
+			func(h *HostMetrics) int { return h.ExcessConnections }},
+		{"gocql_pool_in_flight_requests", "Number of requests in flight to a host.",
+			func(h *HostMetrics) int { return h.InFlight }},
+		{"gocql_pool_available_streams", "Number of streams available on the connections to a host.",
+			func(h *HostMetrics) int { return h.AvailableStreams }},
+	}
+	for _, g := range gauges {
+		p.header(g.name, "gauge", g.help)
+		for i := range m.Hosts {
+			h := &m.Hosts[i]
+			p.sample(g.name, strconv.Itoa(g.value(h)), "host_id", h.HostID, "address", h.Address)
+		}
+	}
+
+	p.counter("gocql_retries_total", "Number of times a request was retried.", m.Retries)
+	p.counter("gocql_speculative_executions_total", "Number of speculative executions started.", m.SpeculativeExecutions)
+	p.counter("gocql_request_timeouts_total", "Number of requests that timed out.", m.Timeouts)
+	p.header("gocql_request_errors_total", "counter", "Number of requests that failed, by error type.")
+	for _, name := range metricsErrorTypes {
+		p.sample("gocql_request_errors_total", strconv.FormatUint(m.Errors[name], 10), "type", name)
+	}
+	p.counter("gocql_prepared_cache_hits_total", "Number of statements found in the prepared statement cache.", m.PreparedCacheHits)
+	p.counter("gocql_prepared_cache_misses_total", "Number of statements not found in the prepared statement cache.", m.PreparedCacheMisses)
+
+	return p.w.Flush()
+}
@@ -0,0 +1,177 @@
+//go:build unit
+// +build unit
+
+package gocql
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	frm "github.com/gocql/gocql/internal/frame"
+)
+
+func TestMetricsErrorType(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{ErrTimeoutNoResponse, MetricsErrorClientTimeout},
+		{fmt.Errorf("wrapped: %w", context.DeadlineExceeded), MetricsErrorClientTimeout},
+		{context.Canceled, MetricsErrorCanceled},
+		{ErrConnectionClosed, MetricsErrorConnection},
+		{&QueryError{err: ErrNoStreams}, MetricsErrorConnection},
+		{&RequestErrReadTimeout{ErrorFrame: frm.ErrorFrame{Code: ErrCodeReadTimeout}}, MetricsErrorReadTimeout},
+		{&QueryError{err: &RequestErrWriteTimeout{ErrorFrame: frm.ErrorFrame{Code: ErrCodeWriteTimeout}}}, MetricsErrorWriteTimeout},
+		{&RequestErrUnavailable{ErrorFrame: frm.ErrorFrame{Code: ErrCodeUnavailable}}, MetricsErrorUnavailable},
+		{frm.ErrorFrame{Code: ErrCodeOverloaded}, MetricsErrorOverloaded},
+		{&frm.ErrorFrame{Code: ErrCodeSyntax}, MetricsErrorSyntax},
+		{&RequestErrAlreadyExists{ErrorFrame: frm.ErrorFrame{Code: ErrCodeAlreadyExists}}, MetricsErrorInvalid},
+		{&RequestErrUnprepared{ErrorFrame: frm.ErrorFrame{Code: ErrCodeUnprepared}}, MetricsErrorUnprepared},
+		{frm.ErrorFrame{Code: ErrCodeServer}, MetricsErrorServer},
+		{ErrNoConnections, MetricsErrorOther},
+	}
+	for _, tt := range tests {
+		if got := metricsErrorTypes[metricsErrorType(tt.err)]; got != tt.want {
+			t.Errorf("metricsErrorType(%v) = %s, want %s", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestLatencyHistogram(t *testing.T) {
+	t.Parallel()
+
+	bounds := []time.Duration{time.Millisecond, 10 * time.Millisecond}
+	h := newLatencyHistogram(bounds)
+	for _, latency := range []time.Duration{time.Microsecond, time.Millisecond, 2 * time.Millisecond, time.Second} {
+		h.observe(bounds, latency)
+	}
+
+	got := h.snapshot(bounds)
+	if want := []uint64{2, 1, 1}; fmt.Sprint(got.Counts) != fmt.Sprint(want) {
+		t.Errorf("counts = %v, want %v", got.Counts, want)
+	}
+	if got.Count != 4 {
+		t.Errorf("count = %d, want 4", got.Count)
+	}
+	if want := time.Second + 3*time.Millisecond + time.Microsecond; got.Sum != want {
+		t.Errorf("sum = %v, want %v", got.Sum, want)
+	}
+}
+
+func TestSessionMetricsRecordsExecution(t *testing.T) {
+	t.Parallel()
+
+	host := (&HostInfo{hostId: UUID{1}, connectAddress: []byte{127, 0, 0, 1}, port: 9042}).setState(NodeUp)
+	executor := newTestQueryExecutor(host)
+	executor.metrics = newSessionMetrics(&MetricsConfig{})
+	s := &Session{metrics: executor.metrics, pool: executor.pool}
+
+	calls := 0
+	qry := &executorTestQuery{
+		rt:         &fixedRetryPolicy{maxRetries: 1, retryType: Retry},
+		idempotent: true,
+	}
+	qry.executeFunc = func(context.Context, *Conn) *Iter {
+		calls++
+		if calls == 1 {
+			return &Iter{err: &RequestErrReadTimeout{ErrorFrame: frm.ErrorFrame{Code: ErrCodeReadTimeout}}}
+		}
+		return &Iter{}
+	}
+	if _, err := executor.executeQuery(qry, newQueryMetrics()); err != nil {
+		t.Fatal(err)
+	}
+	executor.metrics.observePrepare(false)
+	executor.metrics.observePrepare(true)
+	executor.metrics.observePrepare(true)
+
+	m := s.Metrics()
+	if len(m.Hosts) != 1 {
+		t.Fatalf("hosts = %+v, want 1 host", m.Hosts)
+	}
+	h := m.Hosts[0]
+	if h.HostID != host.HostID() || h.Address != "127.0.0.1:9042" {
+		t.Errorf("host = %s %s, want %s 127.0.0.1:9042", h.HostID, h.Address, host.HostID())
+	}
+	if h.Latency.Count != 2 || h.Connections != 1 {
+		t.Errorf("host latency count = %d connections = %d, want 2 and 1", h.Latency.Count, h.Connections)
+	}
+	if len(m.Keyspaces) != 1 || m.Keyspaces[0].Latency.Count != 2 {
+		t.Errorf("keyspaces = %+v, want 1 keyspace with 2 requests", m.Keyspaces)
+	}
+	if m.Retries != 1 || m.Timeouts != 1 || m.Errors[MetricsErrorReadTimeout] != 1 || len(m.Errors) != 1 {
+		t.Errorf("retries = %d timeouts = %d errors = %v, want 1, 1 and one read timeout",
+			m.Retries, m.Timeouts, m.Errors)
+	}
+	if m.PreparedCacheHits != 2 || m.PreparedCacheMisses != 1 {
+		t.Errorf("prepared cache hits = %d misses = %d, want 2 and 1", m.PreparedCacheHits, m.PreparedCacheMisses)
+	}
+
+	executor.pool.session = s
+	executor.pool.removeHost(host.hostUUID())
+	if m := s.Metrics(); len(m.Hosts) != 0 {
+		t.Errorf("hosts = %+v after the pool of the host was removed, want none", m.Hosts)
+	}
+}
+
+func TestSessionMetricsDisabled(t *testing.T) {
+	t.Parallel()
+
+	s := &Session{}
+	if m := s.Metrics(); m != nil {
+		t.Fatalf("Metrics() = %+v, want nil", m)
+	}
+
+	rec := httptest.NewRecorder()
+	s.MetricsHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+func TestMetricsHandler(t *testing.T) {
+	t.Parallel()
+
+	m := newSessionMetrics(&MetricsConfig{LatencyBuckets: []time.Duration{time.Millisecond, 100 * time.Millisecond}})
+	host := &HostInfo{hostId: UUID{1}, connectAddress: []byte{127, 0, 0, 1}, port: 9042}
+	m.observeAttempt(host, `k"s`, 500*time.Microsecond, nil)
+	m.observeAttempt(host, `k"s`, 50*time.Millisecond, ErrTimeoutNoResponse)
+	m.speculativeExecutions.Add(3)
+	s := &Session{metrics: m}
+
+	rec := httptest.NewRecorder()
+	s.MetricsHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("content type = %q, want Prometheus text format", ct)
+	}
+
+	hostLabels := fmt.Sprintf(`host_id="%s",address="127.0.0.1:9042"`, host.HostID())
+	body := rec.Body.String()
+	for _, want := range []string{
+		"# TYPE gocql_host_request_duration_seconds histogram\n",
+		"gocql_host_request_duration_seconds_bucket{" + hostLabels + `,le="0.001"} 1` + "\n",
+		"gocql_host_request_duration_seconds_bucket{" + hostLabels + `,le="0.1"} 2` + "\n",
+		"gocql_host_request_duration_seconds_bucket{" + hostLabels + `,le="+Inf"} 2` + "\n",
+		"gocql_host_request_duration_seconds_sum{" + hostLabels + "} 0.0505\n",
+		"gocql_host_request_duration_seconds_count{" + hostLabels + "} 2\n",
+		`gocql_keyspace_request_duration_seconds_count{keyspace="k\"s"} 2` + "\n",
+		"gocql_pool_connections{" + hostLabels + "} 0\n",
+		"# TYPE gocql_speculative_executions_total counter\n",
+		"gocql_speculative_executions_total 3\n",
+		"gocql_request_timeouts_total 1\n",
+		`gocql_request_errors_total{type="client_timeout"} 1` + "\n",
+		`gocql_request_errors_total{type="syntax"} 0` + "\n",
+		"gocql_prepared_cache_misses_total 0\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics do not contain %q:\n%s", want, body)
+		}
+	}
+}
@@ -0,0 +1,269 @@
+// Package otelgocql traces the requests of gocql sessions with
+// OpenTelemetry, following the semantic conventions of database client
+// spans:
+//
+//	cluster.RequestTracer = otelgocql.NewRequestTracer()
+//
+// Every request is traced with a span, and every attempt of a request, be it
+// a retry or a speculative execution, with a child span of the request span
+// recording the host and shard it was sent to and the decision of the retry
+// policy.
+package otelgocql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/gocql/gocql"
+)
+
+const instrumentationName = "github.com/gocql/gocql/otelgocql"
+
+// The attributes of attempt spans that have no semantic convention.
+const (
+	AttemptKey       = attribute.Key("gocql.attempt")
+	ExecutionKey     = attribute.Key("gocql.execution")
+	ShardKey         = attribute.Key("gocql.shard")
+	RetryDecisionKey = attribute.Key("gocql.retry.decision")
+)
+
+// Option configures a RequestTracer.
+type Option func(*RequestTracer)
+
+// WithTracerProvider sets the provider of the tracer spans are recorded
+// with. Defaults to the global provider.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(t *RequestTracer) {
+		t.provider = provider
+	}
+}
+
+// WithPropagator propagates the trace context of attempts to the server in
+// the custom payload of requests, with propagator, such as
+// propagation.TraceContext. Custom payloads require protocol v4 or newer.
+func WithPropagator(propagator propagation.TextMapPropagator) Option {
+	return func(t *RequestTracer) {
+		t.propagator = propagator
+	}
+}
+
+// WithQueryText sets whether the statements of requests are recorded, in
+// the db.query.text attribute. Defaults to true, as values are sent as bind
+// markers unless they are part of the statements.
+func WithQueryText(enabled bool) Option {
+	return func(t *RequestTracer) {
+		t.queryText = enabled
+	}
+}
+
+// RequestTracer is a gocql.RequestTracer recording spans with OpenTelemetry.
+type RequestTracer struct {
+	provider   trace.TracerProvider
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+	queryText  bool
+}
+
+// NewRequestTracer returns a RequestTracer configured by opts.
+func NewRequestTracer(opts ...Option) *RequestTracer {
+	t := &RequestTracer{queryText: true}
+	for _, opt := range opts {
+		opt(t)
+	}
+	if t.provider == nil {
+		t.provider = otel.GetTracerProvider()
+	}
+	t.tracer = t.provider.Tracer(instrumentationName, trace.WithSchemaURL(semconv.SchemaURL))
+	return t
+}
+
+// operationName returns the db.operation.name of a request, the first
+// keyword of its statement or BATCH.
+func operationName(info gocql.RequestInfo) string {
+	if info.BatchSize > 0 {
+		return "BATCH"
+	}
+	operation, _, _ := strings.Cut(strings.TrimSpace(info.Statement), " ")
+	return strings.ToUpper(operation)
+}
+
+// spanName returns the name of the spans of a request, formatted as
+// "{db.operation.name} {db.namespace}.{db.collection.name}" with the parts
+// that are known.
+func spanName(operation string, info gocql.RequestInfo) string {
+	target := info.Table
+	if info.Keyspace != "" && target != "" {
+		target = info.Keyspace + "." + target
+	} else if target == "" {
+		target = info.Keyspace
+	}
+	switch {
+	case operation != "" && target != "":
+		return operation + " " + target
+	case operation != "":
+		return operation
+	case target != "":
+		return target
+	}
+	return "cassandra"
+}
+
+// StartRequest implements gocql.RequestTracer.
+func (t *RequestTracer) StartRequest(ctx context.Context, info gocql.RequestInfo) (context.Context, gocql.RequestSpan) {
+	operation := operationName(info)
+	attrs := []attribute.KeyValue{
+		semconv.DBSystemNameCassandra,
+		semconv.CassandraConsistencyLevelKey.String(strings.ToLower(info.Consistency.String())),
+		semconv.CassandraQueryIdempotent(info.Idempotent),
+	}
+	if operation != "" {
+		attrs = append(attrs, semconv.DBOperationName(operation))
+	}
+	if info.Keyspace != "" {
+		attrs = append(attrs, semconv.DBNamespace(info.Keyspace))
+	}
+	if info.Table != "" {
+		attrs = append(attrs, semconv.DBCollectionName(info.Table))
+	}
+	if info.BatchSize > 1 {
+		attrs = append(attrs, semconv.DBOperationBatchSize(info.BatchSize))
+	}
+	if info.PageSize > 0 {
+		attrs = append(attrs, semconv.CassandraPageSize(info.PageSize))
+	}
+	if t.queryText && info.Statement != "" {
+		attrs = append(attrs, semconv.DBQueryText(info.Statement))
+	}
+
+	name := spanName(operation, info)
+	ctx, span := t.tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+	return ctx, &requestSpan{tracer: t.tracer, name: name, span: span}
+}
+
+// InjectPayload implements gocql.PayloadPropagator.
+func (t *RequestTracer) InjectPayload(ctx context.Context, payload map[string][]byte) {
+	if t.propagator != nil {
+		t.propagator.Inject(ctx, payloadCarrier(payload))
+	}
+}
+
+// payloadCarrier is a propagation.TextMapCarrier writing to a custom
+// payload.
+type payloadCarrier map[string][]byte
+
+func (c payloadCarrier) Get(key string) string {
+	return string(c[key])
+}
+
+func (c payloadCarrier) Set(key, value string) {
+	c[key] = []byte(value)
+}
+
+func (c payloadCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+type requestSpan struct {
+	tracer trace.Tracer
+	name   string
+	span   trace.Span
+}
+
+func (s *requestSpan) StartAttempt(ctx context.Context, info gocql.AttemptInfo) (context.Context, gocql.AttemptSpan) {
+	attrs := []attribute.KeyValue{
+		AttemptKey.Int(info.Attempt),
+		ExecutionKey.Int(info.Execution),
+	}
+	if host := info.Host; host != nil {
+		attrs = append(attrs,
+			semconv.ServerAddress(host.ConnectAddress().String()),
+			semconv.ServerPort(host.Port()),
+			semconv.CassandraCoordinatorID(host.HostID()),
+		)
+		if dc := host.DataCenter(); dc != "" {
+			attrs = append(attrs, semconv.CassandraCoordinatorDC(dc))
+		}
+	}
+	if info.Shard >= 0 {
+		attrs = append(attrs, ShardKey.Int(info.Shard))
+	}
+	if info.Execution > 0 {
+		s.span.SetAttributes(semconv.CassandraSpeculativeExecutionCount(info.Execution))
+	}
+
+	ctx, span := s.tracer.Start(ctx, s.name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+	return ctx, attemptSpan{span: span}
+}
+
+func (s *requestSpan) End(err error) {
+	setError(s.span, err)
+	s.span.End()
+}
+
+type attemptSpan struct {
+	span trace.Span
+}
+
+func (s attemptSpan) End(result gocql.AttemptResult) {
+	if result.Err != nil {
+		s.span.SetAttributes(RetryDecisionKey.String(retryDecision(result.Retry)))
+	}
+	setError(s.span, result.Err)
+	s.span.End()
+}
+
+func retryDecision(retry gocql.RetryType) string {
+	switch retry {
+	case gocql.Retry:
+		return "retry"
+	case gocql.RetryNextHost:
+		return "retry_next_host"
+	case gocql.Ignore:
+		return "ignore"
+	case gocql.Rethrow:
+		return "rethrow"
+	}
+	return strconv.Itoa(int(retry))
+}
+
+// setError records err, if not nil, on span. Server errors are described by
+// their error code.
+func setError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	var errFrame interface{ GetCode() int }
+	if errors.As(err, &errFrame) {
+		code := fmt.Sprintf("0x%04x", errFrame.GetCode())
+		span.SetAttributes(semconv.DBResponseStatusCode(code), semconv.ErrorTypeKey.String(code))
+	} else {
+		span.SetAttributes(semconv.ErrorTypeKey.String(errorType(err)))
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// errorType returns the type of the innermost error wrapped by err.
+func errorType(err error) string {
+	for {
+		next := errors.Unwrap(err)
+		if next == nil {
+			return fmt.Sprintf("%T", err)
+		}
+		err = next
+	}
+}
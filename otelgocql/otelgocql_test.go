@@ -0,0 +1,150 @@
+//go:build unit
+// +build unit
+
+package otelgocql
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/gocql/gocql"
+)
+
+func attributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
+
+func TestRequestTracer(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracer := NewRequestTracer(WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))))
+
+	ctx, request := tracer.StartRequest(context.Background(), gocql.RequestInfo{
+		Statement:   "select * from users where id = ?",
+		Keyspace:    "ks",
+		Table:       "users",
+		Consistency: gocql.LocalQuorum,
+		Idempotent:  true,
+		PageSize:    100,
+	})
+	_, attempt := request.StartAttempt(ctx, gocql.AttemptInfo{Shard: 3, Execution: 1})
+	attemptErr := errors.New("connection reset")
+	attempt.End(gocql.AttemptResult{Err: attemptErr, Retry: gocql.RetryNextHost})
+	_, attempt = request.StartAttempt(ctx, gocql.AttemptInfo{Shard: -1, Attempt: 1})
+	attempt.End(gocql.AttemptResult{})
+	request.End(nil)
+
+	spans := recorder.Ended()
+	if len(spans) != 3 {
+		t.Fatalf("recorded %d spans, want 3", len(spans))
+	}
+	failed, succeeded, req := spans[0], spans[1], spans[2]
+
+	if req.Name() != "SELECT ks.users" || req.SpanKind() != trace.SpanKindClient {
+		t.Errorf("request span = %s %s, want SELECT ks.users client", req.Name(), req.SpanKind())
+	}
+	attrs := attributes(req)
+	for key, want := range map[attribute.Key]string{
+		"db.system.name":              "cassandra",
+		"db.operation.name":           "SELECT",
+		"db.namespace":                "ks",
+		"db.collection.name":          "users",
+		"db.query.text":               "select * from users where id = ?",
+		"cassandra.consistency.level": "local_quorum",
+	} {
+		if got := attrs[key].AsString(); got != want {
+			t.Errorf("request span %s = %q, want %q", key, got, want)
+		}
+	}
+	if !attrs["cassandra.query.idempotent"].AsBool() || attrs["cassandra.page.size"].AsInt64() != 100 {
+		t.Errorf("request span attributes = %v", attrs)
+	}
+	if attrs["cassandra.speculative_execution.count"].AsInt64() != 1 {
+		t.Errorf("request span speculative executions = %v, want 1", attrs["cassandra.speculative_execution.count"])
+	}
+	if req.Status().Code != codes.Unset {
+		t.Errorf("request span status = %v, want unset", req.Status())
+	}
+
+	for _, span := range []sdktrace.ReadOnlySpan{failed, succeeded} {
+		if span.Parent().SpanID() != req.SpanContext().SpanID() {
+			t.Errorf("attempt span %s is not a child of the request span", span.Name())
+		}
+	}
+	attrs = attributes(failed)
+	if attrs[ShardKey].AsInt64() != 3 || attrs[ExecutionKey].AsInt64() != 1 || attrs[RetryDecisionKey].AsString() != "retry_next_host" {
+		t.Errorf("failed attempt span attributes = %v", attrs)
+	}
+	if attrs["error.type"].AsString() != "*errors.errorString" || failed.Status().Code != codes.Error {
+		t.Errorf("failed attempt span error = %v %v", attrs["error.type"], failed.Status())
+	}
+	attrs = attributes(succeeded)
+	if _, ok := attrs[ShardKey]; ok || attrs[AttemptKey].AsInt64() != 1 {
+		t.Errorf("succeeded attempt span attributes = %v", attrs)
+	}
+	if _, ok := attrs[RetryDecisionKey]; ok {
+		t.Errorf("succeeded attempt span has a retry decision")
+	}
+}
+
+func TestRequestTracerBatch(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracer := NewRequestTracer(
+		WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))),
+		WithQueryText(false),
+	)
+
+	_, request := tracer.StartRequest(context.Background(), gocql.RequestInfo{
+		Statement: "INSERT INTO t (a) VALUES (?); INSERT INTO t (a) VALUES (?)",
+		BatchSize: 2,
+		Keyspace:  "ks",
+	})
+	request.End(errors.New("batch failed"))
+
+	span := recorder.Ended()[0]
+	attrs := attributes(span)
+	if span.Name() != "BATCH ks" || attrs["db.operation.batch.size"].AsInt64() != 2 {
+		t.Errorf("batch span = %s %v", span.Name(), attrs)
+	}
+	if _, ok := attrs["db.query.text"]; ok {
+		t.Errorf("batch span records the query text")
+	}
+	if span.Status().Code != codes.Error {
+		t.Errorf("batch span status = %v, want error", span.Status())
+	}
+}
+
+func TestRequestTracerInjectPayload(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	payload := map[string][]byte{}
+	NewRequestTracer(WithTracerProvider(provider)).InjectPayload(context.Background(), payload)
+	if len(payload) != 0 {
+		t.Fatalf("payload without propagator = %v, want empty", payload)
+	}
+
+	tracer := NewRequestTracer(WithTracerProvider(provider), WithPropagator(propagation.TraceContext{}))
+	ctx, request := tracer.StartRequest(context.Background(), gocql.RequestInfo{Statement: "SELECT now() FROM system.local"})
+	ctx, attempt := request.StartAttempt(ctx, gocql.AttemptInfo{})
+	tracer.InjectPayload(ctx, payload)
+	attempt.End(gocql.AttemptResult{})
+	request.End(nil)
+
+	got := propagation.TraceContext{}.Extract(context.Background(), payloadCarrier(payload))
+	want := recorder.Ended()[0].SpanContext()
+	if sc := trace.SpanContextFromContext(got); sc.TraceID() != want.TraceID() || sc.SpanID() != want.SpanID() {
+		t.Fatalf("propagated span context = %v, want %v", sc, want)
+	}
+}
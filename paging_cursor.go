@@ -0,0 +1,295 @@
+package gocql
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"slices"
+)
+
+const (
+	pagingCursorVersion = 1
+
+	pagingCursorSigned = 1 << 0
+
+	// pagingCursorHeaderLen is the length of the version, flags, protocol
+	// version and digest that precede the page state in an encoded cursor.
+	pagingCursorHeaderLen = 3 + sha256.Size
+)
+
+// PagingCursorError is returned when a paging cursor can not be decoded or
+// does not match the query it is resumed on.
+type PagingCursorError struct {
+	Reason string
+}
+
+func (e *PagingCursorError) Error() string {
+	return "gocql: invalid paging cursor: " + e.Reason
+}
+
+// PagingCursor is a paging state bound to the statement, bound values and
+// protocol version of the query it was obtained from. It is meant to be
+// handed out to clients as an opaque cursor, for instance over HTTP, and
+// prevents a cursor from being replayed against another query.
+//
+// The digest of an unsigned cursor can be recomputed by anyone knowing the
+// statement and values, so cursors given to untrusted clients should be
+// signed by passing a key to Encode and DecodePagingCursor.
+type PagingCursor struct {
+	pageState []byte
+	digest    [sha256.Size]byte
+	proto     uint8
+}
+
+// NewPagingCursor returns a cursor resuming q from pageState, typically the
+// value of Iter.PageState after reading a page of q.
+//
+// The cursor is bound to the values passed to Query.Bind or Session.Query.
+// Queries bound with a binding callback, such as BindStruct, have no values
+// to bind the cursor to and are rejected.
+func NewPagingCursor(q *Query, pageState []byte) (*PagingCursor, error) {
+	digest, err := q.cursorDigest()
+	if err != nil {
+		return nil, err
+	}
+	return &PagingCursor{
+		pageState: slices.Clone(pageState),
+		digest:    digest,
+		proto:     q.cursorProtoVersion(),
+	}, nil
+}
+
+// PageState returns the paging state held by the cursor.
+func (c *PagingCursor) PageState() []byte {
+	return slices.Clone(c.pageState)
+}
+
+// Encode returns the cursor as an URL-safe string. If key is not empty, the
+// cursor is signed with HMAC-SHA256 and must be decoded with the same key.
+func (c *PagingCursor) Encode(key []byte) string {
+	buf := make([]byte, 0, pagingCursorHeaderLen+len(c.pageState)+sha256.Size)
+	var flags byte
+	if len(key) > 0 {
+		flags |= pagingCursorSigned
+	}
+	buf = append(buf, pagingCursorVersion, flags, c.proto)
+	buf = append(buf, c.digest[:]...)
+	buf = append(buf, c.pageState...)
+	if len(key) > 0 {
+		buf = append(buf, pagingCursorMAC(key, buf)...)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
+
+// DecodePagingCursor decodes a cursor returned by PagingCursor.Encode. key
+// must be the key the cursor was encoded with: signed cursors are rejected
+// when key is empty, and unsigned ones when it is not.
+func DecodePagingCursor(s string, key []byte) (*PagingCursor, error) {
+	buf, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(buf) < pagingCursorHeaderLen {
+		return nil, &PagingCursorError{Reason: "malformed cursor"}
+	}
+	if buf[0] != pagingCursorVersion {
+		return nil, &PagingCursorError{Reason: fmt.Sprintf("unsupported cursor version %d", buf[0])}
+	}
+
+	signed := buf[1]&pagingCursorSigned != 0
+	switch {
+	case signed && len(key) == 0:
+		return nil, &PagingCursorError{Reason: "cursor is signed but no key was given"}
+	case !signed && len(key) > 0:
+		return nil, &PagingCursorError{Reason: "cursor is not signed"}
+	case signed:
+		if len(buf) < pagingCursorHeaderLen+sha256.Size {
+			return nil, &PagingCursorError{Reason: "malformed cursor"}
+		}
+		body := buf[:len(buf)-sha256.Size]
+		if !hmac.Equal(pagingCursorMAC(key, body), buf[len(body):]) {
+			return nil, &PagingCursorError{Reason: "signature mismatch"}
+		}
+		buf = body
+	}
+
+	c := &PagingCursor{proto: buf[2]}
+	copy(c.digest[:], buf[3:pagingCursorHeaderLen])
+	if len(buf) > pagingCursorHeaderLen {
+		c.pageState = slices.Clone(buf[pagingCursorHeaderLen:])
+	}
+	return c, nil
+}
+
+// pagingCursorMAC returns the HMAC-SHA256 of data keyed with key.
+func pagingCursorMAC(key, data []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
+
+// ResumeFrom sets the page state of the query to the one held by c, after
+// checking that c was obtained from a query with the same statement, values
+// and protocol version. The values must therefore be bound before calling
+// ResumeFrom. A mismatching cursor is rejected with a *PagingCursorError and
+// leaves the query unchanged.
+func (q *Query) ResumeFrom(c *PagingCursor) error {
+	if proto := q.cursorProtoVersion(); c.proto != proto {
+		return &PagingCursorError{Reason: fmt.Sprintf("cursor was created with protocol version %d, session uses %d", c.proto, proto)}
+	}
+	digest, err := q.cursorDigest()
+	if err != nil {
+		return err
+	}
+	if !hmac.Equal(digest[:], c.digest[:]) {
+		return &PagingCursorError{Reason: "cursor was created for a different statement or values"}
+	}
+	q.PageState(slices.Clone(c.pageState))
+	return nil
+}
+
+func (q *Query) cursorProtoVersion() uint8 {
+	if q.session == nil {
+		return 0
+	}
+	return uint8(q.session.cfg.ProtoVersion)
+}
+
+// cursorDigest hashes the statement and values of the query.
+func (q *Query) cursorDigest() ([sha256.Size]byte, error) {
+	var digest [sha256.Size]byte
+	if q.binding != nil && len(q.values) == 0 {
+		return digest, errors.New("gocql: paging cursors require values bound with Query.Bind")
+	}
+
+	h := sha256.New()
+	writeCursorString(h, q.stmt)
+	writeCursorUvarint(h, uint64(len(q.values)))
+	for i, v := range q.values {
+		if err := writeCursorValue(h, reflect.ValueOf(v)); err != nil {
+			return digest, fmt.Errorf("gocql: can not bind paging cursor to value %d: %w", i, err)
+		}
+	}
+	h.Sum(digest[:0])
+	return digest, nil
+}
+
+func writeCursorUvarint(w io.Writer, n uint64) {
+	var buf [binary.MaxVarintLen64]byte
+	w.Write(buf[:binary.PutUvarint(buf[:], n)])
+}
+
+func writeCursorString(w io.Writer, s string) {
+	writeCursorUvarint(w, uint64(len(s)))
+	io.WriteString(w, s)
+}
+
+var textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+// writeCursorValue writes an unambiguous encoding of v to w, which does not
+// depend on memory addresses or map iteration order.
+func writeCursorValue(w io.Writer, v reflect.Value) error {
+	if !v.IsValid() {
+		writeCursorString(w, "")
+		return nil
+	}
+	writeCursorString(w, v.Type().String())
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			writeCursorUvarint(w, 0)
+			return nil
+		}
+	}
+	// Types such as time.Time, UUID and net.IP hold state that is not part of
+	// their value, but have a canonical text form.
+	if v.Type().Implements(textMarshalerType) && v.CanInterface() {
+		text, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return err
+		}
+		// Shifted by one like lengths below, to tell nil values apart.
+		writeCursorUvarint(w, uint64(len(text))+1)
+		w.Write(text)
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		writeCursorUvarint(w, 1)
+		return writeCursorValue(w, v.Elem())
+	case reflect.Bool:
+		if v.Bool() {
+			writeCursorUvarint(w, 1)
+		} else {
+			writeCursorUvarint(w, 0)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		writeCursorUvarint(w, uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		writeCursorUvarint(w, v.Uint())
+	case reflect.Float32, reflect.Float64:
+		writeCursorUvarint(w, math.Float64bits(v.Float()))
+	case reflect.Complex64, reflect.Complex128:
+		writeCursorUvarint(w, math.Float64bits(real(v.Complex())))
+		writeCursorUvarint(w, math.Float64bits(imag(v.Complex())))
+	case reflect.String:
+		writeCursorString(w, v.String())
+	case reflect.Slice, reflect.Array:
+		// Lengths are shifted by one to tell nil slices apart.
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			writeCursorUvarint(w, 0)
+			return nil
+		}
+		writeCursorUvarint(w, uint64(v.Len())+1)
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			for i := 0; i < v.Len(); i++ {
+				w.Write([]byte{byte(v.Index(i).Uint())})
+			}
+			return nil
+		}
+		for i := 0; i < v.Len(); i++ {
+			if err := writeCursorValue(w, v.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		if v.IsNil() {
+			writeCursorUvarint(w, 0)
+			return nil
+		}
+		// Encode the entries separately so they can be written in a
+		// deterministic order.
+		entries := make([][]byte, 0, v.Len())
+		for it := v.MapRange(); it.Next(); {
+			var buf bytes.Buffer
+			if err := writeCursorValue(&buf, it.Key()); err != nil {
+				return err
+			}
+			if err := writeCursorValue(&buf, it.Value()); err != nil {
+				return err
+			}
+			entries = append(entries, buf.Bytes())
+		}
+		slices.SortFunc(entries, bytes.Compare)
+		writeCursorUvarint(w, uint64(len(entries))+1)
+		for _, e := range entries {
+			w.Write(e)
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if err := writeCursorValue(w, v.Field(i)); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+//go:build unit
+// +build unit
+
+package gocql
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+func newCursorTestQuery(stmt string, values ...any) *Query {
+	return &Query{
+		session: &Session{cfg: ClusterConfig{ProtoVersion: protoVersion4}},
+		stmt:    stmt,
+		values:  values,
+	}
+}
+
+func TestPagingCursorRoundTrip(t *testing.T) {
+	const stmt = "SELECT v FROM ks.tbl WHERE pk = ? AND ts > ?"
+	ts := time.Now()
+	pageState := []byte{1, 2, 3}
+
+	c, err := NewPagingCursor(newCursorTestQuery(stmt, "a", ts), pageState)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, key := range [][]byte{nil, []byte("secret")} {
+		decoded, err := DecodePagingCursor(c.Encode(key), key)
+		if err != nil {
+			t.Fatalf("unexpected error decoding with key %q: %v", key, err)
+		}
+
+		// The monotonic clock reading of ts is not part of its value.
+		qry := newCursorTestQuery(stmt, "a", ts.Round(0))
+		if err := qry.ResumeFrom(decoded); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !bytes.Equal(qry.pageState, pageState) || !qry.disableAutoPage {
+			t.Fatalf("got page state %v, want %v with auto paging disabled", qry.pageState, pageState)
+		}
+	}
+}
+
+func TestPagingCursorRejected(t *testing.T) {
+	const stmt = "SELECT v FROM ks.tbl WHERE pk = ?"
+	key := []byte("secret")
+	c, err := NewPagingCursor(newCursorTestQuery(stmt, map[string]int{"a": 1, "b": 2}), []byte{1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	signed := c.Encode(key)
+
+	tampered := []byte(signed)
+	tampered[len(tampered)/2] ^= 1
+
+	for name, s := range map[string]string{
+		"Malformed": "!!",
+		"Tampered":  string(tampered),
+	} {
+		t.Run(name, func(t *testing.T) {
+			var cursorErr *PagingCursorError
+			if _, err := DecodePagingCursor(s, key); !errors.As(err, &cursorErr) {
+				t.Fatalf("expected a PagingCursorError, got %v", err)
+			}
+		})
+	}
+
+	t.Run("WrongKey", func(t *testing.T) {
+		var cursorErr *PagingCursorError
+		if _, err := DecodePagingCursor(signed, []byte("other")); !errors.As(err, &cursorErr) {
+			t.Fatalf("expected a PagingCursorError, got %v", err)
+		}
+		if _, err := DecodePagingCursor(signed, nil); !errors.As(err, &cursorErr) {
+			t.Fatalf("expected a PagingCursorError for a signed cursor without key, got %v", err)
+		}
+		if _, err := DecodePagingCursor(c.Encode(nil), key); !errors.As(err, &cursorErr) {
+			t.Fatalf("expected a PagingCursorError for an unsigned cursor with a key, got %v", err)
+		}
+	})
+
+	for name, qry := range map[string]*Query{
+		"Statement": newCursorTestQuery("SELECT w FROM ks.tbl WHERE pk = ?", map[string]int{"a": 1, "b": 2}),
+		"Values":    newCursorTestQuery(stmt, map[string]int{"a": 1, "b": 3}),
+		"ValueType": newCursorTestQuery(stmt, map[string]int64{"a": 1, "b": 2}),
+		"Protocol": {
+			session: &Session{cfg: ClusterConfig{ProtoVersion: protoVersion3}},
+			stmt:    stmt,
+			values:  []any{map[string]int{"a": 1, "b": 2}},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var cursorErr *PagingCursorError
+			if err := qry.ResumeFrom(c); !errors.As(err, &cursorErr) {
+				t.Fatalf("expected a PagingCursorError, got %v", err)
+			}
+			if qry.pageState != nil {
+				t.Fatal("expected the query to be left unchanged")
+			}
+		})
+	}
+}
+
+func TestPagingCursorValueEncoding(t *testing.T) {
+	digest := func(values ...any) [32]byte {
+		t.Helper()
+		d, err := newCursorTestQuery("", values...).cursorDigest()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return d
+	}
+	one := 1
+	for name, pair := range map[string][2][]any{
+		"NilSlice":   {{[]byte(nil)}, {[]byte{}}},
+		"NilPointer": {{(*int)(nil)}, {&one}},
+		"Boundaries": {{"ab", "c"}, {"a", "bc"}},
+		"Nil":        {{nil}, {""}},
+	} {
+		if digest(pair[0]...) == digest(pair[1]...) {
+			t.Errorf("%s: expected %v and %v to have different digests", name, pair[0], pair[1])
+		}
+	}
+
+	if _, err := newCursorTestQuery("", func() {}).cursorDigest(); err == nil {
+		t.Fatal("expected an error for a value that can not be encoded")
+	}
+	qry := newCursorTestQuery("").BindStruct(struct{}{})
+	if _, err := NewPagingCursor(qry, nil); err == nil {
+		t.Fatal("expected an error for a query bound with a binding callback")
+	}
+}
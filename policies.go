@@ -33,6 +33,7 @@ import (
 	"math"
 	"math/rand"
 	randv2 "math/rand/v2"
+	"slices"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -640,6 +641,7 @@ func TokenAwareHostPolicy(fallback HostSelectionPolicy, opts ...func(*tokenAware
 	p := &tokenAwareHostPolicy{
 		fallback:        fallback,
 		shuffleReplicas: true,
+		latencyAware:    findLatencyAwarePolicy(fallback),
 	}
 	for _, opt := range opts {
 		opt(p)
@@ -674,6 +676,9 @@ type tokenAwareHostPolicy struct {
 	shuffleReplicas          bool
 	nonLocalReplicasFallback bool
 	avoidSlowReplicas        bool
+	// latencyAware is the LatencyAwarePolicy of the fallback, if any, which
+	// also orders the replicas.
+	latencyAware *latencyAwarePolicy
 }
 
 func (t *tokenAwareHostPolicy) Init(s *Session) {
@@ -952,11 +957,19 @@ func (t *tokenAwareHostPolicy) Pick(qry ExecutableQuery) NextHost {
 		return t.fallback.Pick(qry)
 	}
 
-	routingKey, err := qry.GetRoutingKey()
-	if err != nil {
-		return t.fallback.Pick(qry)
-	} else if routingKey == nil {
-		return t.fallback.Pick(qry)
+	// Queries scanning a token range carry the token to route by instead of a
+	// partition key to hash.
+	routingToken, hasRoutingToken := queryRoutingToken(qry)
+
+	var routingKey []byte
+	if !hasRoutingToken {
+		var err error
+		routingKey, err = qry.GetRoutingKey()
+		if err != nil {
+			return t.fallback.Pick(qry)
+		} else if routingKey == nil {
+			return t.fallback.Pick(qry)
+		}
 	}
 
 	meta := t.getMetadataReadOnly()
@@ -974,7 +987,10 @@ func (t *tokenAwareHostPolicy) Pick(qry ExecutableQuery) NextHost {
 	var token Token
 	var tokenCasted int64Token
 	var isInt64Token bool
-	if h64, ok := partitioner.(int64Hasher); ok {
+	if hasRoutingToken {
+		tokenCasted = routingToken
+		isInt64Token = true
+	} else if h64, ok := partitioner.(int64Hasher); ok {
 		tokenCasted = int64Token(h64.hashInt64(routingKey))
 		isInt64Token = true
 	} else {
@@ -1006,7 +1022,7 @@ func (t *tokenAwareHostPolicy) Pick(qry ExecutableQuery) NextHost {
 			ht = meta.replicas[qry.Keyspace()].replicasFor(token)
 		}
 		if ht != nil {
-			needsMutation := t.shuffleReplicas || t.avoidSlowReplicas
+			needsMutation := t.shuffleReplicas || t.avoidSlowReplicas || t.latencyAware != nil
 			if needsMutation {
 				replicas = make([]*HostInfo, len(ht.hosts))
 				copy(replicas, ht.hosts)
@@ -1039,6 +1055,10 @@ func (t *tokenAwareHostPolicy) Pick(qry ExecutableQuery) NextHost {
 		partitionHealthy(replicas, s)
 	}
 
+	if t.latencyAware != nil && !isLWT {
+		t.latencyAware.demoteReplicas(replicas)
+	}
+
 	var (
 		fallbackIter NextHost
 		i, j, k      int
@@ -1057,7 +1077,6 @@ func (t *tokenAwareHostPolicy) Pick(qry ExecutableQuery) NextHost {
 	if t.nonLocalReplicasFallback {
 		remote = make([][]*HostInfo, maxTier)
 	}
-
 	var used hostSet
 	return func() SelectedHost {
 		for i < len(replicas) {
@@ -1090,14 +1109,16 @@ func (t *tokenAwareHostPolicy) Pick(qry ExecutableQuery) NextHost {
 		}
 
 		if t.nonLocalReplicasFallback {
-			for j < len(remote) && k < len(remote[j]) {
-				h := remote[j][k]
-				k++
-
+			for j < len(remote) {
+				// Tiers without replicas, such as a preferred datacenter
+				// the keyspace is not replicated to, are skipped.
 				if k >= len(remote[j]) {
 					j++
 					k = 0
+					continue
 				}
+				h := remote[j][k]
+				k++
 
 				if h.IsUp() {
 					used.add(h)
@@ -1132,18 +1153,147 @@ type dcAwareRR struct {
 	remoteHosts       cowHostList
 	lastUsedHostIdx   uint64
 	disableDCFailover bool
+	failover          dcFailover
 }
 
-type dcFailoverDisabledPolicy interface {
+type dcFailoverPolicy interface {
 	setDCFailoverDisabled()
+	dcFailover() *dcFailover
 }
 
-type dcAwarePolicyOption func(p dcFailoverDisabledPolicy)
+type dcAwarePolicyOption func(p dcFailoverPolicy)
 
-func HostPolicyOptionDisableDCFailover(p dcFailoverDisabledPolicy) {
+func HostPolicyOptionDisableDCFailover(p dcFailoverPolicy) {
 	p.setDCFailoverDisabled()
 }
 
+// HostPolicyOptionPreferredRemoteDCs makes DCAwareRoundRobinPolicy and
+// RackAwareRoundRobinPolicy fail over to the remote datacenters dcs in order,
+// before the other remote datacenters. Every remote datacenter is a separate
+// HostTier, so TokenAwareHostPolicy with NonLocalReplicasFallback orders
+// remote replicas the same way.
+func HostPolicyOptionPreferredRemoteDCs(dcs ...string) dcAwarePolicyOption {
+	return func(p dcFailoverPolicy) {
+		p.dcFailover().preferredDCs = slices.Clone(dcs)
+	}
+}
+
+// HostPolicyOptionMaxHostsPerRemoteDC limits to max the hosts of every remote
+// datacenter that DCAwareRoundRobinPolicy and RackAwareRoundRobinPolicy fail
+// over to, rotating between them across queries. Remote replicas picked by
+// TokenAwareHostPolicy with NonLocalReplicasFallback are not limited.
+func HostPolicyOptionMaxHostsPerRemoteDC(max int) dcAwarePolicyOption {
+	return func(p dcFailoverPolicy) {
+		p.dcFailover().maxHostsPerDC = max
+	}
+}
+
+// HostPolicyOptionNoRemoteDCsForLocalConsistency makes DCAwareRoundRobinPolicy
+// and RackAwareRoundRobinPolicy fail over to remote datacenters only for
+// queries whose consistency is not LOCAL_ONE, LOCAL_QUORUM or LOCAL_SERIAL.
+// Such a query is served by the replicas of the datacenter of its
+// coordinator, so it would silently read or write a remote datacenter instead
+// of failing. This also applies to the remote replicas of
+// TokenAwareHostPolicy with NonLocalReplicasFallback, and to the retries of a
+// query whose consistency a retry policy rewrites: the consistency of every
+// attempt is checked before it is sent to a remote host.
+func HostPolicyOptionNoRemoteDCsForLocalConsistency(p dcFailoverPolicy) {
+	p.dcFailover().localConsistencyStaysLocal = true
+}
+
+// dcFailover configures how DC and rack aware policies fail over to remote
+// datacenters.
+type dcFailover struct {
+	preferredDCs               []string
+	maxHostsPerDC              int
+	localConsistencyStaysLocal bool
+}
+
+// tier returns the tier of the remote datacenter dc among the remote
+// datacenters, starting at 0.
+func (f *dcFailover) tier(dc string) uint {
+	if i := slices.Index(f.preferredDCs, dc); i >= 0 {
+		return uint(i)
+	}
+	return uint(len(f.preferredDCs))
+}
+
+// maxTier returns the maximum tier of remote datacenters.
+func (f *dcFailover) maxTier() uint {
+	return uint(len(f.preferredDCs))
+}
+
+// allows reports whether qry may fail over to remote datacenters.
+func (f *dcFailover) allows(qry ExecutableQuery) bool {
+	if !f.localConsistencyStaysLocal || qry == nil {
+		return true
+	}
+	switch qry.GetConsistency() {
+	case LocalOne, LocalQuorum, LocalSerial:
+		return false
+	}
+	return true
+}
+
+// layers returns the remote hosts to fail over to, by tier, with at most
+// maxHostsPerDC hosts of every datacenter, starting from shift.
+func (f *dcFailover) layers(remote []*HostInfo, shift int) [][]*HostInfo {
+	if len(f.preferredDCs) == 0 && f.maxHostsPerDC <= 0 {
+		return [][]*HostInfo{remote}
+	}
+
+	layers := make([][]*HostInfo, f.maxTier()+1)
+	var perDC map[string]int
+	if f.maxHostsPerDC > 0 {
+		perDC = make(map[string]int)
+	}
+	for i := range remote {
+		h := remote[(shift+i)%len(remote)]
+		dc := h.DataCenter()
+		if perDC != nil {
+			if perDC[dc] >= f.maxHostsPerDC {
+				continue
+			}
+			perDC[dc]++
+		}
+		tier := f.tier(dc)
+		layers[tier] = append(layers[tier], h)
+	}
+	return layers
+}
+
+// hostFailoverFilter is implemented by host selection policies which do not
+// fail over to every host for every query. Such policies still return all
+// their hosts from Pick, and the hosts a query may not use are skipped when
+// the query is executed.
+type hostFailoverFilter interface {
+	allowsHost(qry ExecutableQuery, host *HostInfo) bool
+}
+
+// findHostFailoverFilter returns the hostFailoverFilter of policy, looking
+// through SingleHostReadyPolicy and the fallback of TokenAwareHostPolicy, or
+// nil if there is none. The query executor uses it to check the hosts of
+// every attempt, as retry policies may change the consistency of a query
+// after the policy picked its hosts.
+func findHostFailoverFilter(policy HostSelectionPolicy) hostFailoverFilter {
+	for range maxPolicyUnwrapDepth {
+		if isNilPolicy(policy) {
+			return nil
+		}
+		switch p := policy.(type) {
+		case hostFailoverFilter:
+			return p
+		case *singleHostReadyPolicy:
+			policy = p.HostSelectionPolicy
+		case *tokenAwareHostPolicy:
+			policy = p.fallback
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
 // DCAwareRoundRobinPolicy is a host selection policies which will prioritize and
 // return hosts which are in the local datacentre before returning hosts in all
 // other datercentres
@@ -1159,6 +1309,10 @@ func (d *dcAwareRR) setDCFailoverDisabled() {
 	d.disableDCFailover = true
 }
 
+func (d *dcAwareRR) dcFailover() *dcFailover {
+	return &d.failover
+}
+
 // dcFailoverDisabled reports whether this policy was constructed with
 // HostPolicyOptionDisableDCFailover. Used by driver_config.go to report
 // query.load-balancing.policy.fallback-to-non-preferred-nodes.
@@ -1199,6 +1353,21 @@ func (d *dcAwareRR) IsLocal(host *HostInfo) bool {
 	return host.DataCenter() == d.local
 }
 
+func (d *dcAwareRR) HostTier(host *HostInfo) uint {
+	if d.IsLocal(host) {
+		return 0
+	}
+	return 1 + d.failover.tier(host.DataCenter())
+}
+
+func (d *dcAwareRR) MaxHostTier() uint {
+	return 1 + d.failover.maxTier()
+}
+
+func (d *dcAwareRR) allowsHost(qry ExecutableQuery, host *HostInfo) bool {
+	return d.IsLocal(host) || d.failover.allows(qry)
+}
+
 func (d *dcAwareRR) AddHost(host *HostInfo) {
 	if d.IsLocal(host) {
 		d.localHosts.add(host)
@@ -1264,7 +1433,8 @@ func (d *dcAwareRR) Pick(q ExecutableQuery) NextHost {
 	if d.disableDCFailover {
 		return roundRobbin(int(nextStartOffset), d.localHosts.get().allHosts())
 	}
-	return roundRobbin(int(nextStartOffset), d.localHosts.get().allHosts(), d.remoteHosts.get().allHosts())
+	remote := d.failover.layers(d.remoteHosts.get().allHosts(), int(nextStartOffset))
+	return roundRobbin(int(nextStartOffset), append([][]*HostInfo{d.localHosts.get().allHosts()}, remote...)...)
 }
 
 // RackAwareRoundRobinPolicy is a host selection policies which will prioritize and
@@ -1281,6 +1451,7 @@ type rackAwareRR struct {
 	// before it.
 	lastUsedHostIdx   uint64
 	disableDCFailover bool
+	failover          dcFailover
 }
 
 func RackAwareRoundRobinPolicy(localDC string, localRack string, opts ...dcAwarePolicyOption) HostSelectionPolicy {
@@ -1313,13 +1484,21 @@ func (d *rackAwareRR) IsOperational(session *Session) error {
 }
 
 func (d *rackAwareRR) MaxHostTier() uint {
-	return 2
+	return 2 + d.failover.maxTier()
 }
 
 func (d *rackAwareRR) setDCFailoverDisabled() {
 	d.disableDCFailover = true
 }
 
+func (d *rackAwareRR) dcFailover() *dcFailover {
+	return &d.failover
+}
+
+func (d *rackAwareRR) allowsHost(qry ExecutableQuery, host *HostInfo) bool {
+	return host.DataCenter() == d.localDC || d.failover.allows(qry)
+}
+
 // dcFailoverDisabled reports whether this policy was constructed with
 // HostPolicyOptionDisableDCFailover. Used by driver_config.go to report
 // query.load-balancing.policy.fallback-to-non-preferred-nodes.
@@ -1339,6 +1518,16 @@ func (d *rackAwareRR) localRackName() string {
 }
 
 func (d *rackAwareRR) HostTier(host *HostInfo) uint {
+	dist := d.distance(host)
+	if dist == 2 {
+		return dist + d.failover.tier(host.DataCenter())
+	}
+	return dist
+}
+
+// distance returns the index in hosts of the list of host: 0 for the local
+// rack, 1 for the local datacenter and 2 for remote datacenters.
+func (d *rackAwareRR) distance(host *HostInfo) uint {
 	if host.DataCenter() == d.localDC {
 		if host.Rack() == d.localRack {
 			return 0
@@ -1355,12 +1544,12 @@ func (d *rackAwareRR) IsLocal(host *HostInfo) bool {
 }
 
 func (d *rackAwareRR) AddHost(host *HostInfo) {
-	dist := d.HostTier(host)
+	dist := d.distance(host)
 	d.hosts[dist].add(host)
 }
 
 func (d *rackAwareRR) RemoveHost(host *HostInfo) {
-	dist := d.HostTier(host)
+	dist := d.distance(host)
 	d.hosts[dist].remove(host)
 }
 
@@ -1372,7 +1561,8 @@ func (d *rackAwareRR) Pick(q ExecutableQuery) NextHost {
 	if d.disableDCFailover {
 		return roundRobbin(int(nextStartOffset), d.hosts[0].get().allHosts(), d.hosts[1].get().allHosts())
 	}
-	return roundRobbin(int(nextStartOffset), d.hosts[0].get().allHosts(), d.hosts[1].get().allHosts(), d.hosts[2].get().allHosts())
+	remote := d.failover.layers(d.hosts[2].get().allHosts(), int(nextStartOffset))
+	return roundRobbin(int(nextStartOffset), append([][]*HostInfo{d.hosts[0].get().allHosts(), d.hosts[1].get().allHosts()}, remote...)...)
 }
 
 // ReadyPolicy defines a policy for when a HostSelectionPolicy can be used. After
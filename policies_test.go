@@ -34,6 +34,7 @@ package gocql
 import (
 	"errors"
 	"fmt"
+	"maps"
 	"net"
 	"sort"
 	"strings"
@@ -1311,6 +1312,158 @@ func TestHostPolicy_RackAwareRR(t *testing.T) {
 	expectNoMoreHosts(t, it)
 }
 
+func TestHostPolicy_DCAwareRR_PreferredRemoteDCs(t *testing.T) {
+	t.Parallel()
+
+	p := DCAwareRoundRobinPolicy("local",
+		HostPolicyOptionPreferredRemoteDCs("near", "mid"),
+		HostPolicyOptionMaxHostsPerRemoteDC(1),
+	)
+
+	hosts := [...]*HostInfo{
+		{hostId: tUUID(0), connectAddress: net.ParseIP("10.0.0.1"), dataCenter: "local"},
+		{hostId: tUUID(1), connectAddress: net.ParseIP("10.0.0.2"), dataCenter: "far"},
+		{hostId: tUUID(2), connectAddress: net.ParseIP("10.0.0.3"), dataCenter: "far"},
+		{hostId: tUUID(3), connectAddress: net.ParseIP("10.0.0.4"), dataCenter: "mid"},
+		{hostId: tUUID(4), connectAddress: net.ParseIP("10.0.0.5"), dataCenter: "mid"},
+		{hostId: tUUID(5), connectAddress: net.ParseIP("10.0.0.6"), dataCenter: "near"},
+		{hostId: tUUID(6), connectAddress: net.ParseIP("10.0.0.7"), dataCenter: "near"},
+		{hostId: tUUID(7), connectAddress: net.ParseIP("10.0.0.8"), dataCenter: "local"},
+	}
+	for _, host := range hosts {
+		p.AddHost(host)
+	}
+
+	tierer := p.(HostTierer)
+	for dc, want := range map[string]uint{"local": 0, "near": 1, "mid": 2, "far": 3} {
+		if got := tierer.HostTier(&HostInfo{dataCenter: dc}); got != want {
+			t.Errorf("HostTier(%s) = %d, want %d", dc, got, want)
+		}
+	}
+	if got := tierer.MaxHostTier(); got != 3 {
+		t.Errorf("MaxHostTier() = %d, want 3", got)
+	}
+
+	for range 2 {
+		var dcs []string
+		it := p.Pick(nil)
+		for h := it(); h != nil; h = it() {
+			dcs = append(dcs, h.Info().DataCenter())
+		}
+		tests.AssertDeepEqual(t, "datacenters", []string{"local", "local", "near", "mid", "far"}, dcs)
+	}
+}
+
+func TestHostPolicy_NoRemoteDCsForLocalConsistency(t *testing.T) {
+	t.Parallel()
+
+	policies := map[string]HostSelectionPolicy{
+		"dc aware":   DCAwareRoundRobinPolicy("local", HostPolicyOptionNoRemoteDCsForLocalConsistency),
+		"rack aware": RackAwareRoundRobinPolicy("local", "a", HostPolicyOptionNoRemoteDCsForLocalConsistency),
+	}
+	for name, p := range policies {
+		t.Run(name, func(t *testing.T) {
+			local := &HostInfo{hostId: tUUID(0), connectAddress: net.ParseIP("10.0.0.1"), dataCenter: "local", rack: "a"}
+			remote := &HostInfo{hostId: tUUID(1), connectAddress: net.ParseIP("10.0.0.2"), dataCenter: "remote", rack: "a"}
+			p.AddHost(local)
+			p.AddHost(remote)
+
+			// Remote hosts are picked for every consistency, as retries may
+			// change it, and filtered for every attempt.
+			for _, cons := range []Consistency{LocalQuorum, Quorum} {
+				it := p.Pick(&Query{cons: cons})
+				expectHosts(t, "local hosts for "+cons.String(), it, tID(0))
+				expectHosts(t, "remote hosts for "+cons.String(), it, tID(1))
+				expectNoMoreHosts(t, it)
+			}
+
+			filter := findHostFailoverFilter(p)
+			if filter == nil {
+				t.Fatal("no host failover filter")
+			}
+			if !filter.allowsHost(&Query{cons: LocalQuorum}, local) {
+				t.Error("local host not allowed for LOCAL_QUORUM")
+			}
+			if filter.allowsHost(&Query{cons: LocalQuorum}, remote) {
+				t.Error("remote host allowed for LOCAL_QUORUM")
+			}
+			if !filter.allowsHost(&Query{cons: Quorum}, remote) {
+				t.Error("remote host not allowed for QUORUM")
+			}
+		})
+	}
+}
+
+func TestHostPolicy_TokenAware_PreferredRemoteDCs(t *testing.T) {
+	t.Parallel()
+
+	const keyspace = "myKeyspace"
+	hosts := [...]*HostInfo{
+		{hostId: tUUID(0), connectAddress: net.IPv4(10, 0, 0, 1), tokens: []string{"05"}, dataCenter: "local"},
+		{hostId: tUUID(1), connectAddress: net.IPv4(10, 0, 0, 2), tokens: []string{"10"}, dataCenter: "far"},
+		{hostId: tUUID(2), connectAddress: net.IPv4(10, 0, 0, 3), tokens: []string{"15"}, dataCenter: "near"},
+		{hostId: tUUID(3), connectAddress: net.IPv4(10, 0, 0, 4), tokens: []string{"20"}, dataCenter: "local"},
+	}
+	newPolicy := func(replication map[string]any) HostSelectionPolicy {
+		policy := TokenAwareHostPolicy(DCAwareRoundRobinPolicy("local",
+			HostPolicyOptionPreferredRemoteDCs("near"),
+			HostPolicyOptionNoRemoteDCsForLocalConsistency,
+		), NonLocalReplicasFallback())
+		policyInternal := policy.(*tokenAwareHostPolicy)
+		policyInternal.getKeyspaceName = func() string { return keyspace }
+		policyInternal.getKeyspaceMetadata = func(ks string) (*KeyspaceMetadata, error) {
+			options := map[string]any{"class": "NetworkTopologyStrategy"}
+			maps.Copy(options, replication)
+			return &KeyspaceMetadata{
+				Name:            keyspace,
+				StrategyClass:   "NetworkTopologyStrategy",
+				StrategyOptions: options,
+			}, nil
+		}
+		for _, host := range hosts {
+			policy.AddHost(host)
+		}
+		policy.SetPartitioner("OrderedPartitioner")
+		policy.KeyspaceChanged(KeyspaceUpdateEvent{Keyspace: keyspace})
+		return policy
+	}
+
+	query := &Query{routingInfo: &queryRoutingInfo{}, cons: Quorum}
+	query.getKeyspace = func() string { return keyspace }
+	query.RoutingKey([]byte("03"))
+
+	policy := newPolicy(map[string]any{"local": 1, "near": 1, "far": 1})
+	iter := policy.Pick(query)
+	expectHosts(t, "local replica", iter, tID(0))
+	expectHosts(t, "replica from the preferred remote DC", iter, tID(2))
+	expectHosts(t, "replica from another remote DC", iter, tID(1))
+	expectHosts(t, "local fallback", iter, tID(3))
+	expectNoMoreHosts(t, iter)
+
+	// Remote replicas are still picked for LOCAL_ONE, the query executor
+	// skips them.
+	query.cons = LocalOne
+	iter = policy.Pick(query)
+	expectHosts(t, "local replica", iter, tID(0))
+	expectHosts(t, "replica from the preferred remote DC", iter, tID(2))
+	expectHosts(t, "replica from another remote DC", iter, tID(1))
+	expectHosts(t, "local fallback", iter, tID(3))
+	expectNoMoreHosts(t, iter)
+	if filter := findHostFailoverFilter(policy); filter == nil || filter.allowsHost(query, hosts[2]) || !filter.allowsHost(query, hosts[3]) {
+		t.Error("LOCAL_ONE must be allowed on local hosts only")
+	}
+
+	// The preferred remote DC has no replicas, which must not hide the
+	// replicas of the other remote DCs.
+	query.cons = Quorum
+	iter = newPolicy(map[string]any{"local": 1, "far": 1}).Pick(query)
+	expectHosts(t, "local replica", iter, tID(0))
+	expectHosts(t, "replica from another remote DC", iter, tID(1))
+	expectHosts(t, "local fallback", iter, tID(3))
+	expectHosts(t, "fallback to the preferred remote DC", iter, tID(2))
+	expectNoMoreHosts(t, iter)
+}
+
 // Tests of the token-aware host selection policy implementation with a
 // DC & Rack aware round-robin host selection policy fallback
 func TestHostPolicy_TokenAware_RackAware(t *testing.T) {
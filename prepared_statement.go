@@ -0,0 +1,128 @@
+package gocql
+
+import (
+	"context"
+	"fmt"
+	"slices"
+)
+
+// PreparedStatement is a statement prepared with Session.Prepare. It exposes
+// the metadata the server returned for the statement and binds values into
+// queries that reuse its routing information.
+//
+// A PreparedStatement is immutable and safe for concurrent use. Its metadata
+// is a snapshot taken when the statement was prepared: if the schema changes
+// afterwards, prepare the statement again to observe the change.
+type PreparedStatement struct {
+	session  *Session
+	routing  *routingKeyInfo
+	stmt     string
+	keyspace string
+	table    string
+	id       []byte
+	args     []ColumnInfo
+	result   []ColumnInfo
+	pkIndex  []int
+	lwt      bool
+}
+
+// Prepare prepares stmt against the session keyspace and returns its bind
+// and result metadata. Only DML statements can be prepared.
+//
+// Preparing up front lets applications validate their statements at startup:
+// a statement that no longer matches the schema fails here rather than on
+// first use.
+func (s *Session) Prepare(ctx context.Context, stmt string) (*PreparedStatement, error) {
+	if s.Closed() {
+		return nil, ErrSessionClosed
+	} else if !stmtIsDML(stmt) {
+		return nil, fmt.Errorf("gocql: only DML statements can be prepared, got %q", stmt)
+	}
+
+	conn := s.getConn()
+	if conn == nil {
+		return nil, ErrNoConnections
+	}
+
+	info, err := conn.prepareStatement(ctx, stmt, nil, s.cfg.Keyspace, s.cfg.Timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	routing, err := s.routingKeyInfo(ctx, stmt, s.cfg.Keyspace, s.cfg.Timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	keyspace, table := resolveRoutingKeyspaceTable(&info.request, s.cfg.Keyspace)
+	ps := &PreparedStatement{
+		session:  s,
+		routing:  routing,
+		stmt:     stmt,
+		keyspace: keyspace,
+		table:    table,
+		id:       info.id,
+		args:     info.request.columns,
+		result:   info.response.columns,
+		lwt:      info.request.lwt,
+	}
+	if routing != nil {
+		ps.pkIndex = routing.indexes
+	}
+	return ps, nil
+}
+
+// Statement returns the CQL statement that was prepared.
+func (ps *PreparedStatement) Statement() string {
+	return ps.stmt
+}
+
+// ID returns the identifier the server assigned to the prepared statement.
+func (ps *PreparedStatement) ID() []byte {
+	return slices.Clone(ps.id)
+}
+
+// Keyspace returns the keyspace the statement targets.
+func (ps *PreparedStatement) Keyspace() string {
+	return ps.keyspace
+}
+
+// Table returns the table the statement targets.
+func (ps *PreparedStatement) Table() string {
+	return ps.table
+}
+
+// IsLWT reports whether the statement is a lightweight transaction.
+func (ps *PreparedStatement) IsLWT() bool {
+	return ps.lwt
+}
+
+// BindMarkers returns the name and type of the statement's bind markers, in
+// the order values must be bound in. A positional marker (?) is named after
+// the column it is bound to and a named marker (:name) after its name.
+func (ps *PreparedStatement) BindMarkers() []ColumnInfo {
+	return slices.Clone(ps.args)
+}
+
+// PartitionKeyIndexes returns the indexes, among the bind markers, of the
+// values that make up the partition key, in partition key component order.
+// It is empty when the statement does not bind the whole partition key.
+func (ps *PreparedStatement) PartitionKeyIndexes() []int {
+	return slices.Clone(ps.pkIndex)
+}
+
+// ResultColumns returns the name and type of the columns the statement
+// returns, if any.
+func (ps *PreparedStatement) ResultColumns() []ColumnInfo {
+	return slices.Clone(ps.result)
+}
+
+// Bind returns a new query executing the statement with values. The query is
+// routed using the routing information resolved by Session.Prepare instead of
+// resolving it again.
+func (ps *PreparedStatement) Bind(values ...any) *Query {
+	qry := ps.session.Query(ps.stmt, values...)
+	qry.preparedRouting = ps.routing
+	qry.bindMarkers = ps.args
+	return qry
+}
@@ -0,0 +1,61 @@
+//go:build unit
+// +build unit
+
+package gocql
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestPreparedStatementBindUsesResolvedRouting(t *testing.T) {
+	intType := NativeType{typ: TypeInt, proto: 4}
+	ps := &PreparedStatement{
+		session: &Session{},
+		routing: &routingKeyInfo{
+			indexes:  []int{1},
+			types:    []TypeInfo{intType},
+			keyspace: "ks",
+			table:    "tbl",
+			lwt:      true,
+		},
+		stmt:     "UPDATE ks.tbl SET v = ? WHERE pk = ? IF EXISTS",
+		keyspace: "ks",
+		table:    "tbl",
+		args: []ColumnInfo{
+			{Keyspace: "ks", Table: "tbl", Name: "v", TypeInfo: intType},
+			{Keyspace: "ks", Table: "tbl", Name: "pk", TypeInfo: intType},
+		},
+		pkIndex: []int{1},
+		lwt:     true,
+	}
+
+	qry := ps.Bind(int32(10), int32(42))
+	if qry.Statement() != ps.stmt {
+		t.Fatalf("got statement %q, want %q", qry.Statement(), ps.stmt)
+	}
+
+	// The session has no routing key info cache, so this would panic if the
+	// query tried to resolve the routing key info again.
+	key, err := qry.GetRoutingKey()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want, err := Marshal(intType, int32(42))
+	if err != nil {
+		t.Fatalf("unexpected error from reference Marshal: %v", err)
+	}
+	if !bytes.Equal(key, want) {
+		t.Fatalf("got routing key %x, want %x", key, want)
+	}
+	if !qry.IsLWT() || qry.Keyspace() != "ks" || qry.Table() != "tbl" {
+		t.Fatalf("expected the query to carry the prepared routing info, got lwt=%t keyspace=%q table=%q",
+			qry.IsLWT(), qry.Keyspace(), qry.Table())
+	}
+
+	markers := ps.BindMarkers()
+	markers[0].Name = "mutated"
+	if ps.BindMarkers()[0].Name != "v" {
+		t.Fatal("expected BindMarkers to return a copy")
+	}
+}
@@ -0,0 +1,176 @@
+package qb
+
+import (
+	"strings"
+
+	"github.com/gocql/gocql/internal/cqlident"
+)
+
+// Cmp is a condition of a WHERE or IF clause.
+type Cmp struct {
+	lhs     string
+	op      string
+	rhs     string
+	names   []string
+	columns []string
+}
+
+func newCmp(column, op, name string) Cmp {
+	return Cmp{
+		lhs:     cqlident.QuoteIfNeeded(column),
+		op:      op,
+		rhs:     marker(name),
+		names:   []string{name},
+		columns: []string{column},
+	}
+}
+
+// Eq returns the condition column = :column.
+func Eq(column string) Cmp { return newCmp(column, "=", column) }
+
+// EqNamed returns the condition column = :name.
+func EqNamed(column, name string) Cmp { return newCmp(column, "=", name) }
+
+// Ne returns the condition column != :column, which is only valid in IF
+// clauses.
+func Ne(column string) Cmp { return newCmp(column, "!=", column) }
+
+// NeNamed returns the condition column != :name.
+func NeNamed(column, name string) Cmp { return newCmp(column, "!=", name) }
+
+// Lt returns the condition column < :column.
+func Lt(column string) Cmp { return newCmp(column, "<", column) }
+
+// LtNamed returns the condition column < :name.
+func LtNamed(column, name string) Cmp { return newCmp(column, "<", name) }
+
+// LtOrEq returns the condition column <= :column.
+func LtOrEq(column string) Cmp { return newCmp(column, "<=", column) }
+
+// LtOrEqNamed returns the condition column <= :name.
+func LtOrEqNamed(column, name string) Cmp { return newCmp(column, "<=", name) }
+
+// Gt returns the condition column > :column.
+func Gt(column string) Cmp { return newCmp(column, ">", column) }
+
+// GtNamed returns the condition column > :name.
+func GtNamed(column, name string) Cmp { return newCmp(column, ">", name) }
+
+// GtOrEq returns the condition column >= :column.
+func GtOrEq(column string) Cmp { return newCmp(column, ">=", column) }
+
+// GtOrEqNamed returns the condition column >= :name.
+func GtOrEqNamed(column, name string) Cmp { return newCmp(column, ">=", name) }
+
+// In returns the condition column IN :column, binding a list of values.
+func In(column string) Cmp { return newCmp(column, "IN", column) }
+
+// InNamed returns the condition column IN :name.
+func InNamed(column, name string) Cmp { return newCmp(column, "IN", name) }
+
+// Contains returns the condition column CONTAINS :column, for collection
+// columns.
+func Contains(column string) Cmp { return newCmp(column, "CONTAINS", column) }
+
+// ContainsNamed returns the condition column CONTAINS :name.
+func ContainsNamed(column, name string) Cmp { return newCmp(column, "CONTAINS", name) }
+
+// ContainsKey returns the condition column CONTAINS KEY :column, for map
+// columns.
+func ContainsKey(column string) Cmp { return newCmp(column, "CONTAINS KEY", column) }
+
+// ContainsKeyNamed returns the condition column CONTAINS KEY :name.
+func ContainsKeyNamed(column, name string) Cmp { return newCmp(column, "CONTAINS KEY", name) }
+
+// TokenBuilder builds conditions on the token of partition key columns,
+// as used to scan a table by token ranges.
+type TokenBuilder []string
+
+// Token returns a TokenBuilder for the token of the given partition key
+// columns.
+func Token(columns ...string) TokenBuilder {
+	return TokenBuilder(columns)
+}
+
+func (t TokenBuilder) lhs() string {
+	var b strings.Builder
+	b.WriteString("token(")
+	writeColumns(&b, t)
+	b.WriteString(")")
+	return b.String()
+}
+
+// cmp returns the condition comparing the token of the columns to the
+// token of the values bound to markers named after the columns.
+func (t TokenBuilder) cmp(op string) Cmp {
+	var b strings.Builder
+	b.WriteString("token(")
+	for i, col := range t {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(marker(col))
+	}
+	b.WriteString(")")
+	return Cmp{lhs: t.lhs(), op: op, rhs: b.String(), names: t, columns: t}
+}
+
+// cmpNamed returns the condition comparing the token of the columns to the
+// token bound to the marker name.
+func (t TokenBuilder) cmpNamed(op, name string) Cmp {
+	return Cmp{lhs: t.lhs(), op: op, rhs: marker(name), names: []string{name}, columns: t}
+}
+
+// Eq returns the condition token(columns) = token(:columns).
+func (t TokenBuilder) Eq() Cmp { return t.cmp("=") }
+
+// EqNamed returns the condition token(columns) = :name.
+func (t TokenBuilder) EqNamed(name string) Cmp { return t.cmpNamed("=", name) }
+
+// Lt returns the condition token(columns) < token(:columns).
+func (t TokenBuilder) Lt() Cmp { return t.cmp("<") }
+
+// LtNamed returns the condition token(columns) < :name.
+func (t TokenBuilder) LtNamed(name string) Cmp { return t.cmpNamed("<", name) }
+
+// LtOrEq returns the condition token(columns) <= token(:columns).
+func (t TokenBuilder) LtOrEq() Cmp { return t.cmp("<=") }
+
+// LtOrEqNamed returns the condition token(columns) <= :name.
+func (t TokenBuilder) LtOrEqNamed(name string) Cmp { return t.cmpNamed("<=", name) }
+
+// Gt returns the condition token(columns) > token(:columns).
+func (t TokenBuilder) Gt() Cmp { return t.cmp(">") }
+
+// GtNamed returns the condition token(columns) > :name.
+func (t TokenBuilder) GtNamed(name string) Cmp { return t.cmpNamed(">", name) }
+
+// GtOrEq returns the condition token(columns) >= token(:columns).
+func (t TokenBuilder) GtOrEq() Cmp { return t.cmp(">=") }
+
+// GtOrEqNamed returns the condition token(columns) >= :name.
+func (t TokenBuilder) GtOrEqNamed(name string) Cmp { return t.cmpNamed(">=", name) }
+
+// writeCmps writes the conditions joined by AND after keyword, and returns
+// names with the names of their bind markers appended.
+func writeCmps(b *strings.Builder, keyword string, cmps []Cmp, names []string) []string {
+	for i, c := range cmps {
+		if i == 0 {
+			b.WriteString(" " + keyword + " ")
+		} else {
+			b.WriteString(" AND ")
+		}
+		b.WriteString(c.lhs + " " + c.op + " " + c.rhs)
+		names = append(names, c.names...)
+	}
+	return names
+}
+
+// cmpColumns returns the columns referenced by cmps.
+func cmpColumns(cmps []Cmp) []string {
+	var columns []string
+	for _, c := range cmps {
+		columns = append(columns, c.columns...)
+	}
+	return columns
+}
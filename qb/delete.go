@@ -0,0 +1,91 @@
+package qb
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+// DeleteBuilder builds DELETE statements.
+type DeleteBuilder struct {
+	table   string
+	columns []string
+	where   []Cmp
+	ifs     []Cmp
+	using   usingClause
+	exists  bool
+}
+
+// Delete returns a builder of a DELETE statement deleting from table, which
+// may be qualified by its keyspace as in "keyspace.table".
+func Delete(table string) *DeleteBuilder {
+	return &DeleteBuilder{table: table}
+}
+
+// Columns adds columns to delete. Whole rows are deleted if none is added.
+func (b *DeleteBuilder) Columns(columns ...string) *DeleteBuilder {
+	b.columns = append(b.columns, columns...)
+	return b
+}
+
+// Where adds conditions to the WHERE clause.
+func (b *DeleteBuilder) Where(cmps ...Cmp) *DeleteBuilder {
+	b.where = append(b.where, cmps...)
+	return b
+}
+
+// If adds conditions to the IF clause, making the statement a lightweight
+// transaction. It replaces a previous call to Existing, as a statement has
+// either IF conditions or IF EXISTS.
+func (b *DeleteBuilder) If(cmps ...Cmp) *DeleteBuilder {
+	b.ifs = append(b.ifs, cmps...)
+	b.exists = false
+	return b
+}
+
+// Existing makes the statement a lightweight transaction only deleting the
+// row if it exists, as in IF EXISTS. It drops the conditions of previous
+// calls to If, as a statement has either IF conditions or IF EXISTS.
+func (b *DeleteBuilder) Existing() *DeleteBuilder {
+	b.exists = true
+	b.ifs = nil
+	return b
+}
+
+// Timestamp sets the timestamp of the deletion.
+func (b *DeleteBuilder) Timestamp(ts time.Time) *DeleteBuilder {
+	b.using.setTimestamp(ts)
+	return b
+}
+
+// TimestampNamed sets the timestamp of the deletion to the value, in
+// microseconds, bound to the marker name.
+func (b *DeleteBuilder) TimestampNamed(name string) *DeleteBuilder {
+	b.using.setTimestampNamed(name)
+	return b
+}
+
+// ToCql returns the statement and the names of its bind markers.
+func (b *DeleteBuilder) ToCql() (stmt string, names []string) {
+	var cql strings.Builder
+	cql.WriteString("DELETE ")
+	if len(b.columns) > 0 {
+		writeColumns(&cql, b.columns)
+		cql.WriteString(" ")
+	}
+	cql.WriteString("FROM " + quoteTable(b.table))
+	names = b.using.writeCql(&cql, names)
+
+	names = writeCmps(&cql, "WHERE", b.where, names)
+	names = writeCmps(&cql, "IF", b.ifs, names)
+	if b.exists {
+		cql.WriteString(" IF EXISTS")
+	}
+	return cql.String(), names
+}
+
+// Validate checks that the columns used by the statement exist in table.
+func (b *DeleteBuilder) Validate(table *gocql.TableMetadata) error {
+	return validateColumns(table, b.columns, cmpColumns(b.where), cmpColumns(b.ifs))
+}
@@ -0,0 +1,88 @@
+package qb
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+// InsertBuilder builds INSERT statements.
+type InsertBuilder struct {
+	table   string
+	columns []string
+	using   usingClause
+	unique  bool
+}
+
+// Insert returns a builder of an INSERT statement writing to table, which
+// may be qualified by its keyspace as in "keyspace.table".
+func Insert(table string) *InsertBuilder {
+	return &InsertBuilder{table: table}
+}
+
+// Columns adds columns to insert, each bound to a marker named after it.
+func (b *InsertBuilder) Columns(columns ...string) *InsertBuilder {
+	b.columns = append(b.columns, columns...)
+	return b
+}
+
+// Unique makes the statement a lightweight transaction only inserting the
+// row if it does not exist, as in IF NOT EXISTS.
+func (b *InsertBuilder) Unique() *InsertBuilder {
+	b.unique = true
+	return b
+}
+
+// TTL sets the time to live of the inserted values, in seconds.
+func (b *InsertBuilder) TTL(ttl time.Duration) *InsertBuilder {
+	b.using.setTTL(ttl)
+	return b
+}
+
+// TTLNamed sets the time to live of the inserted values to the value bound
+// to the marker name.
+func (b *InsertBuilder) TTLNamed(name string) *InsertBuilder {
+	b.using.setTTLNamed(name)
+	return b
+}
+
+// Timestamp sets the write timestamp of the inserted values.
+func (b *InsertBuilder) Timestamp(ts time.Time) *InsertBuilder {
+	b.using.setTimestamp(ts)
+	return b
+}
+
+// TimestampNamed sets the write timestamp of the inserted values to the
+// value, in microseconds, bound to the marker name.
+func (b *InsertBuilder) TimestampNamed(name string) *InsertBuilder {
+	b.using.setTimestampNamed(name)
+	return b
+}
+
+// ToCql returns the statement and the names of its bind markers.
+func (b *InsertBuilder) ToCql() (stmt string, names []string) {
+	var cql strings.Builder
+	cql.WriteString("INSERT INTO " + quoteTable(b.table) + " (")
+	writeColumns(&cql, b.columns)
+	cql.WriteString(") VALUES (")
+	for i, col := range b.columns {
+		if i > 0 {
+			cql.WriteString(", ")
+		}
+		cql.WriteString(marker(col))
+	}
+	cql.WriteString(")")
+	names = append(names, b.columns...)
+
+	if b.unique {
+		cql.WriteString(" IF NOT EXISTS")
+	}
+	names = b.using.writeCql(&cql, names)
+	return cql.String(), names
+}
+
+// Validate checks that the columns used by the statement exist in table.
+func (b *InsertBuilder) Validate(table *gocql.TableMetadata) error {
+	return validateColumns(table, b.columns)
+}
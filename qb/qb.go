@@ -0,0 +1,119 @@
+// Package qb builds CQL statements, quoting identifiers as needed and
+// generating named bind markers.
+//
+// Every builder returns the statement together with the names of its bind
+// markers, in the order they appear in the statement:
+//
+//	stmt, names := qb.Select("ks.users").
+//		Columns("id", "name").
+//		Where(qb.Eq("id")).
+//		ToCql()
+//	// stmt:  SELECT id, name FROM ks.users WHERE id = :id
+//	// names: [id]
+//
+// The names are the ones the server reports for the bind markers of the
+// prepared statement, so the statement can be bound by name with
+// Query.BindMap or Query.BindStruct, or positionally in the order of names:
+//
+//	err := session.Query(stmt).BindMap(map[string]any{"id": id}).Scan(&id, &name)
+//
+// Identifiers are used verbatim, as they appear in the schema metadata, and
+// are quoted when they are case-sensitive, contain special characters or are
+// reserved words.
+package qb
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/gocql/gocql/internal/cqlident"
+)
+
+// Builder is implemented by the statement builders.
+type Builder interface {
+	// ToCql returns the statement and the names of its bind markers.
+	ToCql() (stmt string, names []string)
+	// Validate checks that the columns used by the statement exist in
+	// table, as returned by Session.TableMetadata.
+	Validate(table *gocql.TableMetadata) error
+}
+
+// quoteTable returns a table name, optionally qualified by its keyspace as
+// in "keyspace.table", as a CQL identifier.
+func quoteTable(name string) string {
+	if keyspace, table, ok := strings.Cut(name, "."); ok {
+		return cqlident.QuoteIfNeeded(keyspace) + "." + cqlident.QuoteIfNeeded(table)
+	}
+	return cqlident.QuoteIfNeeded(name)
+}
+
+// marker returns the named bind marker of name.
+func marker(name string) string {
+	return ":" + cqlident.QuoteIfNeeded(name)
+}
+
+func writeColumns(b *strings.Builder, columns []string) {
+	for i, col := range columns {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(cqlident.QuoteIfNeeded(col))
+	}
+}
+
+// validateColumns checks that every column exists in table.
+func validateColumns(table *gocql.TableMetadata, columns ...[]string) error {
+	for _, cols := range columns {
+		for _, col := range cols {
+			if _, ok := table.Columns[col]; !ok {
+				return fmt.Errorf("qb: table %s.%s has no column %q", table.Keyspace, table.Name, col)
+			}
+		}
+	}
+	return nil
+}
+
+// usingClause holds the USING TTL and TIMESTAMP options of a statement.
+type usingClause struct {
+	ttl           string
+	ttlName       string
+	timestamp     string
+	timestampName string
+}
+
+func (u *usingClause) writeCql(b *strings.Builder, names []string) []string {
+	switch {
+	case u.ttl != "" && u.timestamp != "":
+		b.WriteString(" USING TTL " + u.ttl + " AND TIMESTAMP " + u.timestamp)
+	case u.ttl != "":
+		b.WriteString(" USING TTL " + u.ttl)
+	case u.timestamp != "":
+		b.WriteString(" USING TIMESTAMP " + u.timestamp)
+	}
+	if u.ttlName != "" {
+		names = append(names, u.ttlName)
+	}
+	if u.timestampName != "" {
+		names = append(names, u.timestampName)
+	}
+	return names
+}
+
+func (u *usingClause) setTTL(ttl time.Duration) {
+	u.ttl, u.ttlName = strconv.FormatInt(int64(ttl/time.Second), 10), ""
+}
+
+func (u *usingClause) setTTLNamed(name string) {
+	u.ttl, u.ttlName = marker(name), name
+}
+
+func (u *usingClause) setTimestamp(ts time.Time) {
+	u.timestamp, u.timestampName = strconv.FormatInt(ts.UnixMicro(), 10), ""
+}
+
+func (u *usingClause) setTimestampNamed(name string) {
+	u.timestamp, u.timestampName = marker(name), name
+}
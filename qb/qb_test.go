@@ -0,0 +1,129 @@
+//go:build unit
+// +build unit
+
+package qb
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+func TestQuoteTable(t *testing.T) {
+	if got := quoteTable("ks.Users"); got != `ks."Users"` {
+		t.Errorf("got %s, want ks.\"Users\"", got)
+	}
+}
+
+func TestBuilders(t *testing.T) {
+	ts := time.UnixMicro(1700000000000000)
+	tests := []struct {
+		name      string
+		builder   Builder
+		wantStmt  string
+		wantNames []string
+	}{
+		{
+			name: "Select",
+			builder: Select("ks.tbl").Columns("id", "Name").
+				Where(Eq("id"), GtNamed("ts", "since"), In("order")).
+				OrderBy("ts", DESC).Limit(10).AllowFiltering(),
+			wantStmt:  `SELECT id, "Name" FROM ks.tbl WHERE id = :id AND ts > :since AND "order" IN :"order" ORDER BY ts DESC LIMIT 10 ALLOW FILTERING`,
+			wantNames: []string{"id", "since", "order"},
+		},
+		{
+			name:      "SelectCountLimitNamed",
+			builder:   Select("tbl").Count().Where(Contains("tags")).PerPartitionLimit(2).LimitNamed("n"),
+			wantStmt:  `SELECT COUNT(*) FROM tbl WHERE tags CONTAINS :tags PER PARTITION LIMIT 2 LIMIT :n`,
+			wantNames: []string{"tags", "n"},
+		},
+		{
+			name:      "SelectTokenRange",
+			builder:   Select("tbl").Distinct().Columns("a", "b").Where(Token("a", "b").GtNamed("start"), Token("a", "b").LtOrEqNamed("end")),
+			wantStmt:  `SELECT DISTINCT a, b FROM tbl WHERE token(a, b) > :start AND token(a, b) <= :end`,
+			wantNames: []string{"start", "end"},
+		},
+		{
+			name:      "SelectTokenOfKey",
+			builder:   Select("tbl").Where(Token("a").Gt()),
+			wantStmt:  `SELECT * FROM tbl WHERE token(a) > token(:a)`,
+			wantNames: []string{"a"},
+		},
+		{
+			name:      "Insert",
+			builder:   Insert("ks.tbl").Columns("id", "v").Unique().TimestampNamed("ts").TTL(time.Hour),
+			wantStmt:  `INSERT INTO ks.tbl (id, v) VALUES (:id, :v) IF NOT EXISTS USING TTL 3600 AND TIMESTAMP :ts`,
+			wantNames: []string{"id", "v", "ts"},
+		},
+		{
+			name: "Update",
+			builder: Update("tbl").TTLNamed("ttl").Timestamp(ts).
+				Set("v").Add("tags").Remove("counts").Prepend("events").
+				Where(Eq("id")).If(EqNamed("v", "old_v")),
+			wantStmt:  `UPDATE tbl USING TTL :ttl AND TIMESTAMP 1700000000000000 SET v = :v, tags = tags + :tags, counts = counts - :counts, events = :events + events WHERE id = :id IF v = :old_v`,
+			wantNames: []string{"ttl", "v", "tags", "counts", "events", "id", "old_v"},
+		},
+		{
+			name:      "UpdateExisting",
+			builder:   Update("tbl").Set("v").Where(Eq("id")).Existing(),
+			wantStmt:  `UPDATE tbl SET v = :v WHERE id = :id IF EXISTS`,
+			wantNames: []string{"v", "id"},
+		},
+		{
+			name:      "Delete",
+			builder:   Delete("tbl").Columns("v").Timestamp(ts).Where(Eq("id")).If(Ne("v")),
+			wantStmt:  `DELETE v FROM tbl USING TIMESTAMP 1700000000000000 WHERE id = :id IF v != :v`,
+			wantNames: []string{"id", "v"},
+		},
+		{
+			name:      "DeleteRow",
+			builder:   Delete("tbl").Where(Eq("id")).Existing(),
+			wantStmt:  `DELETE FROM tbl WHERE id = :id IF EXISTS`,
+			wantNames: []string{"id"},
+		},
+		{
+			name:      "UpdateIfThenExisting",
+			builder:   Update("tbl").Set("v").Where(Eq("id")).If(Eq("v")).Existing(),
+			wantStmt:  `UPDATE tbl SET v = :v WHERE id = :id IF EXISTS`,
+			wantNames: []string{"v", "id"},
+		},
+		{
+			name:      "DeleteExistingThenIf",
+			builder:   Delete("tbl").Where(Eq("id")).Existing().If(Eq("v")),
+			wantStmt:  `DELETE FROM tbl WHERE id = :id IF v = :v`,
+			wantNames: []string{"id", "v"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stmt, names := tt.builder.ToCql()
+			if stmt != tt.wantStmt {
+				t.Errorf("got statement\n%s\nwant\n%s", stmt, tt.wantStmt)
+			}
+			if !slices.Equal(names, tt.wantNames) {
+				t.Errorf("got names %v, want %v", names, tt.wantNames)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	table := &gocql.TableMetadata{
+		Keyspace: "ks",
+		Name:     "tbl",
+		Columns: map[string]*gocql.ColumnMetadata{
+			"id": {Name: "id"},
+			"v":  {Name: "v"},
+		},
+	}
+	if err := Update("ks.tbl").Set("v").Where(Eq("id")).Validate(table); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err := Select("ks.tbl").Columns("v").Where(Eq("id")).OrderBy("missing", ASC).Validate(table)
+	if err == nil || !strings.Contains(err.Error(), `"missing"`) {
+		t.Fatalf("expected an error naming the missing column, got %v", err)
+	}
+}
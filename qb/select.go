@@ -0,0 +1,164 @@
+package qb
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gocql/gocql"
+	"github.com/gocql/gocql/internal/cqlident"
+)
+
+// Order is the order of an ORDER BY clause.
+type Order bool
+
+const (
+	ASC  Order = true
+	DESC Order = false
+)
+
+type orderBy struct {
+	column string
+	order  Order
+}
+
+// SelectBuilder builds SELECT statements.
+type SelectBuilder struct {
+	table             string
+	columns           []string
+	where             []Cmp
+	groupBy           []string
+	orderBy           []orderBy
+	limit             string
+	limitName         string
+	perPartitionLimit string
+	distinct          bool
+	count             bool
+	allowFiltering    bool
+}
+
+// Select returns a builder of a SELECT statement reading from table, which
+// may be qualified by its keyspace as in "keyspace.table".
+func Select(table string) *SelectBuilder {
+	return &SelectBuilder{table: table}
+}
+
+// Columns adds columns to select. All columns are selected if none is added.
+func (b *SelectBuilder) Columns(columns ...string) *SelectBuilder {
+	b.columns = append(b.columns, columns...)
+	return b
+}
+
+// Distinct selects distinct partition keys, as in SELECT DISTINCT.
+func (b *SelectBuilder) Distinct() *SelectBuilder {
+	b.distinct = true
+	return b
+}
+
+// Count selects the number of rows, as in SELECT COUNT(*), instead of the
+// columns.
+func (b *SelectBuilder) Count() *SelectBuilder {
+	b.count = true
+	return b
+}
+
+// Where adds conditions to the WHERE clause.
+func (b *SelectBuilder) Where(cmps ...Cmp) *SelectBuilder {
+	b.where = append(b.where, cmps...)
+	return b
+}
+
+// GroupBy adds columns to the GROUP BY clause.
+func (b *SelectBuilder) GroupBy(columns ...string) *SelectBuilder {
+	b.groupBy = append(b.groupBy, columns...)
+	return b
+}
+
+// OrderBy adds a clustering column to the ORDER BY clause.
+func (b *SelectBuilder) OrderBy(column string, o Order) *SelectBuilder {
+	b.orderBy = append(b.orderBy, orderBy{column: column, order: o})
+	return b
+}
+
+// Limit sets the maximum number of rows returned.
+func (b *SelectBuilder) Limit(limit uint) *SelectBuilder {
+	b.limit, b.limitName = strconv.FormatUint(uint64(limit), 10), ""
+	return b
+}
+
+// LimitNamed sets the maximum number of rows returned to the value bound to
+// the marker name.
+func (b *SelectBuilder) LimitNamed(name string) *SelectBuilder {
+	b.limit, b.limitName = marker(name), name
+	return b
+}
+
+// PerPartitionLimit sets the maximum number of rows returned per partition.
+func (b *SelectBuilder) PerPartitionLimit(limit uint) *SelectBuilder {
+	b.perPartitionLimit = strconv.FormatUint(uint64(limit), 10)
+	return b
+}
+
+// AllowFiltering adds ALLOW FILTERING to the statement.
+func (b *SelectBuilder) AllowFiltering() *SelectBuilder {
+	b.allowFiltering = true
+	return b
+}
+
+// ToCql returns the statement and the names of its bind markers.
+func (b *SelectBuilder) ToCql() (stmt string, names []string) {
+	var cql strings.Builder
+	cql.WriteString("SELECT ")
+	if b.distinct {
+		cql.WriteString("DISTINCT ")
+	}
+	switch {
+	case b.count:
+		cql.WriteString("COUNT(*)")
+	case len(b.columns) > 0:
+		writeColumns(&cql, b.columns)
+	default:
+		cql.WriteString("*")
+	}
+	cql.WriteString(" FROM " + quoteTable(b.table))
+
+	names = writeCmps(&cql, "WHERE", b.where, names)
+	if len(b.groupBy) > 0 {
+		cql.WriteString(" GROUP BY ")
+		writeColumns(&cql, b.groupBy)
+	}
+	for i, o := range b.orderBy {
+		if i == 0 {
+			cql.WriteString(" ORDER BY ")
+		} else {
+			cql.WriteString(", ")
+		}
+		cql.WriteString(cqlident.QuoteIfNeeded(o.column))
+		if o.order == ASC {
+			cql.WriteString(" ASC")
+		} else {
+			cql.WriteString(" DESC")
+		}
+	}
+	if b.perPartitionLimit != "" {
+		cql.WriteString(" PER PARTITION LIMIT " + b.perPartitionLimit)
+	}
+	if b.limit != "" {
+		cql.WriteString(" LIMIT " + b.limit)
+		if b.limitName != "" {
+			names = append(names, b.limitName)
+		}
+	}
+	if b.allowFiltering {
+		cql.WriteString(" ALLOW FILTERING")
+	}
+	return cql.String(), names
+}
+
+// Validate checks that the columns used by the statement exist in table.
+func (b *SelectBuilder) Validate(table *gocql.TableMetadata) error {
+	ordered := make([]string, len(b.orderBy))
+	for i, o := range b.orderBy {
+		ordered[i] = o.column
+	}
+	return validateColumns(table, b.columns, cmpColumns(b.where), b.groupBy, ordered)
+}
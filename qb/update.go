@@ -0,0 +1,168 @@
+package qb
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/gocql/gocql/internal/cqlident"
+)
+
+type assignment struct {
+	column string
+	cql    string
+	name   string
+}
+
+// UpdateBuilder builds UPDATE statements.
+type UpdateBuilder struct {
+	table       string
+	assignments []assignment
+	where       []Cmp
+	ifs         []Cmp
+	using       usingClause
+	exists      bool
+}
+
+// Update returns a builder of an UPDATE statement writing to table, which
+// may be qualified by its keyspace as in "keyspace.table".
+func Update(table string) *UpdateBuilder {
+	return &UpdateBuilder{table: table}
+}
+
+func (b *UpdateBuilder) assign(column, cql, name string) *UpdateBuilder {
+	b.assignments = append(b.assignments, assignment{column: column, cql: cql, name: name})
+	return b
+}
+
+// Set adds the assignments column = :column for every column.
+func (b *UpdateBuilder) Set(columns ...string) *UpdateBuilder {
+	for _, col := range columns {
+		b.SetNamed(col, col)
+	}
+	return b
+}
+
+// SetNamed adds the assignment column = :name.
+func (b *UpdateBuilder) SetNamed(column, name string) *UpdateBuilder {
+	return b.assign(column, cqlident.QuoteIfNeeded(column)+" = "+marker(name), name)
+}
+
+// Add adds the assignment column = column + :column, which increments a
+// counter or adds elements to a set, list or map.
+func (b *UpdateBuilder) Add(column string) *UpdateBuilder {
+	return b.AddNamed(column, column)
+}
+
+// AddNamed adds the assignment column = column + :name.
+func (b *UpdateBuilder) AddNamed(column, name string) *UpdateBuilder {
+	col := cqlident.QuoteIfNeeded(column)
+	return b.assign(column, col+" = "+col+" + "+marker(name), name)
+}
+
+// Prepend adds the assignment column = :column + column, which prepends
+// elements to a list.
+func (b *UpdateBuilder) Prepend(column string) *UpdateBuilder {
+	return b.PrependNamed(column, column)
+}
+
+// PrependNamed adds the assignment column = :name + column.
+func (b *UpdateBuilder) PrependNamed(column, name string) *UpdateBuilder {
+	col := cqlident.QuoteIfNeeded(column)
+	return b.assign(column, col+" = "+marker(name)+" + "+col, name)
+}
+
+// Remove adds the assignment column = column - :column, which decrements a
+// counter or removes elements from a set, list or map.
+func (b *UpdateBuilder) Remove(column string) *UpdateBuilder {
+	return b.RemoveNamed(column, column)
+}
+
+// RemoveNamed adds the assignment column = column - :name.
+func (b *UpdateBuilder) RemoveNamed(column, name string) *UpdateBuilder {
+	col := cqlident.QuoteIfNeeded(column)
+	return b.assign(column, col+" = "+col+" - "+marker(name), name)
+}
+
+// Where adds conditions to the WHERE clause.
+func (b *UpdateBuilder) Where(cmps ...Cmp) *UpdateBuilder {
+	b.where = append(b.where, cmps...)
+	return b
+}
+
+// If adds conditions to the IF clause, making the statement a lightweight
+// transaction. It replaces a previous call to Existing, as a statement has
+// either IF conditions or IF EXISTS.
+func (b *UpdateBuilder) If(cmps ...Cmp) *UpdateBuilder {
+	b.ifs = append(b.ifs, cmps...)
+	b.exists = false
+	return b
+}
+
+// Existing makes the statement a lightweight transaction only updating the
+// row if it exists, as in IF EXISTS. It drops the conditions of previous
+// calls to If, as a statement has either IF conditions or IF EXISTS.
+func (b *UpdateBuilder) Existing() *UpdateBuilder {
+	b.exists = true
+	b.ifs = nil
+	return b
+}
+
+// TTL sets the time to live of the updated values, in seconds.
+func (b *UpdateBuilder) TTL(ttl time.Duration) *UpdateBuilder {
+	b.using.setTTL(ttl)
+	return b
+}
+
+// TTLNamed sets the time to live of the updated values to the value bound
+// to the marker name.
+func (b *UpdateBuilder) TTLNamed(name string) *UpdateBuilder {
+	b.using.setTTLNamed(name)
+	return b
+}
+
+// Timestamp sets the write timestamp of the updated values.
+func (b *UpdateBuilder) Timestamp(ts time.Time) *UpdateBuilder {
+	b.using.setTimestamp(ts)
+	return b
+}
+
+// TimestampNamed sets the write timestamp of the updated values to the
+// value, in microseconds, bound to the marker name.
+func (b *UpdateBuilder) TimestampNamed(name string) *UpdateBuilder {
+	b.using.setTimestampNamed(name)
+	return b
+}
+
+// ToCql returns the statement and the names of its bind markers.
+func (b *UpdateBuilder) ToCql() (stmt string, names []string) {
+	var cql strings.Builder
+	cql.WriteString("UPDATE " + quoteTable(b.table))
+	names = b.using.writeCql(&cql, names)
+
+	for i, a := range b.assignments {
+		if i == 0 {
+			cql.WriteString(" SET ")
+		} else {
+			cql.WriteString(", ")
+		}
+		cql.WriteString(a.cql)
+		names = append(names, a.name)
+	}
+
+	names = writeCmps(&cql, "WHERE", b.where, names)
+	names = writeCmps(&cql, "IF", b.ifs, names)
+	if b.exists {
+		cql.WriteString(" IF EXISTS")
+	}
+	return cql.String(), names
+}
+
+// Validate checks that the columns used by the statement exist in table.
+func (b *UpdateBuilder) Validate(table *gocql.TableMetadata) error {
+	assigned := make([]string, len(b.assignments))
+	for i, a := range b.assignments {
+		assigned[i] = a.column
+	}
+	return validateColumns(table, assigned, cmpColumns(b.where), cmpColumns(b.ifs))
+}
@@ -56,8 +56,26 @@ type ExecutableQuery interface {
 }
 
 type queryExecutor struct {
-	pool   *policyConnPool
-	policy HostSelectionPolicy
+	pool    *policyConnPool
+	policy  HostSelectionPolicy
+	metrics *sessionMetrics
+	tracer  RequestTracer
+	// latencyAware is the LatencyAwarePolicy of policy, if any, measuring
+	// the latency of attempts.
+	latencyAware *latencyAwarePolicy
+	// failoverFilter is the hostFailoverFilter of policy, if any, checking
+	// the host of every attempt.
+	failoverFilter hostFailoverFilter
+}
+
+// allowsHost reports whether host may serve an attempt of qry with its
+// current consistency. Hosts targeted with Query.SetHostID are always
+// allowed.
+func (q *queryExecutor) allowsHost(qry ExecutableQuery, host SelectedHost) bool {
+	if q.failoverFilter == nil || host.Info() == nil || qry.GetHostID() != "" {
+		return true
+	}
+	return q.failoverFilter.allowsHost(qry, host.Info())
 }
 
 type queryExecutionResult struct {
@@ -77,6 +95,7 @@ func (q *queryExecutor) attemptQuery(ctx context.Context, qry ExecutableQuery, m
 	token := metrics.beginAttempt()
 	iter := qry.execute(ctx, conn, metrics)
 	end := time.Now()
+	token.shard = attemptShard(conn)
 
 	// Retry accounting is page-scoped and must become visible before an
 	// observer callback can block this runner while another speculative branch
@@ -91,7 +110,14 @@ func (q *queryExecutor) attemptQuery(ctx context.Context, qry ExecutableQuery, m
 	// keyspace override) make these diverge, and Keyspace() is the single
 	// source of truth for a statement's keyspace (routing/prepared metadata,
 	// then the SetKeyspace override, then the session default).
-	qry.finishAttempt(token, qry.Keyspace(), end, iter, conn.host)
+	keyspace := qry.Keyspace()
+	if q.metrics != nil {
+		q.metrics.observeAttempt(conn.host, keyspace, end.Sub(token.start), iter.err)
+	}
+	if q.latencyAware != nil {
+		q.latencyAware.observeLatency(conn.host, token.shard, end.Sub(token.start), iter.err)
+	}
+	qry.finishAttempt(token, keyspace, end, iter, conn.host)
 
 	return iter
 }
@@ -107,7 +133,10 @@ func (q *queryExecutor) speculate(ctx context.Context, qry, releaseQry Executabl
 		case <-ticker.C:
 			releaseQry.borrowForExecution() // prevent Query.Release while this runner uses the captured execution view.
 			metrics.retain()
-			go q.run(ctx, qry, releaseQry, metrics, executionAttempts, hostIter, results)
+			if q.metrics != nil {
+				q.metrics.speculativeExecutions.Add(1)
+			}
+			go q.run(withExecution(ctx, i+1), qry, releaseQry, metrics, executionAttempts, hostIter, results)
 		case <-ctx.Done():
 			return queryExecutionResult{
 				iter:        &Iter{err: ctx.Err()},
@@ -122,6 +151,24 @@ func (q *queryExecutor) speculate(ctx context.Context, qry, releaseQry Executabl
 }
 
 func (q *queryExecutor) executeQuery(qry ExecutableQuery, metrics *queryMetrics) (*Iter, error) {
+	if q.tracer == nil {
+		return q.executeQueryContext(qry.Context(), qry, metrics)
+	}
+
+	ctx, span := q.tracer.StartRequest(qry.Context(), newRequestInfo(qry))
+	ctx = context.WithValue(ctx, requestTraceKey{}, &requestTrace{span: span})
+	iter, err := q.executeQueryContext(ctx, qry, metrics)
+	if err == nil {
+		span.End(iter.err)
+	} else {
+		span.End(err)
+	}
+	return iter, err
+}
+
+// executeQueryContext executes qry with ctx, the context of qry or the one
+// its request span was started with.
+func (q *queryExecutor) executeQueryContext(ctx context.Context, qry ExecutableQuery, metrics *queryMetrics) (*Iter, error) {
 	var hostIter NextHost
 
 	// check if the hostID is specified for the query,
@@ -145,7 +192,7 @@ func (q *queryExecutor) executeQuery(qry ExecutableQuery, metrics *queryMetrics)
 	// it is, we force the policy to NonSpeculative
 	sp := qry.speculativeExecutionPolicy()
 	if qry.GetHostID() != "" || !qry.IsIdempotent() || sp.Attempts() == 0 {
-		iter, consistency := q.do(qry.Context(), qry, metrics, nil, hostIter)
+		iter, consistency := q.do(ctx, qry, metrics, nil, hostIter)
 		if consistency != qry.GetConsistency() {
 			qry.SetConsistency(consistency)
 		}
@@ -168,7 +215,7 @@ func (q *queryExecutor) executeQuery(qry ExecutableQuery, metrics *queryMetrics)
 		return origHostIter()
 	}
 
-	ctx, cancel := context.WithCancel(qry.Context())
+	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
 	results := queryExecutionResults()
@@ -281,6 +328,21 @@ func (q *queryExecutor) do(ctx context.Context, qry ExecutableQuery, metrics *qu
 	var retryableQry RetryableQuery
 	var localAttempts int64
 
+	var trace *requestTrace
+	if q.tracer != nil {
+		trace = requestTraceFromContext(ctx)
+	}
+	// attemptSpan is the span of the last attempt, ended by endAttempt once
+	// the retry policy decided what to do next.
+	var attemptSpan AttemptSpan
+	attempt := 0
+	endAttempt := func(err error, retry RetryType) {
+		if attemptSpan != nil {
+			attemptSpan.End(AttemptResult{Err: err, Retry: retry})
+			attemptSpan = nil
+		}
+	}
+
 	execute := func(qry ExecutableQuery, selectedHost SelectedHost) (iter *Iter, retry RetryType) {
 		host := selectedHost.Info()
 		if host == nil || !host.IsUp() {
@@ -309,7 +371,17 @@ func (q *queryExecutor) do(ctx context.Context, qry ExecutableQuery, metrics *qu
 				},
 			}, RetryNextHost
 		}
-		iter = q.attemptQuery(ctx, qry, metrics, executionAttempts, &localAttempts, conn)
+		attemptCtx := ctx
+		if trace != nil {
+			attemptCtx, attemptSpan = trace.span.StartAttempt(ctx, AttemptInfo{
+				Host:      host,
+				Shard:     attemptShard(conn),
+				Attempt:   attempt,
+				Execution: trace.execution,
+			})
+		}
+		attempt++
+		iter = q.attemptQuery(attemptCtx, qry, metrics, executionAttempts, &localAttempts, conn)
 		iter.host = selectedHost.Info()
 		// Update host
 		if iter.err == nil {
@@ -343,11 +415,22 @@ func (q *queryExecutor) do(ctx context.Context, qry ExecutableQuery, metrics *qu
 		return iter, retry
 	}
 
+	// nextHost skips the hosts the consistency of qry, which retry policies
+	// may have changed since the hosts were picked, does not allow.
+	nextHost := func() SelectedHost {
+		for {
+			if h := hostIter(); h == nil || q.allowsHost(qry, h) {
+				return h
+			}
+		}
+	}
+
 	var lastErr error
-	selectedHost := hostIter()
+	selectedHost := nextHost()
 	for selectedHost != nil {
 		iter, retryType := execute(qry, selectedHost)
 		if iter.err == nil {
+			endAttempt(nil, 0)
 			return iter, qry.GetConsistency()
 		}
 		lastErr = iter.err
@@ -370,23 +453,33 @@ func (q *queryExecutor) do(ctx context.Context, qry ExecutableQuery, metrics *qu
 			shouldRetry := getShouldRetry(retryableQry)
 			qry.SetConsistency(retryableQry.GetConsistency())
 			if !shouldRetry {
+				endAttempt(iter.err, Rethrow)
 				return iter, qry.GetConsistency()
 			}
 			retryType = getRetryType(iter.err)
+			if q.metrics != nil && (retryType == Retry || retryType == RetryNextHost) {
+				q.metrics.retries.Add(1)
+			}
 		}
 
+		endAttempt(iter.err, retryType)
+
 		// If query is unsuccessful, check the error with RetryPolicy to retry
 		switch retryType {
 		case Retry:
 			iter.finalize(true)
-			// retry on the same host
+			// retry on the same host, unless the consistency set by the
+			// retry policy does not allow it anymore
+			if !q.allowsHost(qry, selectedHost) {
+				selectedHost = nextHost()
+			}
 			continue
 		case Rethrow, Ignore:
 			return iter, qry.GetConsistency()
 		case RetryNextHost:
 			iter.finalize(true)
 			// retry on the next host
-			selectedHost = hostIter()
+			selectedHost = nextHost()
 			continue
 		default:
 			// Undefined? Return nil and error, this will panic in the requester
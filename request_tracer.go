@@ -0,0 +1,163 @@
+package gocql
+
+import (
+	"context"
+	"maps"
+	"strings"
+)
+
+// RequestTracer traces requests on the client side, with a span for every
+// request and a child span for every attempt of the request, be it a retry
+// or a speculative execution. See ClusterConfig.RequestTracer.
+//
+// Unlike Tracer, which fetches the server side trace of a query, it is meant
+// to bridge the driver to distributed tracing systems, such as OpenTelemetry
+// with the github.com/gocql/gocql/otelgocql module. Implementations may also
+// implement PayloadPropagator.
+type RequestTracer interface {
+	// StartRequest starts the span of a request, before its first attempt,
+	// and returns the context the attempts of the request are started with.
+	// Every page of a query is a request.
+	StartRequest(ctx context.Context, info RequestInfo) (context.Context, RequestSpan)
+}
+
+// RequestSpan is the span of a request started by a RequestTracer.
+type RequestSpan interface {
+	// StartAttempt starts the span of an attempt of the request, before it
+	// is sent, and returns the context it is sent with. It is called
+	// concurrently by speculative executions.
+	StartAttempt(ctx context.Context, info AttemptInfo) (context.Context, AttemptSpan)
+
+	// End ends the span once the request completed, with the error of its
+	// last attempt, if any.
+	End(err error)
+}
+
+// AttemptSpan is the span of an attempt of a request.
+type AttemptSpan interface {
+	// End ends the span once the attempt completed and, if it failed, the
+	// retry policy decided what to do next.
+	End(result AttemptResult)
+}
+
+// PayloadPropagator is implemented by RequestTracers propagating the trace
+// context of attempts to the server in the custom payload of requests.
+type PayloadPropagator interface {
+	// InjectPayload adds the trace context of ctx, a context returned by
+	// RequestSpan.StartAttempt, to payload.
+	InjectPayload(ctx context.Context, payload map[string][]byte)
+}
+
+// RequestInfo describes a request traced by a RequestTracer.
+type RequestInfo struct {
+	// Statement is the statement of a query or the statements of a batch,
+	// separated by semicolons.
+	Statement string
+	// BatchSize is the number of statements of a batch, or 0 for a query.
+	BatchSize   int
+	Keyspace    string
+	Table       string
+	Consistency Consistency
+	Idempotent  bool
+	PageSize    int
+}
+
+// AttemptInfo describes an attempt of a request traced by a RequestTracer.
+type AttemptInfo struct {
+	// Host is the host the attempt is sent to.
+	Host *HostInfo
+	// Shard is the shard the attempt is sent to, or -1 if the host is not
+	// sharded.
+	Shard int
+	// Attempt is the number of the attempt in its execution, starting at 0.
+	Attempt int
+	// Execution is 0 for the attempts of the main execution of the request
+	// and n for the attempts of its n-th speculative execution.
+	Execution int
+}
+
+// AttemptResult is the result of an attempt of a request.
+type AttemptResult struct {
+	// Err is the error the attempt failed with, if any.
+	Err error
+	// Retry is the decision of the retry policy after the attempt failed.
+	// It is meaningless if Err is nil.
+	Retry RetryType
+}
+
+// requestTrace is the trace of a request, carried by the context of its
+// executions.
+type requestTrace struct {
+	span RequestSpan
+	// execution is the AttemptInfo.Execution of the attempts started with
+	// the context.
+	execution int
+}
+
+type requestTraceKey struct{}
+
+func requestTraceFromContext(ctx context.Context) *requestTrace {
+	trace, _ := ctx.Value(requestTraceKey{}).(*requestTrace)
+	return trace
+}
+
+// withExecution returns ctx with the trace of its request, if any, for the
+// n-th speculative execution.
+func withExecution(ctx context.Context, n int) context.Context {
+	if trace := requestTraceFromContext(ctx); trace != nil {
+		return context.WithValue(ctx, requestTraceKey{}, &requestTrace{span: trace.span, execution: n})
+	}
+	return ctx
+}
+
+func newRequestInfo(qry ExecutableQuery) RequestInfo {
+	info := RequestInfo{
+		Keyspace:    qry.Keyspace(),
+		Table:       qry.Table(),
+		Consistency: qry.GetConsistency(),
+		Idempotent:  qry.IsIdempotent(),
+	}
+	switch qry := qry.(type) {
+	case *Query:
+		info.Statement = qry.stmt
+		info.PageSize = qry.pageSize
+	case *Batch:
+		statements := make([]string, len(qry.Entries))
+		for i, entry := range qry.Entries {
+			statements[i] = entry.Stmt
+		}
+		info.Statement = strings.Join(statements, "; ")
+		info.BatchSize = len(statements)
+	}
+	return info
+}
+
+// attemptShard returns the AttemptInfo.Shard of the attempts sent on conn.
+func attemptShard(conn *Conn) int {
+	features := conn.getScyllaSupported()
+	if features.ShardsCount() == 0 {
+		return -1
+	}
+	return features.Shard()
+}
+
+// customPayload returns the custom payload of a request sent with ctx:
+// payload, with the trace context of ctx if the session propagates it and
+// the protocol supports custom payloads.
+func (c *Conn) customPayload(ctx context.Context, payload map[string][]byte) map[string][]byte {
+	if c.session == nil || c.version < protoVersion4 {
+		return payload
+	}
+	propagator, ok := c.session.cfg.RequestTracer.(PayloadPropagator)
+	if !ok || requestTraceFromContext(ctx) == nil {
+		return payload
+	}
+	traced := make(map[string][]byte, len(payload)+1)
+	propagator.InjectPayload(ctx, traced)
+	if len(traced) == 0 {
+		return payload
+	}
+	// The payload of the query wins over the trace context.
+	maps.Copy(traced, payload)
+	return traced
+}
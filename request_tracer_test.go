@@ -0,0 +1,204 @@
+//go:build unit
+// +build unit
+
+package gocql
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type recordingRequestTracer struct {
+	mu       sync.Mutex
+	requests []*recordingRequestSpan
+}
+
+type tracedAttempt struct {
+	info   AttemptInfo
+	result AttemptResult
+	ended  bool
+}
+
+type recordingRequestSpan struct {
+	info     RequestInfo
+	mu       sync.Mutex
+	attempts []*tracedAttempt
+	err      error
+	ended    bool
+}
+
+type tracedAttemptKey struct{}
+
+func (t *recordingRequestTracer) StartRequest(ctx context.Context, info RequestInfo) (context.Context, RequestSpan) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	span := &recordingRequestSpan{info: info}
+	t.requests = append(t.requests, span)
+	return ctx, span
+}
+
+func (t *recordingRequestTracer) InjectPayload(ctx context.Context, payload map[string][]byte) {
+	if attempt, ok := ctx.Value(tracedAttemptKey{}).(*tracedAttempt); ok {
+		payload["trace"] = []byte{byte(attempt.info.Attempt)}
+	}
+}
+
+func (s *recordingRequestSpan) StartAttempt(ctx context.Context, info AttemptInfo) (context.Context, AttemptSpan) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	attempt := &tracedAttempt{info: info}
+	s.attempts = append(s.attempts, attempt)
+	return context.WithValue(ctx, tracedAttemptKey{}, attempt), attempt
+}
+
+func (s *recordingRequestSpan) End(err error) {
+	s.err = err
+	s.ended = true
+}
+
+func (a *tracedAttempt) End(result AttemptResult) {
+	a.result = result
+	a.ended = true
+}
+
+// repeatHostPolicy picks its host for every attempt.
+type repeatHostPolicy struct {
+	HostSelectionPolicy
+	host *HostInfo
+}
+
+func (p repeatHostPolicy) Pick(ExecutableQuery) NextHost {
+	return func() SelectedHost { return staticSelectedHost{host: p.host} }
+}
+
+func TestRequestTracerRetries(t *testing.T) {
+	t.Parallel()
+
+	host := (&HostInfo{hostId: UUID{1}}).setState(NodeUp)
+	executor := newTestQueryExecutor(host)
+	executor.policy = repeatHostPolicy{HostSelectionPolicy: executor.policy, host: host}
+	tracer := &recordingRequestTracer{}
+	executor.tracer = tracer
+
+	attemptErr := errors.New("attempt failed")
+	calls := 0
+	qry := &executorTestQuery{
+		rt:          &fixedRetryPolicy{maxRetries: 1, retryType: RetryNextHost},
+		consistency: LocalQuorum,
+		idempotent:  true,
+	}
+	qry.executeFunc = func(ctx context.Context, _ *Conn) *Iter {
+		if _, ok := ctx.Value(tracedAttemptKey{}).(*tracedAttempt); !ok {
+			t.Error("attempt executed without the context of its span")
+		}
+		calls++
+		return &Iter{err: attemptErr}
+	}
+	iter, err := executor.executeQuery(qry, newQueryMetrics())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !errors.Is(iter.err, attemptErr) {
+		t.Fatalf("iter error = %v, want %v", iter.err, attemptErr)
+	}
+
+	if len(tracer.requests) != 1 {
+		t.Fatalf("traced %d requests, want 1", len(tracer.requests))
+	}
+	request := tracer.requests[0]
+	if request.info.Consistency != LocalQuorum || !request.info.Idempotent {
+		t.Errorf("request info = %+v, want LOCAL_QUORUM idempotent", request.info)
+	}
+	if !request.ended || !errors.Is(request.err, attemptErr) {
+		t.Errorf("request ended = %v with %v, want ended with %v", request.ended, request.err, attemptErr)
+	}
+	if len(request.attempts) != 2 || calls != 2 {
+		t.Fatalf("traced %d attempts of %d, want 2", len(request.attempts), calls)
+	}
+	for i, want := range []RetryType{RetryNextHost, Rethrow} {
+		attempt := request.attempts[i]
+		if attempt.info.Attempt != i || attempt.info.Execution != 0 || attempt.info.Host != host || attempt.info.Shard != -1 {
+			t.Errorf("attempt %d info = %+v", i, attempt.info)
+		}
+		if !attempt.ended || !errors.Is(attempt.result.Err, attemptErr) || attempt.result.Retry != want {
+			t.Errorf("attempt %d ended = %v with %+v, want retry %v", i, attempt.ended, attempt.result, want)
+		}
+	}
+}
+
+func TestRequestTracerSpeculativeExecution(t *testing.T) {
+	t.Parallel()
+
+	host := (&HostInfo{hostId: UUID{1}}).setState(NodeUp)
+	executor := newTestQueryExecutor(host)
+	executor.policy = repeatHostPolicy{HostSelectionPolicy: executor.policy, host: host}
+	tracer := &recordingRequestTracer{}
+	executor.tracer = tracer
+
+	release := make(chan struct{})
+	var once sync.Once
+	qry := &executorTestQuery{
+		rt:         &fixedRetryPolicy{maxRetries: 0, retryType: Rethrow},
+		spec:       &SimpleSpeculativeExecution{NumAttempts: 1, TimeoutDelay: time.Millisecond},
+		idempotent: true,
+	}
+	qry.executeFunc = func(ctx context.Context, _ *Conn) *Iter {
+		attempt := ctx.Value(tracedAttemptKey{}).(*tracedAttempt)
+		if attempt.info.Execution == 0 {
+			// The main execution completes once the speculative one did.
+			<-release
+			return &Iter{err: ctx.Err()}
+		}
+		once.Do(func() { close(release) })
+		return &Iter{}
+	}
+	iter, err := executor.executeQuery(qry, newQueryMetrics())
+	if err != nil || iter.err != nil {
+		t.Fatalf("executeQuery() = %v, %v", iter.err, err)
+	}
+
+	request := tracer.requests[0]
+	request.mu.Lock()
+	defer request.mu.Unlock()
+	executions := map[int]bool{}
+	for _, attempt := range request.attempts {
+		executions[attempt.info.Execution] = true
+	}
+	if !executions[0] || !executions[1] {
+		t.Fatalf("attempts = %+v, want attempts of executions 0 and 1", request.attempts)
+	}
+	if !request.ended || request.err != nil {
+		t.Fatalf("request ended = %v with %v, want ended without error", request.ended, request.err)
+	}
+}
+
+func TestConnCustomPayloadPropagatesTrace(t *testing.T) {
+	t.Parallel()
+
+	tracer := &recordingRequestTracer{}
+	conn := &Conn{version: protoVersion4, session: &Session{cfg: ClusterConfig{RequestTracer: tracer}}}
+	payload := map[string][]byte{"key": []byte("value")}
+
+	if got := conn.customPayload(context.Background(), payload); len(got) != 1 {
+		t.Fatalf("payload outside of a request = %v, want it unchanged", got)
+	}
+
+	_, span := tracer.StartRequest(context.Background(), RequestInfo{})
+	ctx := context.WithValue(context.Background(), requestTraceKey{}, &requestTrace{span: span})
+	ctx, _ = span.StartAttempt(ctx, AttemptInfo{Attempt: 3})
+	got := conn.customPayload(ctx, payload)
+	if len(got) != 2 || string(got["key"]) != "value" || len(got["trace"]) != 1 || got["trace"][0] != 3 {
+		t.Fatalf("payload = %v, want the payload of the query and the trace", got)
+	}
+	if len(payload) != 1 {
+		t.Fatalf("payload of the query modified: %v", payload)
+	}
+
+	conn.version = protoVersion3
+	if got := conn.customPayload(ctx, nil); got != nil {
+		t.Fatalf("payload with protocol v3 = %v, want nil", got)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"crypto/tls"
 	"errors"
 	"fmt"
+	"log/slog"
 	"math"
 	"net"
 	"strconv"
@@ -13,8 +14,6 @@ import (
 	"sync/atomic"
 	"syscall"
 	"time"
-
-	"github.com/gocql/gocql/internal/debug"
 )
 
 // ScyllaFeatures represents Scylla connection options as sent in SUPPORTED
@@ -167,7 +166,7 @@ var _ cqlProtocolExtension = &rateLimitExt{}
 
 // Factory function to deserialize and create an `rateLimitExt` instance
 // from SUPPORTED message payload.
-func newRateLimitExt(supported map[string][]string, logger StdLogger) *rateLimitExt {
+func newRateLimitExt(supported map[string][]string, logger *slog.Logger) *rateLimitExt {
 	const rateLimitErrorCode = "ERROR_CODE"
 
 	if v, found := supported[rateLimitError]; found {
@@ -179,10 +178,9 @@ func newRateLimitExt(supported map[string][]string, logger StdLogger) *rateLimit
 					errorCode int
 				)
 				if errorCode, err = strconv.Atoi(splitVal[1]); err != nil {
-					if debug.Enabled {
-						logger.Printf("scylla: failed to parse %s value %v: %s", rateLimitErrorCode, splitVal[1], err)
-						return nil
-					}
+					logger.Debug("failed to parse supported option", slog.String("option", rateLimitErrorCode),
+						slog.String("value", splitVal[1]), logError(err))
+					return nil
 				}
 				return &rateLimitExt{
 					rateLimitErrorCode: errorCode,
@@ -221,7 +219,7 @@ var _ cqlProtocolExtension = &lwtAddMetadataMarkExt{}
 
 // Factory function to deserialize and create an `lwtAddMetadataMarkExt` instance
 // from SUPPORTED message payload.
-func newLwtAddMetaMarkExt(supported map[string][]string, logger StdLogger) *lwtAddMetadataMarkExt {
+func newLwtAddMetaMarkExt(supported map[string][]string, logger *slog.Logger) *lwtAddMetadataMarkExt {
 	const lwtOptMetaBitMaskKey = "LWT_OPTIMIZATION_META_BIT_MASK"
 
 	if v, found := supported[lwtAddMetadataMarkKey]; found {
@@ -233,10 +231,9 @@ func newLwtAddMetaMarkExt(supported map[string][]string, logger StdLogger) *lwtA
 					bitMask int
 				)
 				if bitMask, err = strconv.Atoi(splitVal[1]); err != nil {
-					if debug.Enabled {
-						logger.Printf("scylla: failed to parse %s value %v: %s", lwtOptMetaBitMaskKey, splitVal[1], err)
-						return nil
-					}
+					logger.Debug("failed to parse supported option", slog.String("option", lwtOptMetaBitMaskKey),
+						slog.String("value", splitVal[1]), logError(err))
+					return nil
 				}
 				return &lwtAddMetadataMarkExt{
 					lwtOptMetaBitMask: bitMask,
@@ -296,7 +293,7 @@ func (ext *scyllaUseMetadataIDExt) serialize() map[string]string {
 	}
 }
 
-func parseSupported(supported map[string][]string, logger StdLogger) ScyllaConnectionFeatures {
+func parseSupported(supported map[string][]string, logger *slog.Logger) ScyllaConnectionFeatures {
 	const (
 		scyllaShard             = "SCYLLA_SHARD"
 		scyllaNrShards          = "SCYLLA_NR_SHARDS"
@@ -314,23 +311,20 @@ func parseSupported(supported map[string][]string, logger StdLogger) ScyllaConne
 
 	if s, ok := supported[scyllaShard]; ok {
 		if si.shard, err = strconv.Atoi(s[0]); err != nil {
-			if debug.Enabled {
-				logger.Printf("scylla: failed to parse %s value %v: %s", scyllaShard, s, err)
-			}
+			logger.Debug("failed to parse supported option", slog.String("option", scyllaShard),
+				slog.String("value", s[0]), logError(err))
 		}
 	}
 	if s, ok := supported[scyllaNrShards]; ok {
 		if si.nrShards, err = strconv.Atoi(s[0]); err != nil {
-			if debug.Enabled {
-				logger.Printf("scylla: failed to parse %s value %v: %s", scyllaNrShards, s, err)
-			}
+			logger.Debug("failed to parse supported option", slog.String("option", scyllaNrShards),
+				slog.String("value", s[0]), logError(err))
 		}
 	}
 	if s, ok := supported[scyllaShardingIgnoreMSB]; ok {
 		if si.msbIgnore, err = strconv.ParseUint(s[0], 10, 64); err != nil {
-			if debug.Enabled {
-				logger.Printf("scylla: failed to parse %s value %v: %s", scyllaShardingIgnoreMSB, s, err)
-			}
+			logger.Debug("failed to parse supported option", slog.String("option", scyllaShardingIgnoreMSB),
+				slog.String("value", s[0]), logError(err))
 		}
 	}
 
@@ -342,18 +336,16 @@ func parseSupported(supported map[string][]string, logger StdLogger) ScyllaConne
 	}
 	if s, ok := supported[scyllaShardAwarePort]; ok {
 		if shardAwarePort, err := strconv.ParseUint(s[0], 10, 16); err != nil {
-			if debug.Enabled {
-				logger.Printf("scylla: failed to parse %s value %v: %s", scyllaShardAwarePort, s, err)
-			}
+			logger.Debug("failed to parse supported option", slog.String("option", scyllaShardAwarePort),
+				slog.String("value", s[0]), logError(err))
 		} else {
 			si.shardAwarePort = uint16(shardAwarePort)
 		}
 	}
 	if s, ok := supported[scyllaShardAwarePortSSL]; ok {
 		if shardAwarePortTLS, err := strconv.ParseUint(s[0], 10, 16); err != nil {
-			if debug.Enabled {
-				logger.Printf("scylla: failed to parse %s value %v: %s", scyllaShardAwarePortSSL, s, err)
-			}
+			logger.Debug("failed to parse supported option", slog.String("option", scyllaShardAwarePortSSL),
+				slog.String("value", s[0]), logError(err))
 		} else {
 			si.shardAwarePortTLS = uint16(shardAwarePortTLS)
 		}
@@ -376,10 +368,9 @@ func parseSupported(supported map[string][]string, logger StdLogger) ScyllaConne
 	si.isScylla = hasLWT || hasRateLimit || si.isMetadataIDSupported || si.nrShards != 0
 
 	if si.partitioner != "org.apache.cassandra.dht.Murmur3Partitioner" || si.shardingAlgorithm != "biased-token-round-robin" || si.nrShards == 0 || si.msbIgnore == 0 {
-		if debug.Enabled {
-			logger.Printf("scylla: unsupported sharding configuration, partitioner=%s, algorithm=%s, no_shards=%d, msb_ignore=%d",
-				si.partitioner, si.shardingAlgorithm, si.nrShards, si.msbIgnore)
-		}
+		logger.Debug("unsupported sharding configuration", slog.String("partitioner", si.partitioner),
+			slog.String("algorithm", si.shardingAlgorithm), slog.Int("shards", si.nrShards),
+			slog.Uint64("msb_ignore", si.msbIgnore))
 		// Clear shard-routing fields only; host-wide features are preserved.
 		si.shard = 0
 		si.nrShards = 0
@@ -395,7 +386,7 @@ func parseSupported(supported map[string][]string, logger StdLogger) ScyllaConne
 // version: an extension whose wire effect is version-specific is gated on it here,
 // which is the single point that feeds both the STARTUP opt-in (startupCoordinator.startup)
 // and the framer config (connFramers.initCache), so the two cannot disagree.
-func parseCQLProtocolExtensions(supported map[string][]string, version byte, logger StdLogger) []cqlProtocolExtension {
+func parseCQLProtocolExtensions(supported map[string][]string, version byte, logger *slog.Logger) []cqlProtocolExtension {
 	exts := []cqlProtocolExtension{}
 
 	lwtExt := newLwtAddMetaMarkExt(supported, logger)
@@ -440,6 +431,8 @@ func (c *Conn) isScyllaConn() bool {
 // in a round-robin fashion.
 type scyllaConnPicker struct {
 	logger StdLogger
+	// slog is the structured logger of the picker, see slogger.
+	slog *slog.Logger
 	// disableShardAwarePortUntil is used to temporarily disable new connections to the shard-aware port temporarily
 	disableShardAwarePortUntil *atomic.Pointer[time.Time]
 	address                    string
@@ -463,11 +456,10 @@ func newScyllaConnPicker(conn *Conn, logger StdLogger) *scyllaConnPicker {
 		panic(fmt.Sprintf("scylla: %s not a sharded connection", addr))
 	}
 
-	if debug.Enabled {
-		logger.Printf("scylla: %s new conn picker sharding options %+v", addr, conn.scyllaSupported)
-	}
+	slogger(logger).Debug("new shard-aware connection picker", logHostID(conn.host), logAddress(addr),
+		slog.Int("shards", conn.scyllaSupported.nrShards), slog.Int("msb_ignore", int(conn.scyllaSupported.msbIgnore)))
 
-	return &scyllaConnPicker{
+	p := &scyllaConnPicker{
 		address:                addr,
 		hostId:                 conn.host.hostId,
 		nrShards:               conn.scyllaSupported.nrShards,
@@ -479,6 +471,8 @@ func newScyllaConnPicker(conn *Conn, logger StdLogger) *scyllaConnPicker {
 
 		disableShardAwarePortUntil: new(atomic.Pointer[time.Time]),
 	}
+	p.slog = p.newSlogger()
+	return p
 }
 
 func (p *scyllaConnPicker) Pick(t Token, qry ExecutableQuery) *Conn {
@@ -614,10 +608,8 @@ func (p *scyllaConnPicker) Put(conn *Conn) error {
 	defer p.mu.Unlock()
 
 	if nrShards != p.nrShards {
-		if debug.Enabled {
-			p.logger.Printf("scylla: %s shard count changed from %d to %d, rebuilding connection pool",
-				p.address, p.nrShards, nrShards)
-		}
+		p.slogger().Debug("shard count changed, rebuilding connection pool",
+			slog.Int("old_shards", p.nrShards), slog.Int("shards", nrShards))
 		p.handleShardCountChange(conn, nrShards)
 	} else if nrShards != len(p.conns) {
 		conns := p.conns
@@ -633,10 +625,10 @@ func (p *scyllaConnPicker) Put(conn *Conn) error {
 			// changes the source port along the way, therefore we can't trust
 			// the shard-aware port to return connection to the shard
 			// that we requested. Fall back to non-shard-aware port for some time.
-			p.logger.Printf(
-				"scylla: connection to shard-aware address %s resulted in wrong shard being assigned; please check that you are not behind a NAT or AddressTranslater which changes source ports; falling back to non-shard-aware port for %v",
-				p.address,
-				scyllaShardAwarePortFallbackDuration,
+			p.slogger().Warn(
+				"connection to shard-aware port resulted in wrong shard being assigned; please check that you are not behind a NAT or AddressTranslator which changes source ports; falling back to non-shard-aware port",
+				logShard(shard),
+				slog.Duration("fallback", scyllaShardAwarePortFallbackDuration),
 			)
 			until := time.Now().Add(scyllaShardAwarePortFallbackDuration)
 			p.disableShardAwarePortUntil.Store(&until)
@@ -644,16 +636,14 @@ func (p *scyllaConnPicker) Put(conn *Conn) error {
 			return fmt.Errorf("connection landed on %d shard that already has connection", shard)
 		} else {
 			p.excessConns = append(p.excessConns, conn)
-			if debug.Enabled {
-				p.logger.Printf("scylla: %s put shard %d excess connection total: %d missing: %d excess: %d", p.address, shard, p.nrConns, p.nrShards-p.nrConns, len(p.excessConns))
-			}
+			p.slogger().Debug("put excess connection", logShard(shard), slog.Int("connections", p.nrConns),
+				slog.Int("missing", p.nrShards-p.nrConns), slog.Int("excess", len(p.excessConns)))
 		}
 	} else {
 		p.conns[shard] = conn
 		p.nrConns++
-		if debug.Enabled {
-			p.logger.Printf("scylla: %s put shard %d connection total: %d missing: %d", p.address, shard, p.nrConns, p.nrShards-p.nrConns)
-		}
+		p.slogger().Debug("put connection", logShard(shard), slog.Int("connections", p.nrConns),
+			slog.Int("missing", p.nrShards-p.nrConns))
 	}
 
 	if p.shouldCloseExcessConns() {
@@ -668,9 +658,7 @@ func (p *scyllaConnPicker) handleShardCountChange(newConn *Conn, newShardCount i
 	oldConns := make([]*Conn, len(p.conns))
 	copy(oldConns, p.conns)
 
-	if debug.Enabled {
-		p.logger.Printf("scylla: %s handling shard topology change from %d to %d", p.address, oldShardCount, newShardCount)
-	}
+	p.slogger().Debug("handling shard topology change", slog.Int("old_shards", oldShardCount), slog.Int("shards", newShardCount))
 
 	newConns := make([]*Conn, newShardCount)
 	var toClose []*Conn
@@ -698,9 +686,21 @@ func (p *scyllaConnPicker) handleShardCountChange(newConn *Conn, newShardCount i
 		go closeConns(toClose...)
 	}
 
-	if debug.Enabled {
-		p.logger.Printf("scylla: %s migrated %d/%d connections to new shard topology, closing %d excess connections", p.address, migratedCount, len(oldConns), len(toClose))
+	p.slogger().Debug("migrated connections to new shard topology", slog.Int("migrated", migratedCount),
+		slog.Int("connections", len(oldConns)), slog.Int("closed", len(toClose)))
+}
+
+// slogger returns the structured logger of the picker, with the attributes
+// of its host, built once by newScyllaConnPicker.
+func (p *scyllaConnPicker) slogger() *slog.Logger {
+	if p.slog != nil {
+		return p.slog
 	}
+	return p.newSlogger()
+}
+
+func (p *scyllaConnPicker) newSlogger() *slog.Logger {
+	return slogger(p.logger).With(slog.String(LogKeyHostID, p.hostId.String()), logAddress(p.address))
 }
 
 func (p *scyllaConnPicker) shouldCloseExcessConns() bool {
@@ -734,14 +734,10 @@ func (p *scyllaConnPicker) Remove(conn *Conn) {
 	if conn.scyllaSupported.nrShards == 0 {
 		// It is possible for Remove to be called before the connection is added to the pool.
 		// Ignoring these connections here is safe.
-		if debug.Enabled {
-			p.logger.Printf("scylla: %s has unknown sharding state, ignoring it", p.address)
-		}
+		p.slogger().Debug("connection has unknown sharding state, ignoring it")
 		return
 	}
-	if debug.Enabled {
-		p.logger.Printf("scylla: %s remove shard %d connection", p.address, shard)
-	}
+	p.slogger().Debug("remove connection", logShard(shard))
 
 	p.mu.Lock()
 	defer p.mu.Unlock()
@@ -764,6 +760,35 @@ func (p *scyllaConnPicker) InFlight() int {
 	return result
 }
 
+func (p *scyllaConnPicker) AvailableStreams() int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	result := 0
+	for _, conn := range p.conns {
+		if conn != nil {
+			result += conn.AvailableStreams()
+		}
+	}
+	return result
+}
+
+func (p *scyllaConnPicker) ShardConnections() []int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	result := make([]int, p.nrShards)
+	for shard, conn := range p.conns {
+		if conn != nil && shard < len(result) {
+			result[shard]++
+		}
+	}
+	for _, conn := range p.excessConns {
+		if shard := conn.scyllaSupported.shard; shard < len(result) {
+			result[shard]++
+		}
+	}
+	return result
+}
+
 func (p *scyllaConnPicker) Size() (int, int) {
 	p.mu.RLock()
 	defer p.mu.RUnlock()
@@ -781,22 +806,12 @@ func (p *scyllaConnPicker) Close() {
 
 	// Close connections outside of the lock to avoid deadlocks when a
 	// connection close triggers HandleError. See scylladb/gocql#53.
+	p.slogger().Debug("closing connections", slog.Int("connections", len(conns)), slog.Int("excess", len(excessConns)))
 	if len(conns) > 0 {
-		if debug.Enabled {
-			p.logger.Printf("scylla: %s closing %d connections", p.address, len(conns))
-		}
 		go closeConns(conns...)
-	} else if debug.Enabled {
-		p.logger.Printf("scylla: %s no connections to close", p.address)
 	}
-
 	if len(excessConns) > 0 {
-		if debug.Enabled {
-			p.logger.Printf("scylla: %s closing %d excess connections", p.address, len(excessConns))
-		}
 		go closeConns(excessConns...)
-	} else if debug.Enabled {
-		p.logger.Printf("scylla: %s no excess connections to close", p.address)
 	}
 }
 
@@ -804,18 +819,13 @@ func (p *scyllaConnPicker) Close() {
 // The actual connection closing happens asynchronously outside the lock.
 func (p *scyllaConnPicker) closeExcessConnsLocked() {
 	if len(p.excessConns) == 0 {
-		if debug.Enabled {
-			p.logger.Printf("scylla: %s no excess connections to close", p.address)
-		}
 		return
 	}
 
 	conns := p.excessConns
 	p.excessConns = nil
 
-	if debug.Enabled {
-		p.logger.Printf("scylla: %s closing %d excess connections", p.address, len(conns))
-	}
+	p.slogger().Debug("closing excess connections", slog.Int("excess", len(conns)))
 	go closeConns(conns...)
 }
 
@@ -933,9 +943,7 @@ func (sd *scyllaDialer) DialShard(ctx context.Context, host *HostInfo, shardID,
 		}
 	}
 
-	if debug.Enabled {
-		sd.logger.Printf("scylla: connecting to shard %d", shardID)
-	}
+	slogger(sd.logger).Debug("connecting to shard", logHostID(host), logAddress(addr), logShard(shardID))
 
 	conn, err := sd.dialShardAware(ctx, addr, shardAwareAddr, iter)
 	if err != nil {
@@ -969,14 +977,12 @@ func (sd *scyllaDialer) dialShardAware(ctx context.Context, addr, shardAwareAddr
 				// but we may also be unlucky and the node became reachable
 				// just after we tried the first connection.
 				// We can't avoid false positives here, so I'm putting it
-				// behind a debug flag.
-				if debug.Enabled {
-					sd.logger.Printf(
-						"scylla: %s couldn't connect to shard-aware address while the non-shard-aware address %s is available; this might be an issue with ",
-						addr,
-						shardAwareAddr,
-					)
-				}
+				// behind the debug level.
+				slogger(sd.logger).Debug(
+					"couldn't connect to shard-aware address while the non-shard-aware address is available",
+					logAddress(addr),
+					slog.String("shard_aware_address", shardAwareAddr),
+				)
 			}
 			return conn, err
 		}
@@ -377,12 +377,12 @@ func TestScyllaUseMetadataIDExtParsing(t *testing.T) {
 	})
 
 	t.Run("parseCQLProtocolExtensions registers the extension only when advertised", func(t *testing.T) {
-		exts := parseCQLProtocolExtensions(map[string][]string{scyllaUseMetadataID: {}}, protoVersion4, &testLogger{})
+		exts := parseCQLProtocolExtensions(map[string][]string{scyllaUseMetadataID: {}}, protoVersion4, slogger(&testLogger{}))
 		if findCQLProtoExtByName(exts, scyllaUseMetadataID) == nil {
 			t.Error("expected parseCQLProtocolExtensions to register SCYLLA_USE_METADATA_ID when advertised")
 		}
 
-		extsAbsent := parseCQLProtocolExtensions(map[string][]string{}, protoVersion4, &testLogger{})
+		extsAbsent := parseCQLProtocolExtensions(map[string][]string{}, protoVersion4, slogger(&testLogger{}))
 		if findCQLProtoExtByName(extsAbsent, scyllaUseMetadataID) != nil {
 			t.Error("did not expect SCYLLA_USE_METADATA_ID to be registered when not advertised")
 		}
@@ -393,7 +393,7 @@ func TestScyllaUseMetadataIDExtParsing(t *testing.T) {
 	// the opt-in off the wire — the framer config follows from the same list.
 	t.Run("parseCQLProtocolExtensions leaves the extension out below v4", func(t *testing.T) {
 		advertised := map[string][]string{scyllaUseMetadataID: {}, tabletsRoutingV1: {}}
-		exts := parseCQLProtocolExtensions(advertised, protoVersion3, &testLogger{})
+		exts := parseCQLProtocolExtensions(advertised, protoVersion3, slogger(&testLogger{}))
 		if findCQLProtoExtByName(exts, scyllaUseMetadataID) != nil {
 			t.Error("did not expect SCYLLA_USE_METADATA_ID to be registered on protocol v3")
 		}
@@ -508,7 +508,7 @@ func TestParseSupported(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
-			got := parseSupported(tt.supported, &testLogger{})
+			got := parseSupported(tt.supported, slogger(&testLogger{}))
 			assert.Equal(t, tt.nrShards, got.nrShards, "nrShards")
 			assert.Equal(t, tt.msbIgnore, got.msbIgnore, "msbIgnore")
 			assert.Equal(t, tt.shardingAlgorithm, got.shardingAlgorithm, "shardingAlgorithm")
@@ -528,7 +528,7 @@ func TestIsScyllaConn(t *testing.T) {
 	makeConn := func(supported map[string][]string) *Conn {
 		conn := mockConn(0)
 		conn.supported = supported
-		conn.scyllaSupported = parseSupported(supported, &testLogger{})
+		conn.scyllaSupported = parseSupported(supported, slogger(&testLogger{}))
 		return conn
 	}
 
@@ -566,7 +566,7 @@ func TestInitConnPickerNonShardAwareScylla(t *testing.T) {
 	conn := mockConn(0)
 	conn.scyllaSupported = parseSupported(map[string][]string{
 		lwtAddMetadataMarkKey: {"LWT_OPTIMIZATION_META_BIT_MASK=8"},
-	}, &testLogger{})
+	}, slogger(&testLogger{}))
 	require.True(t, conn.isScyllaConn())
 	require.Zero(t, conn.getScyllaSupported().nrShards)
 
@@ -2,6 +2,7 @@ package inet
 
 import (
 	"net"
+	"net/netip"
 	"reflect"
 )
 
@@ -17,6 +18,14 @@ func Marshal(value any) ([]byte, error) {
 		return EncNetIP(v)
 	case *net.IP:
 		return EncNetIPr(v)
+	case netip.Addr:
+		return EncAddr(v)
+	case *netip.Addr:
+		return EncAddrR(v)
+	case netip.AddrPort:
+		return EncAddrPort(v)
+	case *netip.AddrPort:
+		return EncAddrPortR(v)
 	case [4]byte:
 		return EncArray4(v)
 	case *[4]byte:
@@ -3,6 +3,7 @@ package inet
 import (
 	"fmt"
 	"net"
+	"net/netip"
 	"reflect"
 )
 
@@ -58,6 +59,37 @@ func EncNetIPr(v *net.IP) ([]byte, error) {
 	return EncNetIP(*v)
 }
 
+// EncAddr encodes v as 4 bytes if it is an IPv4 or IPv4-mapped IPv6 address,
+// like EncNetIP, and the invalid zero Addr as null.
+func EncAddr(v netip.Addr) ([]byte, error) {
+	if !v.IsValid() {
+		return nil, nil
+	}
+	if v.Zone() != "" {
+		return nil, fmt.Errorf("failed to marshal inet: can not marshal (netip.Addr)(%s) with a zone", v)
+	}
+	return v.Unmap().AsSlice(), nil
+}
+
+func EncAddrR(v *netip.Addr) ([]byte, error) {
+	if v == nil {
+		return nil, nil
+	}
+	return EncAddr(*v)
+}
+
+// EncAddrPort encodes the address of v, the port is not stored.
+func EncAddrPort(v netip.AddrPort) ([]byte, error) {
+	return EncAddr(v.Addr())
+}
+
+func EncAddrPortR(v *netip.AddrPort) ([]byte, error) {
+	if v == nil {
+		return nil, nil
+	}
+	return EncAddrPort(*v)
+}
+
 func EncArray16(v [16]byte) ([]byte, error) {
 	tmp := make([]byte, 16)
 	copy(tmp, v[:])
@@ -110,14 +142,14 @@ func EncReflect(v reflect.Value) ([]byte, error) {
 	switch v.Kind() {
 	case reflect.Array:
 		if l := v.Len(); v.Type().Elem().Kind() != reflect.Uint8 || (l != 16 && l != 4) {
-			return nil, fmt.Errorf("failed to marshal inet: unsupported value type (%T)(%[1]v), supported types: ~[]byte, ~[4]byte, ~[16]byte, ~string, net.IP, unsetColumn", v.Interface())
+			return nil, fmt.Errorf("failed to marshal inet: unsupported value type (%T)(%[1]v), supported types: ~[]byte, ~[4]byte, ~[16]byte, ~string, net.IP, netip.Addr, netip.AddrPort, unsetColumn", v.Interface())
 		}
 		nv := reflect.New(v.Type())
 		nv.Elem().Set(v)
 		return nv.Elem().Bytes(), nil
 	case reflect.Slice:
 		if v.Type().Elem().Kind() != reflect.Uint8 {
-			return nil, fmt.Errorf("failed to marshal inet: unsupported value type (%T)(%[1]v), supported types: ~[]byte, ~[4]byte, ~[16]byte, ~string, net.IP, unsetColumn", v.Interface())
+			return nil, fmt.Errorf("failed to marshal inet: unsupported value type (%T)(%[1]v), supported types: ~[]byte, ~[4]byte, ~[16]byte, ~string, net.IP, netip.Addr, netip.AddrPort, unsetColumn", v.Interface())
 		}
 		return encReflectBytes(v)
 	case reflect.String:
@@ -126,9 +158,9 @@ func EncReflect(v reflect.Value) ([]byte, error) {
 		if v.Type().String() == "gocql.unsetColumn" {
 			return nil, nil
 		}
-		return nil, fmt.Errorf("failed to marshal inet: unsupported value type (%T)(%[1]v), supported types: ~[]byte, ~[4]byte, ~[16]byte, ~string, net.IP, unsetColumn", v.Interface())
+		return nil, fmt.Errorf("failed to marshal inet: unsupported value type (%T)(%[1]v), supported types: ~[]byte, ~[4]byte, ~[16]byte, ~string, net.IP, netip.Addr, netip.AddrPort, unsetColumn", v.Interface())
 	default:
-		return nil, fmt.Errorf("failed to marshal inet: unsupported value type (%T)(%[1]v), supported types: ~[]byte, ~[4]byte, ~[16]byte, ~string, net.IP, unsetColumn", v.Interface())
+		return nil, fmt.Errorf("failed to marshal inet: unsupported value type (%T)(%[1]v), supported types: ~[]byte, ~[4]byte, ~[16]byte, ~string, net.IP, netip.Addr, netip.AddrPort, unsetColumn", v.Interface())
 	}
 }
 
@@ -139,18 +171,18 @@ func EncReflectR(v reflect.Value) ([]byte, error) {
 	switch ev := v.Elem(); ev.Kind() {
 	case reflect.Array:
 		if l := v.Len(); ev.Type().Elem().Kind() != reflect.Uint8 || (l != 16 && l != 4) {
-			return nil, fmt.Errorf("failed to marshal inet: unsupported value type (%T)(%[1]v), supported types: ~[]byte, ~[4]byte, ~[16]byte, ~string, net.IP, unsetColumn", v.Interface())
+			return nil, fmt.Errorf("failed to marshal inet: unsupported value type (%T)(%[1]v), supported types: ~[]byte, ~[4]byte, ~[16]byte, ~string, net.IP, netip.Addr, netip.AddrPort, unsetColumn", v.Interface())
 		}
 		return v.Elem().Bytes(), nil
 	case reflect.Slice:
 		if ev.Type().Elem().Kind() != reflect.Uint8 {
-			return nil, fmt.Errorf("failed to marshal inet: unsupported value type (%T)(%[1]v), supported types: ~[]byte, ~[4]byte, ~[16]byte, ~string, net.IP, unsetColumn", v.Interface())
+			return nil, fmt.Errorf("failed to marshal inet: unsupported value type (%T)(%[1]v), supported types: ~[]byte, ~[4]byte, ~[16]byte, ~string, net.IP, netip.Addr, netip.AddrPort, unsetColumn", v.Interface())
 		}
 		return encReflectBytes(ev)
 	case reflect.String:
 		return encReflectString(ev)
 	default:
-		return nil, fmt.Errorf("failed to marshal inet: unsupported value type (%T)(%[1]v), supported types: ~[]byte, ~[4]byte, ~[16]byte, ~string, net.IP, unsetColumn", v.Interface())
+		return nil, fmt.Errorf("failed to marshal inet: unsupported value type (%T)(%[1]v), supported types: ~[]byte, ~[4]byte, ~[16]byte, ~string, net.IP, netip.Addr, netip.AddrPort, unsetColumn", v.Interface())
 	}
 }
 
@@ -3,6 +3,7 @@ package inet
 import (
 	"fmt"
 	"net"
+	"net/netip"
 	"reflect"
 )
 
@@ -18,6 +19,14 @@ func Unmarshal(data []byte, value any) error {
 		return DecNetIP(data, v)
 	case **net.IP:
 		return DecNetIPr(data, v)
+	case *netip.Addr:
+		return DecAddr(data, v)
+	case **netip.Addr:
+		return DecAddrR(data, v)
+	case *netip.AddrPort:
+		return DecAddrPort(data, v)
+	case **netip.AddrPort:
+		return DecAddrPortR(data, v)
 	case *[4]byte:
 		return DecArray4(data, v)
 	case **[4]byte:
@@ -36,7 +45,7 @@ func Unmarshal(data []byte, value any) error {
 		rv := reflect.ValueOf(value)
 		rt := rv.Type()
 		if rt.Kind() != reflect.Ptr {
-			return fmt.Errorf("failed to unmarshal inet: unsupported value type (%T)(%[1]v), supported types: ~[]byte, ~[4]byte, ~[16]byte, ~string, net.IP", v)
+			return fmt.Errorf("failed to unmarshal inet: unsupported value type (%T)(%[1]v), supported types: ~[]byte, ~[4]byte, ~[16]byte, ~string, net.IP, netip.Addr, netip.AddrPort", v)
 		}
 		if rt.Elem().Kind() != reflect.Ptr {
 			return DecReflect(data, rv)
@@ -118,6 +118,88 @@ func DecNetIPr(p []byte, v **net.IP) error {
 	return nil
 }
 
+func decAddr(p []byte) (netip.Addr, error) {
+	switch len(p) {
+	case 0:
+		return netip.Addr{}, nil
+	case 4:
+		return netip.AddrFrom4([4]byte(p)), nil
+	case 16:
+		return netip.AddrFrom16([16]byte(p)).Unmap(), nil
+	default:
+		return netip.Addr{}, errWrongDataLen
+	}
+}
+
+// DecAddr decodes IPv4-mapped IPv6 addresses as IPv4 addresses, like
+// DecNetIP, and null or empty data as the invalid zero Addr.
+func DecAddr(p []byte, v *netip.Addr) error {
+	if v == nil {
+		return errNilReference(v)
+	}
+	addr, err := decAddr(p)
+	if err != nil {
+		return err
+	}
+	*v = addr
+	return nil
+}
+
+func DecAddrR(p []byte, v **netip.Addr) error {
+	if v == nil {
+		return errNilReference(v)
+	}
+	if p == nil {
+		*v = nil
+		return nil
+	}
+	addr, err := decAddr(p)
+	if err != nil {
+		return err
+	}
+	*v = &addr
+	return nil
+}
+
+// DecAddrPort decodes the address into v and keeps its port. Null data sets
+// v to the zero AddrPort.
+func DecAddrPort(p []byte, v *netip.AddrPort) error {
+	if v == nil {
+		return errNilReference(v)
+	}
+	if p == nil {
+		*v = netip.AddrPort{}
+		return nil
+	}
+	addr, err := decAddr(p)
+	if err != nil {
+		return err
+	}
+	*v = netip.AddrPortFrom(addr, v.Port())
+	return nil
+}
+
+func DecAddrPortR(p []byte, v **netip.AddrPort) error {
+	if v == nil {
+		return errNilReference(v)
+	}
+	if p == nil {
+		*v = nil
+		return nil
+	}
+	addr, err := decAddr(p)
+	if err != nil {
+		return err
+	}
+	var port uint16
+	if *v != nil {
+		port = (*v).Port()
+	}
+	tmp := netip.AddrPortFrom(addr, port)
+	*v = &tmp
+	return nil
+}
+
 func DecArray4(p []byte, v *[4]byte) error {
 	if v == nil {
 		return errNilReference(v)
@@ -255,7 +337,7 @@ func DecReflect(p []byte, v reflect.Value) error {
 	switch v = v.Elem(); v.Kind() {
 	case reflect.Array:
 		if v.Type().Elem().Kind() != reflect.Uint8 {
-			return fmt.Errorf("failed to unmarshal inet: unsupported value type (%T)(%[1]v), supported types: ~[]byte, ~[4]byte, ~[16]byte, ~string, net.IP", v.Interface())
+			return fmt.Errorf("failed to unmarshal inet: unsupported value type (%T)(%[1]v), supported types: ~[]byte, ~[4]byte, ~[16]byte, ~string, net.IP, netip.Addr, netip.AddrPort", v.Interface())
 		}
 		switch v.Len() {
 		case 4:
@@ -263,17 +345,17 @@ func DecReflect(p []byte, v reflect.Value) error {
 		case 16:
 			return decReflectArray16(p, v)
 		default:
-			return fmt.Errorf("failed to unmarshal inet: unsupported value type (%T)(%[1]v), supported types: ~[]byte, ~[4]byte, ~[16]byte, ~string, net.IP", v.Interface())
+			return fmt.Errorf("failed to unmarshal inet: unsupported value type (%T)(%[1]v), supported types: ~[]byte, ~[4]byte, ~[16]byte, ~string, net.IP, netip.Addr, netip.AddrPort", v.Interface())
 		}
 	case reflect.Slice:
 		if v.Type().Elem().Kind() != reflect.Uint8 {
-			return fmt.Errorf("failed to unmarshal inet: unsupported value type (%T)(%[1]v), supported types: ~[]byte, ~[4]byte, ~[16]byte, ~string, net.IP", v.Interface())
+			return fmt.Errorf("failed to unmarshal inet: unsupported value type (%T)(%[1]v), supported types: ~[]byte, ~[4]byte, ~[16]byte, ~string, net.IP, netip.Addr, netip.AddrPort", v.Interface())
 		}
 		return decReflectBytes(p, v)
 	case reflect.String:
 		return decReflectString(p, v)
 	default:
-		return fmt.Errorf("failed to unmarshal inet: unsupported value type (%T)(%[1]v), supported types: ~[]byte, ~[4]byte, ~[16]byte, ~string, net.IP", v.Interface())
+		return fmt.Errorf("failed to unmarshal inet: unsupported value type (%T)(%[1]v), supported types: ~[]byte, ~[4]byte, ~[16]byte, ~string, net.IP, netip.Addr, netip.AddrPort", v.Interface())
 	}
 }
 
@@ -286,7 +368,7 @@ func DecReflectR(p []byte, v reflect.Value) error {
 	switch evt := ev.Type().Elem(); evt.Kind() {
 	case reflect.Array:
 		if evt.Elem().Kind() != reflect.Uint8 {
-			return fmt.Errorf("failed to marshal inet: unsupported value type (%T)(%[1]v), supported types: ~[]byte, ~[4]byte, ~[16]byte, ~string, net.IP", v.Interface())
+			return fmt.Errorf("failed to marshal inet: unsupported value type (%T)(%[1]v), supported types: ~[]byte, ~[4]byte, ~[16]byte, ~string, net.IP, netip.Addr, netip.AddrPort", v.Interface())
 		}
 		switch ev.Len() {
 		case 4:
@@ -294,17 +376,17 @@ func DecReflectR(p []byte, v reflect.Value) error {
 		case 16:
 			return decReflectArray16R(p, ev)
 		default:
-			return fmt.Errorf("failed to unmarshal inet: unsupported value type (%T)(%[1]v), supported types: ~[]byte, ~[4]byte, ~[16]byte, ~string, net.IP", v.Interface())
+			return fmt.Errorf("failed to unmarshal inet: unsupported value type (%T)(%[1]v), supported types: ~[]byte, ~[4]byte, ~[16]byte, ~string, net.IP, netip.Addr, netip.AddrPort", v.Interface())
 		}
 	case reflect.Slice:
 		if evt.Elem().Kind() != reflect.Uint8 {
-			return fmt.Errorf("failed to marshal inet: unsupported value type (%T)(%[1]v), supported types: ~[]byte, ~[4]byte, ~[16]byte, ~string, net.IP", v.Interface())
+			return fmt.Errorf("failed to marshal inet: unsupported value type (%T)(%[1]v), supported types: ~[]byte, ~[4]byte, ~[16]byte, ~string, net.IP, netip.Addr, netip.AddrPort", v.Interface())
 		}
 		return decReflectBytesR(p, ev)
 	case reflect.String:
 		return decReflectStringR(p, ev)
 	default:
-		return fmt.Errorf("failed to unmarshal inet: unsupported value type (%T)(%[1]v), supported types: ~[]byte, ~[4]byte, ~[16]byte, ~string, net.IP", v.Interface())
+		return fmt.Errorf("failed to unmarshal inet: unsupported value type (%T)(%[1]v), supported types: ~[]byte, ~[4]byte, ~[16]byte, ~string, net.IP, netip.Addr, netip.AddrPort", v.Interface())
 	}
 }
 
@@ -30,10 +30,13 @@ import (
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"iter"
+	"log/slog"
 	"maps"
 	mrand "math/rand/v2"
 	"net"
 	"slices"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -70,6 +73,8 @@ type Session struct {
 	connectObserver      ConnectObserver
 	frameObserver        FrameHeaderObserver
 	streamObserver       StreamObserver
+	metrics              *sessionMetrics
+	codecs               *CodecRegistry
 	initErr              error
 	nodeEvents           *eventDebouncer
 	stmtsLRU             *preparedLRU
@@ -125,7 +130,7 @@ func resolveInitialEndpoints(resolver DNSResolver, addrs []string, defaultPort i
 		if err != nil {
 			err = fmt.Errorf("failed to resolve endpoint %q: %w", hostaddr, err)
 			errs = append(errs, err)
-			logger.Println(err.Error())
+			slogger(logger).Warn("failed to resolve endpoint", logAddress(hostaddr), logError(err))
 			continue
 		}
 		hosts = append(hosts, resolvedHosts...)
@@ -155,6 +160,8 @@ func newSessionCommon(cfg ClusterConfig) (*Session, error) {
 		cancel:            cancel,
 		logger:            cfg.logger(),
 		addressTranslator: cfg.AddressTranslator,
+		codecs:            cfg.Codecs.clone(),
+		metrics:           newSessionMetrics(cfg.Metrics),
 		readyCh:           make(chan struct{}, 1),
 	}
 
@@ -178,7 +185,7 @@ func newSessionCommon(cfg ClusterConfig) (*Session, error) {
 
 	s.metadataDescriber = newMetadataDescriber(s)
 
-	s.eventBus = eventbus.New[events.Event](cfg.EventBusConfig, cfg.Logger)
+	s.eventBus = eventbus.New[events.Event](cfg.EventBusConfig, s.logger)
 	if err = s.eventBus.Start(); err != nil {
 		return nil, fmt.Errorf("gocql: unable to create session: %v", err)
 	}
@@ -202,8 +209,13 @@ func newSessionCommon(cfg ClusterConfig) (*Session, error) {
 	s.policy.Init(s)
 
 	s.executor = &queryExecutor{
-		pool:   s.pool,
-		policy: cfg.PoolConfig.HostSelectionPolicy,
+		pool:    s.pool,
+		policy:  cfg.PoolConfig.HostSelectionPolicy,
+		metrics: s.metrics,
+		tracer:  cfg.RequestTracer,
+
+		latencyAware:   findLatencyAwarePolicy(cfg.PoolConfig.HostSelectionPolicy),
+		failoverFilter: findHostFailoverFilter(cfg.PoolConfig.HostSelectionPolicy),
 	}
 
 	s.queryObserver = cfg.QueryObserver
@@ -244,7 +256,7 @@ const sessionIDStartupKey = "SESSION_ID"
 func newSessionID(logger StdLogger) string {
 	id, err := RandomUUID()
 	if err != nil {
-		logger.Printf("gocql: unable to generate a random session id, falling back to a pseudo-random one: %v", err)
+		slogger(logger).Warn("unable to generate a random session id, falling back to a pseudo-random one", logError(err))
 		id = pseudoRandomUUID()
 	}
 	return id.String()
@@ -358,9 +370,7 @@ func (s *Session) init() error {
 				proto, err = s.control.discoverProtocol(hosts)
 				if err != nil {
 					err = fmt.Errorf("unable to discover protocol version: %w\n", err)
-					if debug.Enabled {
-						s.logger.Println(err.Error())
-					}
+					slogger(s.logger).Debug("unable to discover protocol version", logError(err))
 					continue
 				} else if proto == 0 {
 					return errors.New("unable to discovery protocol version")
@@ -373,9 +383,7 @@ func (s *Session) init() error {
 
 			if err = s.control.connect(hosts); err != nil {
 				err = fmt.Errorf("unable to create control connection: %w\n", err)
-				if debug.Enabled {
-					s.logger.Println(err.Error())
-				}
+				slogger(s.logger).Debug("unable to create control connection", logError(err))
 				continue
 			}
 			break
@@ -555,12 +563,12 @@ func (s *Session) reconnectDownedHosts(intv time.Duration) {
 			hosts := s.hostSource.getHostsList()
 
 			// Print session.hostSource for debug.
-			if debug.Enabled {
-				buf := bytes.NewBufferString("Session.hostSource:")
+			if logger := slogger(s.logger); logger.Enabled(s.ctx, slog.LevelDebug) {
+				var buf bytes.Buffer
 				for _, h := range hosts {
 					buf.WriteString("[" + h.ConnectAddress().String() + ":" + h.State().String() + "]")
 				}
-				s.logger.Println(buf.String())
+				logger.Debug("reconnecting downed hosts", slog.String("hosts", buf.String()))
 			}
 
 			for _, h := range hosts {
@@ -1140,12 +1148,12 @@ func (s *Session) MapExecuteBatchCAS(batch *Batch, dest map[string]any) (applied
 		return false, iter, iter.err
 	}
 	// check if [applied] was returned, otherwise it might not be CAS
-	if appliedRaw, ok := dest["[applied]"]; ok {
+	if appliedRaw, ok := dest[casAppliedColumn]; ok {
 		applied, ok = appliedRaw.(bool)
 		if !ok {
-			s.logger.Println("encountered non-bool \"[applied]\" key")
+			slogger(s.logger).Warn("encountered non-bool \"[applied]\" key")
 		}
-		delete(dest, "[applied]")
+		delete(dest, casAppliedColumn)
 	}
 
 	// we usually close here, but instead of closing, just returin an error
@@ -1165,9 +1173,8 @@ func translateAddressPort(addressTranslator AddressTranslator, host *HostInfo, a
 	translatorV2, ok := addressTranslator.(AddressTranslatorV2)
 	if !ok {
 		newAddr, newPort := addressTranslator.Translate(addr.Address, int(addr.Port))
-		if debug.Enabled {
-			logger.Printf("gocql: translated address %q to '%v:%d'", addr, newAddr, newPort)
-		}
+		slogger(logger).Debug("translated address", logAddress(addr.ToNetAddr()),
+			slog.String("translated_address", net.JoinHostPort(newAddr.String(), strconv.Itoa(newPort))))
 		return AddressPort{
 			Address: newAddr,
 			Port:    uint16(newPort),
@@ -1175,14 +1182,10 @@ func translateAddressPort(addressTranslator AddressTranslator, host *HostInfo, a
 	}
 	newAddr, err := translatorV2.TranslateHost(host, addr)
 	if err != nil {
-		if debug.Enabled {
-			logger.Printf("gocql: failed to translate address %q: %s", addr, err.Error())
-		}
+		slogger(logger).Debug("failed to translate address", logAddress(addr.ToNetAddr()), logError(err))
 		return addr, err
 	}
-	if debug.Enabled {
-		logger.Printf("gocql: translated address %q to %q", addr, newAddr)
-	}
+	slogger(logger).Debug("translated address", logAddress(addr.ToNetAddr()), slog.String("translated_address", newAddr.ToNetAddr()))
 	return newAddr, nil
 }
 
@@ -1215,6 +1218,10 @@ type AttemptMetric struct {
 	// Host is the host where the attempt was executed.
 	Host *HostInfo
 
+	// Shard is the shard where the attempt was executed, or -1 if the host
+	// is not sharded.
+	Shard int
+
 	// Attempt is the launch-order index of this attempt.
 	Attempt int
 
@@ -1562,6 +1569,8 @@ type attemptToken struct {
 	start   time.Time
 	metrics *queryMetrics
 	attempt int
+	// shard is the shard the attempt was executed on, or -1.
+	shard int
 }
 
 func (qm *queryMetrics) beginAttempt() attemptToken {
@@ -1570,6 +1579,7 @@ func (qm *queryMetrics) beginAttempt() attemptToken {
 		metrics: qm,
 		attempt: int(qm.nextAttempt.Add(1) - 1),
 		start:   time.Now(),
+		shard:   -1,
 	}
 }
 
@@ -1783,6 +1793,7 @@ func (qm *queryMetrics) finishAttempt(token attemptToken, latency time.Duration,
 		qm.history = qm.history.withAttempt(AttemptMetric{
 			Attempt: token.attempt,
 			Host:    host,
+			Shard:   token.shard,
 			Latency: addLatencyNanos,
 		})
 	}
@@ -1811,8 +1822,18 @@ type Query struct {
 	// getKeyspace is field so that it can be overriden in tests
 	getKeyspace func() string
 	// routingInfo is a pointer because Query can be copied and copyable struct can't hold a mutex.
-	routingInfo       *queryRoutingInfo
-	binding           func(q *QueryInfo) ([]any, error)
+	routingInfo *queryRoutingInfo
+	binding     func(q *QueryInfo) ([]any, error)
+	// preparedRouting is the routing key info of queries bound from a
+	// PreparedStatement, which GetRoutingKey then does not look up again.
+	preparedRouting *routingKeyInfo
+	// bindMarkers are the bind markers of queries bound from a
+	// PreparedStatement, which DebugString formats the values with.
+	bindMarkers []ColumnInfo
+	// routingToken, when set, is the token the query is routed by in place of
+	// the token of its routing key. It is used by token range scans, which
+	// have no partition key to hash.
+	routingToken      *int64Token
 	executionAttempts *atomic.Int64
 	metricsOwner      queryMetricsOwner
 	nowInSecondsValue *int
@@ -2068,6 +2089,9 @@ func cloneQuery(q *Query, metrics *queryMetrics) *Query {
 		getKeyspace:       q.getKeyspace,
 		routingInfo:       q.routingInfo,
 		binding:           q.binding,
+		preparedRouting:   q.preparedRouting,
+		bindMarkers:       q.bindMarkers,
+		routingToken:      q.routingToken,
 		// The proto v5 per-statement options travel with the clone. Every
 		// execution of an idempotent query with a speculative policy runs from a
 		// clone, as does every page after the first (cloneQueryForNextPage) and
@@ -2131,6 +2155,10 @@ func (q *Query) finishAttempt(token attemptToken, keyspace string, end time.Time
 			extendedObserver.ObserveQueryWithAttemptMetrics(q.Context(), ObservedQueryWithAttemptMetrics{
 				ObservedQuery:  observed,
 				AttemptMetrics: attemptMetrics,
+				Table:          q.Table(),
+				Consistency:    q.GetConsistency(),
+				PageSize:       q.pageSize,
+				BindMarkers:    q.bindMarkers,
 			})
 		} else {
 			q.observer.ObserveQuery(q.Context(), observed)
@@ -2207,10 +2235,15 @@ func (q *Query) GetRoutingKey() ([]byte, error) {
 		return nil, nil
 	}
 
-	// try to determine the routing key
-	routingKeyInfo, err := q.session.routingKeyInfo(q.Context(), q.stmt, q.keyspace, q.requestTimeout)
-	if err != nil {
-		return nil, err
+	// try to determine the routing key, unless it was resolved once and for
+	// all by Session.Prepare
+	routingKeyInfo := q.preparedRouting
+	if routingKeyInfo == nil {
+		var err error
+		routingKeyInfo, err = q.session.routingKeyInfo(q.Context(), q.stmt, q.keyspace, q.requestTimeout)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	if routingKeyInfo != nil {
@@ -2221,7 +2254,15 @@ func (q *Query) GetRoutingKey() ([]byte, error) {
 		q.routingInfo.table = routingKeyInfo.table
 		q.routingInfo.mu.Unlock()
 	}
-	return createRoutingKey(routingKeyInfo, q.values)
+	return createRoutingKey(q.session.codecs, routingKeyInfo, q.values)
+}
+
+// queryRoutingToken returns the token qry is explicitly routed by, if any.
+func queryRoutingToken(qry ExecutableQuery) (int64Token, bool) {
+	if q, ok := qry.(*Query); ok && q.routingToken != nil {
+		return *q.routingToken, true
+	}
+	return 0, false
 }
 
 func (q *Query) shouldPrepare() bool {
@@ -2560,12 +2601,12 @@ func (q *Query) MapScanCAS(dest map[string]any) (applied bool, err error) {
 		return false, iter.Close()
 	}
 	// check if [applied] was returned, otherwise it might not be CAS
-	if appliedRaw, ok := dest["[applied]"]; ok {
+	if appliedRaw, ok := dest[casAppliedColumn]; ok {
 		applied, ok = appliedRaw.(bool)
 		if !ok {
-			q.session.logger.Println("encountered non-bool \"[applied]\" key")
+			slogger(q.session.logger).Warn("encountered non-bool \"[applied]\" key")
 		}
-		delete(dest, "[applied]")
+		delete(dest, casAppliedColumn)
 	}
 
 	return applied, iter.Close()
@@ -2691,11 +2732,21 @@ type Iter struct {
 	releasedCustomPayload map[string][]byte
 	next                  *nextIter
 	host                  *HostInfo
+	// structPlan caches the column-to-field mapping computed by StructScan
+	// for the current result metadata.
+	structPlan *structScanPlan
 	// allWarnings accumulates warnings across page boundaries.
 	// When a page's framer is released during fetchNextPage(), its warnings
 	// are appended here so they are not lost.
 	allWarnings []string
 
+	// codecs are the codecs of the session that executed the query.
+	codecs *CodecRegistry
+
+	// borrowed are the columns of the current row handed out without
+	// copying, which are poisoned when iter advances in debug builds.
+	borrowed [][]byte
+
 	// scanColumns caches the column names computed by RowData() so that
 	// MapScan does not recompute them on every row. Populated lazily on
 	// the first call to getScanColumns().
@@ -2706,6 +2757,7 @@ type Iter struct {
 	closed            int32
 	warningsHandled   int32
 	warningQueryOwned bool
+	strictStruct      bool
 }
 
 // Host returns the host which the query was sent to.
@@ -2729,6 +2781,8 @@ func (iter *Iter) copyPageData(src *Iter) {
 	iter.next = src.next
 	iter.host = src.host
 	iter.meta = src.meta
+	// Column names cached by RowData() belong to the previous page's metadata.
+	iter.scanColumns = nil
 	iter.allWarnings = append(iter.allWarnings, src.allWarnings...)
 	iter.releasedCustomPayload = src.releasedCustomPayload
 	iter.pos = src.pos
@@ -2931,6 +2985,11 @@ type iterScanner struct {
 
 func (is *iterScanner) Next() bool {
 	iter := is.iter
+	if debug.Enabled {
+		for _, col := range is.cols {
+			debug.Poison(col)
+		}
+	}
 	if iter.err != nil {
 		iter.finalize(true)
 		return false
@@ -2969,7 +3028,7 @@ func (is *iterScanner) Next() bool {
 	return true
 }
 
-func scanColumn(p []byte, col *ColumnInfo, dest []any) (int, error) {
+func scanColumn(codecs *CodecRegistry, p []byte, col *ColumnInfo, dest []any) (int, error) {
 	if dest[0] == nil {
 		return 1, nil
 	}
@@ -2981,12 +3040,12 @@ func scanColumn(p []byte, col *ColumnInfo, dest []any) (int, error) {
 		count := len(tuple.Elems)
 		// here we pass in a slice of the struct which has the number number of
 		// values as elements in the tuple
-		if err := Unmarshal(col.TypeInfo, p, dest[:count]); err != nil {
+		if err := unmarshal(codecs, col.TypeInfo, p, dest[:count]); err != nil {
 			return 0, err
 		}
 		return count, nil
 	} else {
-		if err := Unmarshal(col.TypeInfo, p, dest[0]); err != nil {
+		if err := unmarshal(codecs, col.TypeInfo, p, dest[0]); err != nil {
 			return 0, err
 		}
 		return 1, nil
@@ -3011,7 +3070,7 @@ func (is *iterScanner) Scan(dest ...any) error {
 	var err error
 	for j := range iter.meta.columns {
 		var n int
-		n, err = scanColumn(is.cols[j], &iter.meta.columns[j], dest[i:])
+		n, err = scanColumn(iter.codecs, is.cols[j], &iter.meta.columns[j], dest[i:])
 		if err != nil {
 			break
 		}
@@ -3040,6 +3099,114 @@ func (iter *Iter) Scanner() Scanner {
 	return &iterScanner{iter: iter, cols: make([][]byte, len(iter.meta.columns))}
 }
 
+// Row is a single result row yielded by Iter.Rows. It is only valid for the
+// duration of the loop body that received it and may be scanned at most once.
+type Row struct {
+	scanner *iterScanner
+}
+
+// Columns returns the name and type of the columns of the row.
+func (r *Row) Columns() []ColumnInfo {
+	return r.scanner.iter.meta.columns
+}
+
+// Scan copies the row's columns into dest, following the same rules as
+// Iter.Scan. Use nil as a dest value to skip the corresponding column.
+func (r *Row) Scan(dest ...any) error {
+	return r.scanner.Scan(dest...)
+}
+
+// RawRow is the serialized values of a row, borrowed from the response buffer
+// it was read from.
+type RawRow struct {
+	Columns []ColumnInfo
+	// Values holds the serialized value of each column, nil for null. The
+	// values are only valid until the loop advances to the next row and must
+	// be copied to be retained.
+	Values [][]byte
+}
+
+// Raw returns the serialized values of the row's columns without copying
+// them, see RawRow. Raw may be used alongside Scan.
+func (r *Row) Raw() RawRow {
+	return RawRow{Columns: r.scanner.iter.meta.columns, Values: r.scanner.cols}
+}
+
+// Rows returns an iterator over the remaining rows of iter, suitable for use
+// with a range-over-func loop:
+//
+//	for row, err := range session.Query(`SELECT name, age FROM users`).Iter().Rows() {
+//		if err != nil {
+//			return err
+//		}
+//		var name string
+//		var age int
+//		if err := row.Scan(&name, &age); err != nil {
+//			return err
+//		}
+//	}
+//
+// Pages are fetched transparently as the loop advances. The iter is closed
+// when the loop finishes or is stopped early; if the query or the iteration
+// failed, the error is yielded once, with a nil Row, as the last element.
+// The iter should NOT be used again after calling this method.
+func (iter *Iter) Rows() iter.Seq2[*Row, error] {
+	return func(yield func(*Row, error) bool) {
+		if iter == nil {
+			return
+		}
+		scanner := &iterScanner{iter: iter, cols: make([][]byte, len(iter.meta.columns))}
+		row := &Row{scanner: scanner}
+		for scanner.Next() {
+			if !yield(row, nil) {
+				iter.Close()
+				return
+			}
+		}
+		if err := iter.Close(); err != nil {
+			yield(nil, err)
+		}
+	}
+}
+
+// All returns an iterator over the remaining rows of iter, yielding each row
+// as RowData with freshly allocated values, so rows may be retained after the
+// loop advances:
+//
+//	for row, err := range session.Query(`SELECT * FROM users`).Iter().All() {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(row.Columns, row.Values)
+//	}
+//
+// Like Rows, All fetches pages transparently, closes the iter when the loop
+// finishes or is stopped early and yields any error once as the last element.
+// The iter should NOT be used again after calling this method.
+func (iter *Iter) All() iter.Seq2[RowData, error] {
+	return func(yield func(RowData, error) bool) {
+		for row, err := range iter.Rows() {
+			if err != nil {
+				yield(RowData{}, err)
+				return
+			}
+			data, err := iter.RowData()
+			if err == nil {
+				err = row.Scan(data.Values...)
+			}
+			if err != nil {
+				iter.err = err
+				iter.Close()
+				yield(RowData{}, err)
+				return
+			}
+			if !yield(data, nil) {
+				return
+			}
+		}
+	}
+}
+
 func (iter *Iter) readColumn() ([]byte, error) {
 	if iter.framer == nil {
 		return nil, errors.New("no framer available")
@@ -3047,15 +3214,12 @@ func (iter *Iter) readColumn() ([]byte, error) {
 	return iter.framer.ReadBytesInternal()
 }
 
-// Scan consumes the next row of the iterator and copies the columns of the
-// current row into the values pointed at by dest. Use nil as a dest value
-// to skip the corresponding column. Scan might send additional queries
-// to the database to retrieve the next set of rows if paging was enabled.
-//
-// Scan returns true if the row was successfully unmarshaled or false if the
-// end of the result set was reached or if an error occurred. Close should
-// be called afterwards to retrieve any potential errors.
-func (iter *Iter) Scan(dest ...any) bool {
+// nextRow positions iter at the start of the next row, fetching the next page
+// if the current one is exhausted and prefetching the one after it when due.
+// It returns false, finalizing iter, when there are no more rows or an error
+// occurred.
+func (iter *Iter) nextRow() bool {
+	iter.poisonBorrowed()
 	if iter.err != nil {
 		iter.finalize(true)
 		return false
@@ -3075,6 +3239,21 @@ func (iter *Iter) Scan(dest ...any) bool {
 	if iter.next != nil && iter.pos >= iter.next.pos {
 		iter.next.fetchAsync()
 	}
+	return true
+}
+
+// Scan consumes the next row of the iterator and copies the columns of the
+// current row into the values pointed at by dest. Use nil as a dest value
+// to skip the corresponding column. Scan might send additional queries
+// to the database to retrieve the next set of rows if paging was enabled.
+//
+// Scan returns true if the row was successfully unmarshaled or false if the
+// end of the result set was reached or if an error occurred. Close should
+// be called afterwards to retrieve any potential errors.
+func (iter *Iter) Scan(dest ...any) bool {
+	if !iter.nextRow() {
+		return false
+	}
 
 	// currently only support scanning into an expand tuple, such that its the same
 	// as scanning in more values from a single column
@@ -3094,8 +3273,11 @@ func (iter *Iter) Scan(dest ...any) bool {
 			iter.finalize(true)
 			return false
 		}
+		if debug.Enabled {
+			iter.borrowed = append(iter.borrowed, colBytes)
+		}
 
-		n, err := scanColumn(colBytes, &iter.meta.columns[j], dest[i:])
+		n, err := scanColumn(iter.codecs, colBytes, &iter.meta.columns[j], dest[i:])
 		if err != nil {
 			iter.err = err
 			iter.finalize(true)
@@ -3108,6 +3290,54 @@ func (iter *Iter) Scan(dest ...any) bool {
 	return true
 }
 
+// ScanRaw consumes the next row of the iterator and calls fn with the
+// serialized values of its columns, a nil slice for null. The values are
+// borrowed from the response buffer rather than copied: they are only valid
+// until fn returns and must be copied to be retained. This avoids the cost of
+// unmarshaling for readers that process values, such as blobs, in place.
+//
+// Like Scan, ScanRaw returns false at the end of the result set or if an
+// error occurred, including an error returned by fn, which Close then
+// returns.
+func (iter *Iter) ScanRaw(fn func(cols [][]byte) error) bool {
+	if !iter.nextRow() {
+		return false
+	}
+
+	cols := iter.borrowed[:0]
+	for range iter.meta.columns {
+		colBytes, err := iter.readColumn()
+		if err != nil {
+			iter.err = err
+			iter.finalize(true)
+			return false
+		}
+		cols = append(cols, colBytes)
+	}
+	iter.borrowed = cols
+	iter.pos++
+
+	if err := fn(cols); err != nil {
+		iter.err = err
+		iter.finalize(true)
+		return false
+	}
+	return true
+}
+
+// poisonBorrowed overwrites the columns of the previous row handed out
+// without copying, in debug builds, as they are no longer valid.
+func (iter *Iter) poisonBorrowed() {
+	if !debug.Enabled {
+		return
+	}
+	for i, col := range iter.borrowed {
+		debug.Poison(col)
+		iter.borrowed[i] = nil
+	}
+	iter.borrowed = iter.borrowed[:0]
+}
+
 // GetCustomPayload returns any parsed custom payload results if given in the
 // response from Cassandra. The returned map is a shallow copy and is safe to
 // retain after the Iter advances or is closed.
@@ -3625,6 +3855,7 @@ func (b *Batch) finishAttempt(token attemptToken, keyspace string, end time.Time
 		extendedObserver.ObserveBatchWithAttemptMetrics(b.Context(), ObservedBatchWithAttemptMetrics{
 			ObservedBatch:  observed,
 			AttemptMetrics: attemptMetrics,
+			Consistency:    b.GetConsistency(),
 		})
 	} else {
 		b.observer.ObserveBatch(b.Context(), observed)
@@ -3657,7 +3888,7 @@ func (b *Batch) GetRoutingKey() ([]byte, error) {
 		b.routingInfo.mu.Unlock()
 	}
 
-	return createRoutingKey(routingKeyInfo, entry.Args)
+	return createRoutingKey(b.session.codecs, routingKeyInfo, entry.Args)
 }
 
 // GetRequestTimeout returns time driver waits for single server response
@@ -3673,14 +3904,15 @@ func (b *Batch) SetRequestTimeout(timeout time.Duration) *Batch {
 	return b
 }
 
-func createRoutingKey(routingKeyInfo *routingKeyInfo, values []any) ([]byte, error) {
+func createRoutingKey(codecs *CodecRegistry, routingKeyInfo *routingKeyInfo, values []any) ([]byte, error) {
 	if routingKeyInfo == nil {
 		return nil, nil
 	}
 
 	if len(routingKeyInfo.indexes) == 1 {
 		// single column routing key
-		routingKey, err := Marshal(
+		routingKey, err := marshal(
+			codecs,
 			routingKeyInfo.types[0],
 			values[routingKeyInfo.indexes[0]],
 		)
@@ -3697,7 +3929,8 @@ func createRoutingKey(routingKeyInfo *routingKeyInfo, values []any) ([]byte, err
 	var backing [256]byte
 	buf := backing[:0]
 	for i := range routingKeyInfo.indexes {
-		encoded, err := Marshal(
+		encoded, err := marshal(
+			codecs,
 			routingKeyInfo.types[i],
 			values[routingKeyInfo.indexes[i]],
 		)
@@ -3942,6 +4175,13 @@ type ObservedQueryWithAttemptMetrics struct {
 	// attempts. Manual paging starts a separate logical execution for each call
 	// to Iter.
 	AttemptMetrics AttemptMetrics
+	// Table is the table of the query, if known.
+	Table       string
+	Consistency Consistency
+	PageSize    int
+	// BindMarkers are the bind markers of Values when the query was bound
+	// from a PreparedStatement.
+	BindMarkers []ColumnInfo
 }
 
 // QueryObserverWithAttemptMetrics optionally extends QueryObserver. When an
@@ -4005,6 +4245,7 @@ type ObservedBatchWithAttemptMetrics struct {
 	// Attempts are iterated in launch order and can contain gaps when a later
 	// speculative attempt completes first.
 	AttemptMetrics AttemptMetrics
+	Consistency    Consistency
 }
 
 // BatchObserverWithAttemptMetrics optionally extends BatchObserver. When an
@@ -1009,6 +1009,7 @@ func TestQueryMetricsObservedSnapshotsRemainImmutableAcrossResets(t *testing.T)
 		assertSingleAttemptMetric(t, retainedAttempts[i], AttemptMetric{
 			Attempt: 0,
 			Host:    expectedHost,
+			Shard:   -1,
 			Latency: int64(i + 1),
 		})
 		for j := i + 1; j < len(retained); j++ {
@@ -1055,6 +1056,7 @@ func TestQueryObserverDeprecatedMetricsIncludeManualHostUpdates(t *testing.T) {
 	assertSingleAttemptMetric(t, observedQuery.AttemptMetrics, AttemptMetric{
 		Attempt: 2,
 		Host:    host,
+		Shard:   -1,
 		Latency: 10,
 	})
 }
@@ -1096,6 +1098,7 @@ func TestBatchObserverDeprecatedMetricsIncludeManualHostUpdates(t *testing.T) {
 	assertSingleAttemptMetric(t, observedBatch.AttemptMetrics, AttemptMetric{
 		Attempt: 2,
 		Host:    host,
+		Shard:   -1,
 		Latency: 10,
 	})
 }
@@ -1281,6 +1284,7 @@ func TestQueryObserverAttemptMetricsAndDeprecatedMetrics(t *testing.T) {
 	assertSingleAttemptMetric(t, first.AttemptMetrics, AttemptMetric{
 		Attempt: 0,
 		Host:    host,
+		Shard:   -1,
 		Latency: 10,
 	})
 
@@ -1292,8 +1296,8 @@ func TestQueryObserverAttemptMetricsAndDeprecatedMetrics(t *testing.T) {
 		t.Fatalf("second observation = %+v, want attempt=1 rows=3 host=%p", second, host)
 	}
 	assertAttemptMetrics(t, second.AttemptMetrics, []AttemptMetric{
-		{Attempt: 0, Host: host, Latency: 10},
-		{Attempt: 1, Host: host, Latency: 20},
+		{Attempt: 0, Host: host, Shard: -1, Latency: 10},
+		{Attempt: 1, Host: host, Shard: -1, Latency: 20},
 	})
 }
 
@@ -1338,6 +1342,7 @@ func TestBatchObserverAttemptMetricsAndDeprecatedMetrics(t *testing.T) {
 	assertSingleAttemptMetric(t, observedBatch.AttemptMetrics, AttemptMetric{
 		Attempt: 0,
 		Host:    host,
+		Shard:   -1,
 		Latency: 12,
 	})
 }
@@ -1378,11 +1383,12 @@ func TestBatchObserverAttemptMetricsAreCumulative(t *testing.T) {
 	assertSingleAttemptMetric(t, observations[0].AttemptMetrics, AttemptMetric{
 		Attempt: 0,
 		Host:    host1,
+		Shard:   -1,
 		Latency: 5,
 	})
 	assertAttemptMetrics(t, observations[1].AttemptMetrics, []AttemptMetric{
-		{Attempt: 0, Host: host1, Latency: 5},
-		{Attempt: 1, Host: host2, Latency: 7},
+		{Attempt: 0, Host: host1, Shard: -1, Latency: 5},
+		{Attempt: 1, Host: host2, Shard: -1, Latency: 7},
 	})
 	if observations[0].Metrics.Attempts != 1 || observations[1].Metrics.Attempts != 1 {
 		t.Fatalf("deprecated per-host attempts = (%d,%d), want (1,1)",
@@ -1513,19 +1519,19 @@ func TestQueryObserverAttemptMetricsOutOfOrderCompletion(t *testing.T) {
 	q.finishAttempt(secondLaunched, "ks", start.Add(20*time.Nanosecond), &Iter{}, host2)
 	firstSnapshot := (<-observed).AttemptMetrics
 	assertAttemptMetrics(t, firstSnapshot, []AttemptMetric{
-		{Attempt: 1, Host: host2, Latency: 20},
+		{Attempt: 1, Host: host2, Shard: -1, Latency: 20},
 	})
 
 	q.finishAttempt(firstLaunched, "ks", start.Add(10*time.Nanosecond), &Iter{}, host1)
 	secondSnapshot := (<-observed).AttemptMetrics
 	assertAttemptMetrics(t, secondSnapshot, []AttemptMetric{
-		{Attempt: 0, Host: host1, Latency: 10},
-		{Attempt: 1, Host: host2, Latency: 20},
+		{Attempt: 0, Host: host1, Shard: -1, Latency: 10},
+		{Attempt: 1, Host: host2, Shard: -1, Latency: 20},
 	})
 
 	// Completing another attempt must not mutate a retained earlier snapshot.
 	assertAttemptMetrics(t, firstSnapshot, []AttemptMetric{
-		{Attempt: 1, Host: host2, Latency: 20},
+		{Attempt: 1, Host: host2, Shard: -1, Latency: 20},
 	})
 }
 
@@ -1571,11 +1577,13 @@ func TestQueryMetricsExecutionGenerationIsolation(t *testing.T) {
 	assertSingleAttemptMetric(t, newObservation.AttemptMetrics, AttemptMetric{
 		Attempt: 0,
 		Host:    host,
+		Shard:   -1,
 		Latency: 7,
 	})
 	assertSingleAttemptMetric(t, oldObservation.AttemptMetrics, AttemptMetric{
 		Attempt: 0,
 		Host:    host,
+		Shard:   -1,
 		Latency: 11,
 	})
 	if refs := oldMetrics.refs.Load(); refs != 0 {
@@ -1820,6 +1828,9 @@ func TestCloneQueryAccountsForEveryField(t *testing.T) {
 		"getKeyspace":                {},
 		"routingInfo":                {},
 		"binding":                    {},
+		"preparedRouting":            {},
+		"bindMarkers":                {},
+		"routingToken":               {},
 		"keyspace":                   {},
 		"nowInSecondsValue":          {},
 		"hostID":                     {},
@@ -3051,6 +3062,123 @@ func TestQueryExecutorPropagatesWinningRetryConsistency(t *testing.T) {
 	})
 }
 
+func TestQueryExecutorNoRemoteDCsAfterRetryConsistencyRewrite(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name      string
+		rt        RetryPolicy
+		localDown bool
+	}{
+		// The retry policy downgrades to LOCAL_ONE and moves to the next
+		// host, which is remote.
+		{"RetryNextHost", &DowngradingConsistencyRetryPolicy{ConsistencyLevelsToTry: []Consistency{LocalOne}}, false},
+		// The local host is down, so the first attempt is sent to the remote
+		// host, which the retry on the same host with LOCAL_QUORUM must not
+		// be.
+		{"Retry", &consistencyRetryPolicy{consistency: LocalQuorum}, true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			local := (&HostInfo{hostId: UUID{1}, dataCenter: "local"}).setState(NodeUp)
+			remote := (&HostInfo{hostId: UUID{2}, dataCenter: "remote"}).setState(NodeUp)
+			if test.localDown {
+				local.setState(NodeDown)
+			}
+			policy := DCAwareRoundRobinPolicy("local", HostPolicyOptionNoRemoteDCsForLocalConsistency)
+			executor := &queryExecutor{
+				pool:           &policyConnPool{hostConnPools: map[UUID]*hostConnPool{}},
+				policy:         policy,
+				failoverFilter: findHostFailoverFilter(SingleHostReadyPolicy(policy)),
+			}
+			for _, host := range []*HostInfo{local, remote} {
+				policy.AddHost(host)
+				executor.pool.hostConnPools[host.hostUUID()] = &hostConnPool{
+					host:       host,
+					connPicker: staticConnPicker{conn: &Conn{host: host}},
+				}
+			}
+
+			var attempts []*HostInfo
+			qry := &executorTestQuery{
+				rt:          test.rt,
+				consistency: Quorum,
+			}
+			qry.executeFunc = func(_ context.Context, conn *Conn) *Iter {
+				attempts = append(attempts, conn.host)
+				return &Iter{err: errors.New("failed")}
+			}
+
+			iter, err := executor.executeQuery(qry, newQueryMetrics())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if iter.err == nil {
+				t.Fatal("execution succeeded, want the error of the first attempt")
+			}
+			if len(attempts) != 1 {
+				t.Fatalf("attempted %d hosts, want 1: %v", len(attempts), attempts)
+			}
+			want := local
+			if test.localDown {
+				want = remote
+			}
+			if attempts[0] != want {
+				t.Errorf("attempted %v, want %v", attempts[0], want)
+			}
+		})
+	}
+}
+
+func TestQueryExecutorRemoteDCsAfterRetryConsistencyRewrite(t *testing.T) {
+	t.Parallel()
+
+	// The first attempt uses LOCAL_QUORUM, which must stay local, and the
+	// retry policy downgrades it to ONE, which may be sent to the remote
+	// host.
+	local := (&HostInfo{hostId: UUID{1}, dataCenter: "local"}).setState(NodeUp)
+	remote := (&HostInfo{hostId: UUID{2}, dataCenter: "remote"}).setState(NodeUp)
+	policy := DCAwareRoundRobinPolicy("local", HostPolicyOptionNoRemoteDCsForLocalConsistency)
+	executor := &queryExecutor{
+		pool:           &policyConnPool{hostConnPools: map[UUID]*hostConnPool{}},
+		policy:         policy,
+		failoverFilter: findHostFailoverFilter(SingleHostReadyPolicy(policy)),
+	}
+	for _, host := range []*HostInfo{local, remote} {
+		policy.AddHost(host)
+		executor.pool.hostConnPools[host.hostUUID()] = &hostConnPool{
+			host:       host,
+			connPicker: staticConnPicker{conn: &Conn{host: host}},
+		}
+	}
+
+	var attempts []*HostInfo
+	qry := &executorTestQuery{
+		rt:          &DowngradingConsistencyRetryPolicy{ConsistencyLevelsToTry: []Consistency{One}},
+		consistency: LocalQuorum,
+	}
+	qry.executeFunc = func(_ context.Context, conn *Conn) *Iter {
+		attempts = append(attempts, conn.host)
+		if conn.host == local {
+			return &Iter{err: errors.New("failed")}
+		}
+		return &Iter{}
+	}
+
+	iter, err := executor.executeQuery(qry, newQueryMetrics())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if iter.err != nil {
+		t.Fatalf("execution failed: %v", iter.err)
+	}
+	if len(attempts) != 2 || attempts[0] != local || attempts[1] != remote {
+		t.Fatalf("attempted %v, want the local then the remote host", attempts)
+	}
+	if cons := qry.GetConsistency(); cons != One {
+		t.Errorf("consistency = %v, want ONE", cons)
+	}
+}
+
 func TestQueryExecutorSuccessfulAttemptDoesNotWriteConsistency(t *testing.T) {
 	t.Parallel()
 
@@ -3680,8 +3808,8 @@ func TestQueryObserverMetricsContinueAcrossAutomaticPages(t *testing.T) {
 		t.Fatalf("page observations = %+v, want attempts [0 1]", observations)
 	}
 	assertAttemptMetrics(t, observations[1].AttemptMetrics, []AttemptMetric{
-		{Attempt: 0, Host: host, Latency: 1},
-		{Attempt: 1, Host: host, Latency: 2},
+		{Attempt: 0, Host: host, Shard: -1, Latency: 1},
+		{Attempt: 1, Host: host, Shard: -1, Latency: 2},
 	})
 	if attempts, latency := firstPageMetrics.totalsSnapshot(); attempts != 2 || latency != 3 {
 		t.Fatalf("automatic-page totals = (%d,%d), want (2,3)", attempts, latency)
@@ -3766,8 +3894,8 @@ func TestQueryIterManualPagingDefersHiddenEmptyPageWarnings(t *testing.T) {
 		t.Fatalf("page observations = %+v, want attempts [0 1]", observations)
 	}
 	assertAttemptMetrics(t, observations[1].AttemptMetrics, []AttemptMetric{
-		{Attempt: 0, Host: host, Latency: 1},
-		{Attempt: 1, Host: host, Latency: 2},
+		{Attempt: 0, Host: host, Shard: -1, Latency: 1},
+		{Attempt: 1, Host: host, Shard: -1, Latency: 2},
 	})
 	if handler.calls != 0 {
 		t.Fatalf("handler call count before Close = %d, want 0", handler.calls)
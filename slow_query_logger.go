@@ -0,0 +1,261 @@
+package gocql
+
+import (
+	"context"
+	"log/slog"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultSlowQueryThreshold is the default SlowQueryLoggerOptions.Threshold.
+const DefaultSlowQueryThreshold = 500 * time.Millisecond
+
+// SlowQueryLoggerOptions configures a SlowQueryLogger.
+type SlowQueryLoggerOptions struct {
+	// Logger receives the slow queries, with the warn level. Defaults to
+	// slog.Default().
+	Logger *slog.Logger
+	// Threshold is the latency above which attempts are logged. Defaults to
+	// DefaultSlowQueryThreshold.
+	Threshold time.Duration
+	// Thresholds overrides Threshold for the keyspaces and tables of its
+	// keys, formatted as "keyspace" or "keyspace.table".
+	Thresholds map[string]time.Duration
+	// LogValues logs the statements with their bound values inlined, as
+	// Query.DebugString does, instead of with bind markers.
+	LogValues bool
+	// RedactColumns are the names of the bind markers whose values are
+	// redacted when LogValues is set. Bind markers are known for queries
+	// bound from a PreparedStatement and for named values: if RedactColumns
+	// is not empty, the values whose bind marker is not known, such as the
+	// values of batches and the positional values of Session.Query, are all
+	// redacted.
+	RedactColumns []string
+	// Redact, if not nil, is called when LogValues is set with the index and
+	// bind marker of each value of a statement, and redacts the value if it
+	// returns true.
+	Redact func(i int, marker ColumnInfo) bool
+	// SampleRate is the fraction of slow attempts that are logged, in
+	// (0, 1]. Defaults to 1.
+	SampleRate float64
+	// MaxPerSecond, if positive, is the maximum rate of logged attempts,
+	// with bursts of up to MaxPerSecond attempts, or one if it is lower. The
+	// number of attempts dropped by the limit is logged with the next logged
+	// attempt.
+	MaxPerSecond float64
+}
+
+// SlowQueryLogger is a QueryObserver and BatchObserver logging the attempts
+// of queries and batches slower than a threshold, with the host and shard
+// they were executed on and the attempts of their execution that completed
+// before them. It is safe to use in production: statements are logged
+// without their values unless LogValues is set, and sampling and rate
+// limiting bound the volume of logs.
+//
+//	slow := gocql.NewSlowQueryLogger(gocql.SlowQueryLoggerOptions{
+//		Threshold:    time.Second,
+//		MaxPerSecond: 10,
+//	})
+//	cluster.QueryObserver = slow
+//	cluster.BatchObserver = slow
+type SlowQueryLogger struct {
+	opts   SlowQueryLoggerOptions
+	redact map[string]struct{}
+
+	mu         sync.Mutex
+	tokens     float64
+	lastRefill time.Time
+	suppressed atomic.Int64
+}
+
+var (
+	_ QueryObserverWithAttemptMetrics = (*SlowQueryLogger)(nil)
+	_ BatchObserverWithAttemptMetrics = (*SlowQueryLogger)(nil)
+)
+
+// NewSlowQueryLogger returns a SlowQueryLogger configured by opts.
+func NewSlowQueryLogger(opts SlowQueryLoggerOptions) *SlowQueryLogger {
+	if opts.Logger == nil {
+		opts.Logger = slog.Default()
+	}
+	if opts.Threshold <= 0 {
+		opts.Threshold = DefaultSlowQueryThreshold
+	}
+	if opts.SampleRate <= 0 || opts.SampleRate > 1 {
+		opts.SampleRate = 1
+	}
+	l := &SlowQueryLogger{
+		opts:   opts,
+		redact: make(map[string]struct{}, len(opts.RedactColumns)),
+	}
+	l.tokens = l.burst()
+	for _, column := range opts.RedactColumns {
+		l.redact[column] = struct{}{}
+	}
+	return l
+}
+
+func (l *SlowQueryLogger) threshold(keyspace, table string) time.Duration {
+	if table != "" {
+		if threshold, ok := l.opts.Thresholds[keyspace+"."+table]; ok {
+			return threshold
+		}
+	}
+	if threshold, ok := l.opts.Thresholds[keyspace]; ok {
+		return threshold
+	}
+	return l.opts.Threshold
+}
+
+func (l *SlowQueryLogger) burst() float64 {
+	return max(l.opts.MaxPerSecond, 1)
+}
+
+// allow reports whether a slow attempt is logged, according to the sample
+// rate and the rate limit.
+func (l *SlowQueryLogger) allow(now time.Time) bool {
+	if l.opts.SampleRate < 1 && rand.Float64() >= l.opts.SampleRate {
+		return false
+	}
+	if l.opts.MaxPerSecond <= 0 {
+		return true
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.lastRefill.IsZero() {
+		l.tokens += now.Sub(l.lastRefill).Seconds() * l.opts.MaxPerSecond
+		l.tokens = min(l.tokens, l.burst())
+	}
+	l.lastRefill = now
+	if l.tokens < 1 {
+		l.suppressed.Add(1)
+		return false
+	}
+	l.tokens--
+	return true
+}
+
+func (l *SlowQueryLogger) redactValue(i int, marker ColumnInfo) bool {
+	if _, ok := l.redact[marker.Name]; ok || (marker.Name == "" && len(l.redact) > 0) {
+		return true
+	}
+	return l.opts.Redact != nil && l.opts.Redact(i, marker)
+}
+
+func (l *SlowQueryLogger) statement(stmt string, values []any, markers []ColumnInfo) string {
+	if !l.opts.LogValues {
+		return stmt
+	}
+	return debugStatement(protoVersion4, stmt, values, markers, l.redactValue)
+}
+
+// slowQueryAttempt is an attempt of the execution of a slow query.
+type slowQueryAttempt struct {
+	Attempt int           `json:"attempt"`
+	HostID  string        `json:"host_id"`
+	Address string        `json:"address"`
+	Shard   int           `json:"shard"`
+	Latency time.Duration `json:"latency"`
+}
+
+// attemptAttrs returns the attributes of the attempt and of the attempts of
+// its execution.
+func (l *SlowQueryLogger) attemptAttrs(attrs []slog.Attr, host *HostInfo, attempt int, metrics AttemptMetrics) []slog.Attr {
+	shard := -1
+	var attempts []slowQueryAttempt
+	metrics.ForEachAttempt(func(m AttemptMetric) bool {
+		a := slowQueryAttempt{Attempt: m.Attempt, Shard: m.Shard, Latency: time.Duration(m.Latency)}
+		if m.Host != nil {
+			a.HostID = m.Host.HostID()
+			a.Address = m.Host.ConnectAddressAndPort()
+		}
+		if m.Attempt == attempt {
+			shard = m.Shard
+		}
+		attempts = append(attempts, a)
+		return true
+	})
+	if host != nil {
+		attrs = append(attrs, logHostID(host), logAddress(host.ConnectAddressAndPort()))
+	}
+	if shard >= 0 {
+		attrs = append(attrs, logShard(shard))
+	}
+	attrs = append(attrs, slog.Int("attempt", attempt))
+	if len(attempts) > 0 {
+		attrs = append(attrs, slog.Any("attempts", attempts))
+	}
+	if suppressed := l.suppressed.Swap(0); suppressed > 0 {
+		attrs = append(attrs, slog.Int64("suppressed", suppressed))
+	}
+	return attrs
+}
+
+// ObserveQuery logs o if it is slow, without the attempts of its execution.
+func (l *SlowQueryLogger) ObserveQuery(ctx context.Context, o ObservedQuery) {
+	l.ObserveQueryWithAttemptMetrics(ctx, ObservedQueryWithAttemptMetrics{ObservedQuery: o})
+}
+
+// ObserveQueryWithAttemptMetrics logs o if it is slow.
+func (l *SlowQueryLogger) ObserveQueryWithAttemptMetrics(ctx context.Context, o ObservedQueryWithAttemptMetrics) {
+	latency := o.End.Sub(o.Start)
+	if latency <= l.threshold(o.Keyspace, o.Table) || !l.allow(o.End) {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("statement", l.statement(o.Statement, o.Values, o.BindMarkers)),
+		logKeyspace(o.Keyspace),
+	}
+	if o.Table != "" {
+		attrs = append(attrs, slog.String("table", o.Table))
+	}
+	attrs = append(attrs,
+		slog.Duration("latency", latency),
+		slog.String("consistency", o.Consistency.String()),
+		slog.Int("page_size", o.PageSize),
+		slog.Int("rows", o.Rows),
+	)
+	attrs = l.attemptAttrs(attrs, o.Host, o.Attempt, o.AttemptMetrics)
+	if o.Err != nil {
+		attrs = append(attrs, logError(o.Err))
+	}
+	l.opts.Logger.LogAttrs(ctx, slog.LevelWarn, "slow query", attrs...)
+}
+
+// ObserveBatch logs o if it is slow, without the attempts of its execution.
+func (l *SlowQueryLogger) ObserveBatch(ctx context.Context, o ObservedBatch) {
+	l.ObserveBatchWithAttemptMetrics(ctx, ObservedBatchWithAttemptMetrics{ObservedBatch: o})
+}
+
+// ObserveBatchWithAttemptMetrics logs o if it is slow.
+func (l *SlowQueryLogger) ObserveBatchWithAttemptMetrics(ctx context.Context, o ObservedBatchWithAttemptMetrics) {
+	latency := o.End.Sub(o.Start)
+	if latency <= l.threshold(o.Keyspace, "") || !l.allow(o.End) {
+		return
+	}
+
+	statements := make([]string, len(o.Statements))
+	for i, stmt := range o.Statements {
+		var values []any
+		if i < len(o.Values) {
+			values = o.Values[i]
+		}
+		statements[i] = l.statement(stmt, values, nil)
+	}
+	attrs := []slog.Attr{
+		slog.Any("statements", statements),
+		logKeyspace(o.Keyspace),
+		slog.Int("batch_size", len(statements)),
+		slog.Duration("latency", latency),
+		slog.String("consistency", o.Consistency.String()),
+	}
+	attrs = l.attemptAttrs(attrs, o.Host, o.Attempt, o.AttemptMetrics)
+	if o.Err != nil {
+		attrs = append(attrs, logError(o.Err))
+	}
+	l.opts.Logger.LogAttrs(ctx, slog.LevelWarn, "slow batch", attrs...)
+}
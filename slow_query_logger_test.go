@@ -0,0 +1,217 @@
+//go:build unit
+// +build unit
+
+package gocql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func slowQueryRecords(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var record map[string]any
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatal(err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestSlowQueryLoggerQuery(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	l := NewSlowQueryLogger(SlowQueryLoggerOptions{
+		Logger:        slog.New(slog.NewJSONHandler(&buf, nil)),
+		Threshold:     10 * time.Millisecond,
+		Thresholds:    map[string]time.Duration{"ks": time.Second, "ks.hot": time.Millisecond},
+		LogValues:     true,
+		RedactColumns: []string{"password"},
+	})
+
+	host := &HostInfo{hostId: UUID{1}, connectAddress: []byte{127, 0, 0, 1}, port: 9042}
+	start := time.Unix(0, 0)
+	metrics := newAttemptMetrics(AttemptMetric{Host: host, Shard: -1, Attempt: 0, Latency: int64(time.Millisecond)}).
+		withAttempt(AttemptMetric{Host: host, Shard: 3, Attempt: 1, Latency: int64(5 * time.Millisecond)})
+	observed := ObservedQueryWithAttemptMetrics{
+		ObservedQuery: ObservedQuery{
+			Start:     start,
+			End:       start.Add(5 * time.Millisecond),
+			Host:      host,
+			Keyspace:  "ks",
+			Statement: "UPDATE users SET password = ? WHERE id = ?",
+			Values:    []any{"secret", 42},
+			Rows:      0,
+			Attempt:   1,
+		},
+		AttemptMetrics: metrics,
+		Table:          "hot",
+		Consistency:    LocalQuorum,
+		PageSize:       100,
+		BindMarkers: []ColumnInfo{
+			{Name: "password", TypeInfo: NewNativeType(protoVersion4, TypeText)},
+			{Name: "id", TypeInfo: NewNativeType(protoVersion4, TypeInt)},
+		},
+	}
+	l.ObserveQueryWithAttemptMetrics(context.Background(), observed)
+
+	// Below the threshold of the keyspace.
+	observed.Table = "users"
+	l.ObserveQueryWithAttemptMetrics(context.Background(), observed)
+
+	records := slowQueryRecords(t, &buf)
+	if len(records) != 1 {
+		t.Fatalf("logged %d records, want 1:\n%s", len(records), buf.String())
+	}
+	record := records[0]
+	for key, want := range map[string]any{
+		"level":       "WARN",
+		"msg":         "slow query",
+		"statement":   "UPDATE users SET password = <redacted> WHERE id = 42",
+		"keyspace":    "ks",
+		"table":       "hot",
+		"host_id":     host.HostID(),
+		"address":     "127.0.0.1:9042",
+		"shard":       float64(3),
+		"consistency": "LOCAL_QUORUM",
+		"page_size":   float64(100),
+		"attempt":     float64(1),
+	} {
+		if got := record[key]; got != want {
+			t.Errorf("%s = %v, want %v", key, got, want)
+		}
+	}
+	attempts, _ := record["attempts"].([]any)
+	if len(attempts) != 2 {
+		t.Fatalf("attempts = %v, want 2 attempts", record["attempts"])
+	}
+	if first := attempts[0].(map[string]any); first["shard"] != float64(-1) || first["latency"] != float64(time.Millisecond) {
+		t.Errorf("first attempt = %v", first)
+	}
+}
+
+func TestSlowQueryLoggerBatch(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	l := NewSlowQueryLogger(SlowQueryLoggerOptions{
+		Logger:    slog.New(slog.NewJSONHandler(&buf, nil)),
+		Threshold: time.Millisecond,
+		LogValues: true,
+		Redact:    func(i int, _ ColumnInfo) bool { return i == 0 },
+	})
+
+	start := time.Unix(0, 0)
+	l.ObserveBatch(context.Background(), ObservedBatch{
+		Start:      start,
+		End:        start.Add(time.Second),
+		Keyspace:   "ks",
+		Statements: []string{"INSERT INTO t (a, b) VALUES (?, ?)"},
+		Values:     [][]any{{"a", 1}},
+	})
+
+	records := slowQueryRecords(t, &buf)
+	if len(records) != 1 {
+		t.Fatalf("logged %d records, want 1:\n%s", len(records), buf.String())
+	}
+	statements, _ := records[0]["statements"].([]any)
+	if records[0]["msg"] != "slow batch" || len(statements) != 1 || statements[0] != "INSERT INTO t (a, b) VALUES (<redacted>, 1)" {
+		t.Fatalf("record = %v", records[0])
+	}
+}
+
+func TestSlowQueryLoggerRedactUnknownMarkers(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	l := NewSlowQueryLogger(SlowQueryLoggerOptions{
+		Logger:        slog.New(slog.NewJSONHandler(&buf, nil)),
+		Threshold:     time.Millisecond,
+		LogValues:     true,
+		RedactColumns: []string{"password"},
+	})
+
+	start := time.Unix(0, 0)
+	l.ObserveBatch(context.Background(), ObservedBatch{
+		Start:    start,
+		End:      start.Add(time.Second),
+		Keyspace: "ks",
+		Statements: []string{
+			"INSERT INTO users (id, password) VALUES (?, ?)",
+			"UPDATE users SET password = :password WHERE id = :id",
+		},
+		Values: [][]any{
+			{1, "secret"},
+			{NamedValue("password", "secret"), NamedValue("id", 2)},
+		},
+	})
+	l.ObserveQuery(context.Background(), ObservedQuery{
+		Start:     start,
+		End:       start.Add(time.Second),
+		Keyspace:  "ks",
+		Statement: "UPDATE users SET password = ? WHERE id = ?",
+		Values:    []any{"secret", 3},
+	})
+
+	records := slowQueryRecords(t, &buf)
+	if len(records) != 2 {
+		t.Fatalf("logged %d records, want 2:\n%s", len(records), buf.String())
+	}
+	statements, _ := records[0]["statements"].([]any)
+	want := []any{
+		"INSERT INTO users (id, password) VALUES (<redacted>, <redacted>)",
+		"UPDATE users SET password = <redacted> WHERE id = 2",
+	}
+	if len(statements) != len(want) || statements[0] != want[0] || statements[1] != want[1] {
+		t.Errorf("batch statements = %v, want %v", statements, want)
+	}
+	if got, want := records[1]["statement"], "UPDATE users SET password = <redacted> WHERE id = <redacted>"; got != want {
+		t.Errorf("statement = %v, want %v", got, want)
+	}
+}
+
+func TestSlowQueryLoggerRateLimit(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	l := NewSlowQueryLogger(SlowQueryLoggerOptions{
+		Logger:       slog.New(slog.NewJSONHandler(&buf, nil)),
+		Threshold:    time.Millisecond,
+		MaxPerSecond: 1,
+	})
+
+	start := time.Unix(0, 0)
+	observe := func(end time.Time) {
+		l.ObserveQuery(context.Background(), ObservedQuery{Start: end.Add(-time.Second), End: end, Statement: "SELECT 1"})
+	}
+	observe(start)
+	observe(start.Add(100 * time.Millisecond))
+	observe(start.Add(200 * time.Millisecond))
+	observe(start.Add(1200 * time.Millisecond))
+
+	records := slowQueryRecords(t, &buf)
+	if len(records) != 2 {
+		t.Fatalf("logged %d records, want 2:\n%s", len(records), buf.String())
+	}
+	if _, ok := records[0]["suppressed"]; ok {
+		t.Errorf("first record = %v, want no suppressed attempts", records[0])
+	}
+	if records[1]["suppressed"] != float64(2) {
+		t.Errorf("second record suppressed = %v, want 2", records[1]["suppressed"])
+	}
+	if records[0]["statement"] != "SELECT 1" {
+		t.Errorf("statement = %v, want it without values", records[0]["statement"])
+	}
+}
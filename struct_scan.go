@@ -0,0 +1,311 @@
+package gocql
+
+import (
+	"fmt"
+	"iter"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// structFieldsCache maps a struct reflect.Type to its structFields.
+var structFieldsCache sync.Map
+
+// structFields describes how the columns of a result row or the bind markers
+// of a statement map onto the fields of a struct type.
+type structFields struct {
+	byName map[string]structField
+}
+
+// structField is a struct field mapped to a column or bind marker.
+type structField struct {
+	// index is the index sequence of the field, suitable for
+	// reflect.Value.FieldByIndex.
+	index []int
+	// omitEmpty is set by the omitempty tag option: a zero value is bound as
+	// UnsetValue rather than null.
+	omitEmpty bool
+}
+
+// structScanPlan maps every column of a result metadata onto a struct field.
+type structScanPlan struct {
+	typ reflect.Type
+	// columns is the metadata the plan was built for; plans are reused for as
+	// long as the iterator keeps returning the very same column slice.
+	columns []ColumnInfo
+	// fields holds, for each column, the index sequence of the field it is
+	// scanned into, or nil if the column has no corresponding field.
+	fields [][]int
+	strict bool
+}
+
+// parseCQLTag splits a cql struct tag into the column name and whether the
+// omitempty option is set.
+func parseCQLTag(tag string) (name string, omitEmpty bool) {
+	name, opts, _ := strings.Cut(tag, ",")
+	for opts != "" {
+		var opt string
+		opt, opts, _ = strings.Cut(opts, ",")
+		if opt == "omitempty" {
+			omitEmpty = true
+		}
+	}
+	return name, omitEmpty
+}
+
+// cachedStructFields returns the structFields of t, which must be a struct type.
+//
+// Fields are named after their cql tag, or after the lower-cased field name
+// when untagged, which matches how Cassandra folds unquoted identifiers.
+// Fields tagged `cql:"-"` and unexported fields are ignored, and the tag may
+// carry options after the name, as in `cql:"name,omitempty"`. The fields of
+// untagged embedded structs are promoted, with shallower fields taking
+// precedence over deeper ones, as with Go field selectors.
+func cachedStructFields(t reflect.Type) *structFields {
+	if cached, ok := structFieldsCache.Load(t); ok {
+		return cached.(*structFields)
+	}
+
+	type level struct {
+		typ   reflect.Type
+		index []int
+	}
+
+	fields := &structFields{byName: make(map[string]structField)}
+	visited := map[reflect.Type]bool{t: true}
+	current := []level{{typ: t}}
+	for len(current) > 0 {
+		var next []level
+		// Names found at this depth; only used to keep the first of several
+		// candidates at the same depth.
+		seen := make(map[string]bool)
+		for _, l := range current {
+			for i := 0; i < l.typ.NumField(); i++ {
+				sf := l.typ.Field(i)
+				tag := sf.Tag.Get("cql")
+				if tag == "-" {
+					continue
+				}
+				name, omitEmpty := parseCQLTag(tag)
+
+				index := make([]int, len(l.index)+1)
+				copy(index, l.index)
+				index[len(l.index)] = i
+
+				if sf.Anonymous && name == "" {
+					ft := sf.Type
+					if ft.Kind() == reflect.Ptr {
+						ft = ft.Elem()
+					}
+					if ft.Kind() == reflect.Struct {
+						// Promoted fields are settable through an unexported
+						// embedded struct, but not through a pointer to one,
+						// which could not be allocated.
+						if !sf.IsExported() && sf.Type.Kind() == reflect.Ptr {
+							continue
+						}
+						if !visited[ft] {
+							visited[ft] = true
+							next = append(next, level{typ: ft, index: index})
+						}
+						continue
+					}
+				}
+				if !sf.IsExported() {
+					continue
+				}
+				if name == "" {
+					name = strings.ToLower(sf.Name)
+				}
+				if _, ok := fields.byName[name]; ok || seen[name] {
+					continue
+				}
+				seen[name] = true
+				fields.byName[name] = structField{index: index, omitEmpty: omitEmpty}
+			}
+		}
+		current = next
+	}
+
+	cached, _ := structFieldsCache.LoadOrStore(t, fields)
+	return cached.(*structFields)
+}
+
+// fieldByIndexAlloc is like reflect.Value.FieldByIndex but allocates nil
+// embedded struct pointers on the way to the field.
+func fieldByIndexAlloc(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+// structScanPlanFor returns the plan for scanning the current result metadata
+// into t, reusing the cached one while the metadata stays the same.
+func (iter *Iter) structScanPlanFor(t reflect.Type) (*structScanPlan, error) {
+	columns := iter.meta.columns
+	if plan := iter.structPlan; plan != nil && plan.typ == t && plan.strict == iter.strictStruct &&
+		len(plan.columns) == len(columns) && (len(columns) == 0 || &plan.columns[0] == &columns[0]) {
+		return plan, nil
+	}
+
+	fields := cachedStructFields(t)
+	plan := &structScanPlan{
+		typ:     t,
+		columns: columns,
+		fields:  make([][]int, len(columns)),
+		strict:  iter.strictStruct,
+	}
+	for i, col := range columns {
+		field, ok := fields.byName[col.Name]
+		if !ok && plan.strict && col.Name != casAppliedColumn {
+			return nil, fmt.Errorf("gocql: StructScan: column %q has no corresponding field in %s", col.Name, t)
+		}
+		plan.fields[i] = field.index
+	}
+	iter.structPlan = plan
+	return plan, nil
+}
+
+// StrictStructScan sets whether StructScan, TypedRows and Collect report an
+// error for result columns that have no corresponding struct field, rather
+// than skipping them. Strict mode catches queries such as SELECT * silently
+// returning columns that the destination struct does not know about. The
+// [applied] column of conditional statements is exempt.
+func (iter *Iter) StrictStructScan(strict bool) *Iter {
+	iter.strictStruct = strict
+	return iter
+}
+
+// StructScan consumes the next row of the iterator and copies its columns into
+// the fields of the struct pointed at by dest.
+//
+// Columns are matched to fields by the `cql:"name"` struct tag, or by the
+// lower-cased field name when a field is untagged. Fields tagged `cql:"-"` are
+// ignored and the fields of untagged embedded structs are promoted. Use pointer
+// fields to tell null columns apart from zero values. Columns without a
+// matching field are skipped, unless StrictStructScan is enabled. The
+// column-to-field mapping is computed once per result metadata.
+//
+// Like Scan, StructScan returns false when the end of the result set was
+// reached or an error occurred. Close should be called afterwards to retrieve
+// any potential errors.
+func (iter *Iter) StructScan(dest any) bool {
+	return iter.structScan(dest, nil)
+}
+
+// structScan implements StructScan, additionally unmarshalling the [applied]
+// column into applied if it is not nil.
+func (iter *Iter) structScan(dest any, applied *bool) bool {
+	rv := reflect.ValueOf(dest)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		iter.err = fmt.Errorf("gocql: StructScan: dest must be a non-nil pointer to a struct, got %T", dest)
+		iter.finalize(true)
+		return false
+	}
+
+	if !iter.nextRow() {
+		return false
+	}
+
+	v := rv.Elem()
+	plan, err := iter.structScanPlanFor(v.Type())
+	if err != nil {
+		iter.err = err
+		iter.finalize(true)
+		return false
+	}
+
+	for j := range iter.meta.columns {
+		colBytes, err := iter.readColumn()
+		if err != nil {
+			iter.err = err
+			iter.finalize(true)
+			return false
+		}
+
+		col := &iter.meta.columns[j]
+		if applied != nil && col.Name == casAppliedColumn {
+			if err := unmarshal(iter.codecs, col.TypeInfo, colBytes, applied); err != nil {
+				iter.err = fmt.Errorf("gocql: StructScan: column %q: %w", col.Name, err)
+				iter.finalize(true)
+				return false
+			}
+			continue
+		}
+		index := plan.fields[j]
+		if index == nil {
+			continue
+		}
+		field := fieldByIndexAlloc(v, index)
+		if err := unmarshal(iter.codecs, col.TypeInfo, colBytes, field.Addr().Interface()); err != nil {
+			iter.err = fmt.Errorf("gocql: StructScan: column %q into field %s: %w", col.Name, v.Type().FieldByIndex(index).Name, err)
+			iter.finalize(true)
+			return false
+		}
+	}
+
+	iter.pos++
+	return true
+}
+
+// TypedRows returns an iterator over the remaining rows of iter, scanning each
+// one into a new T with StructScan. T must be a struct or a pointer to a struct.
+//
+//	for user, err := range gocql.TypedRows[User](session.Query(`SELECT * FROM users`).Iter()) {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(user.Name)
+//	}
+//
+// The iter is closed when the loop finishes or is stopped early; if the query
+// or the iteration failed, the error is yielded once as the last element.
+// The iter should NOT be used again after calling this function.
+func TypedRows[T any](iter *Iter) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		if iter == nil {
+			return
+		}
+		ptrType := reflect.TypeFor[T]().Kind() == reflect.Ptr
+		for {
+			var v T
+			var dest any = &v
+			if ptrType {
+				v = reflect.New(reflect.TypeFor[T]().Elem()).Interface().(T)
+				dest = v
+			}
+			if !iter.StructScan(dest) {
+				break
+			}
+			if !yield(v, nil) {
+				iter.Close()
+				return
+			}
+		}
+		if err := iter.Close(); err != nil {
+			var zero T
+			yield(zero, err)
+		}
+	}
+}
+
+// Collect consumes the remaining rows of iter, closes it and returns the rows
+// scanned into a slice of T using StructScan. T must be a struct or a pointer
+// to a struct.
+func Collect[T any](iter *Iter) ([]T, error) {
+	var rows []T
+	for row, err := range TypedRows[T](iter) {
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
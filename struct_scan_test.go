@@ -0,0 +1,181 @@
+//go:build unit
+// +build unit
+
+package gocql
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/gocql/gocql/internal/tests/mock"
+)
+
+type StructScanAudit struct {
+	CreatedBy string `cql:"created_by"`
+}
+
+type structScanBase struct {
+	*StructScanAudit
+	ID int32
+}
+
+type structScanUser struct {
+	structScanBase
+	Name     string  `cql:"user_name"`
+	Nickname *string `cql:"nickname"`
+	Ignored  string  `cql:"-"`
+	internal string
+}
+
+func newStructScanIter(t *testing.T, rows [][]byte, numRows int) *Iter {
+	t.Helper()
+	return &Iter{
+		meta: resultMetadata{
+			columns: []ColumnInfo{
+				{Name: "id", TypeInfo: NativeType{typ: TypeInt, proto: 4}},
+				{Name: "user_name", TypeInfo: NativeType{typ: TypeVarchar, proto: 4}},
+				{Name: "nickname", TypeInfo: NativeType{typ: TypeVarchar, proto: 4}},
+				{Name: "created_by", TypeInfo: NativeType{typ: TypeVarchar, proto: 4}},
+			},
+			actualColCount: 4,
+		},
+		framer:  &mock.MockFramer{Data: rows},
+		numRows: numRows,
+	}
+}
+
+func TestCachedStructFields(t *testing.T) {
+	fields := cachedStructFields(reflect.TypeFor[structScanUser]())
+
+	want := map[string][]int{
+		"id":         {0, 1},
+		"user_name":  {1},
+		"nickname":   {2},
+		"created_by": {0, 0, 0},
+	}
+	if len(fields.byName) != len(want) {
+		t.Fatalf("got fields %v, want %v", fields.byName, want)
+	}
+	for name, index := range want {
+		if got := fields.byName[name].index; !slices.Equal(got, index) {
+			t.Errorf("field %q: got index %v, want %v", name, got, index)
+		}
+	}
+
+	if cachedStructFields(reflect.TypeFor[structScanUser]()) != fields {
+		t.Error("expected the struct fields to be cached per type")
+	}
+}
+
+func TestIterStructScan(t *testing.T) {
+	t.Run("MapsColumnsToFields", func(t *testing.T) {
+		iter := newStructScanIter(t, [][]byte{
+			marshalTestInt(t, 1), []byte("alice"), []byte("al"), []byte("admin"),
+			marshalTestInt(t, 2), []byte("bob"), nil, []byte("root"),
+		}, 2)
+
+		var first, second structScanUser
+		if !iter.StructScan(&first) {
+			t.Fatalf("expected the first row, err: %v", iter.Close())
+		}
+		plan := iter.structPlan
+		if !iter.StructScan(&second) {
+			t.Fatalf("expected the second row, err: %v", iter.Close())
+		}
+		if err := iter.Close(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if first.ID != 1 || first.Name != "alice" || first.Nickname == nil || *first.Nickname != "al" ||
+			first.StructScanAudit == nil || first.CreatedBy != "admin" {
+			t.Errorf("unexpected first row: %+v", first)
+		}
+		if second.ID != 2 || second.Name != "bob" || second.Nickname != nil || second.CreatedBy != "root" {
+			t.Errorf("unexpected second row: %+v", second)
+		}
+		if iter.structPlan != plan {
+			t.Error("expected the scan plan to be reused for the same metadata")
+		}
+	})
+
+	t.Run("StrictReportsUnmappedColumns", func(t *testing.T) {
+		type partial struct {
+			ID int32 `cql:"id"`
+		}
+		iter := newStructScanIter(t, [][]byte{
+			marshalTestInt(t, 1), []byte("alice"), nil, nil,
+		}, 1).StrictStructScan(true)
+
+		var row partial
+		if iter.StructScan(&row) {
+			t.Fatal("expected StructScan to fail in strict mode")
+		}
+		err := iter.Close()
+		if err == nil || !strings.Contains(err.Error(), `"user_name"`) {
+			t.Fatalf("expected an error naming the unmapped column, got %v", err)
+		}
+	})
+
+	t.Run("LenientSkipsUnmappedColumns", func(t *testing.T) {
+		type partial struct {
+			ID int32 `cql:"id"`
+		}
+		iter := newStructScanIter(t, [][]byte{
+			marshalTestInt(t, 7), []byte("alice"), nil, nil,
+		}, 1)
+
+		var row partial
+		if !iter.StructScan(&row) {
+			t.Fatalf("expected a row, err: %v", iter.Close())
+		}
+		if row.ID != 7 {
+			t.Fatalf("got id %d, want 7", row.ID)
+		}
+	})
+
+	t.Run("RejectsNonStructDest", func(t *testing.T) {
+		iter := newStructScanIter(t, nil, 0)
+		var n int
+		if iter.StructScan(&n) {
+			t.Fatal("expected StructScan to reject a non-struct destination")
+		}
+		if err := iter.Close(); err == nil {
+			t.Fatal("expected an error")
+		}
+	})
+}
+
+func TestCollect(t *testing.T) {
+	iter := newStructScanIter(t, [][]byte{
+		marshalTestInt(t, 1), []byte("alice"), nil, nil,
+		marshalTestInt(t, 2), []byte("bob"), nil, nil,
+	}, 2)
+
+	users, err := Collect[*structScanUser](iter)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(users) != 2 || users[0].Name != "alice" || users[1].Name != "bob" {
+		t.Fatalf("unexpected rows: %+v", users)
+	}
+	if users[0] == users[1] {
+		t.Fatal("expected every row to be scanned into a new value")
+	}
+
+	var n int
+	for _, err := range TypedRows[structScanUser](newStructScanIter(t, [][]byte{
+		marshalTestInt(t, 1), []byte("alice"), nil, nil,
+		marshalTestInt(t, 2), []byte("bob"), nil, nil,
+	}, 2)) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		n++
+		break
+	}
+	if n != 1 {
+		t.Fatalf("expected the loop to stop after the first row, got %d rows", n)
+	}
+}
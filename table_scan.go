@@ -0,0 +1,399 @@
+package gocql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/gocql/gocql/internal/cqlident"
+	"github.com/gocql/gocql/tablets"
+)
+
+const (
+	defaultTableScanParallelism = 8
+	defaultTableScanRetries     = 3
+)
+
+// TokenRange is a range of Murmur3 partitioner tokens, exclusive of Start and
+// inclusive of End, as selected by `token(pk) > Start AND token(pk) <= End`.
+type TokenRange struct {
+	Start int64
+	End   int64
+}
+
+func (r TokenRange) String() string {
+	return fmt.Sprintf("(%d, %d]", r.Start, r.End)
+}
+
+// split splits r into at most n contiguous ranges of roughly equal width.
+func (r TokenRange) split(n int) []TokenRange {
+	if n <= 1 || r.End <= r.Start {
+		return []TokenRange{r}
+	}
+	// The width of the full ring overflows int64, but not uint64.
+	width := uint64(r.End) - uint64(r.Start)
+	if uint64(n) > width {
+		n = int(width)
+	}
+	step := width / uint64(n)
+
+	ranges := make([]TokenRange, 0, n)
+	start := r.Start
+	for i := 1; i < n; i++ {
+		end := int64(uint64(r.Start) + uint64(i)*step)
+		ranges = append(ranges, TokenRange{Start: start, End: end})
+		start = end
+	}
+	return append(ranges, TokenRange{Start: start, End: r.End})
+}
+
+// ringTokenRanges returns the ranges between consecutive boundaries, covering
+// the whole ring. boundaries is sorted in place.
+func ringTokenRanges(boundaries []int64) []TokenRange {
+	slices.Sort(boundaries)
+
+	var ranges []TokenRange
+	cur := int64(math.MinInt64)
+	for _, b := range boundaries {
+		if b > cur {
+			ranges = append(ranges, TokenRange{Start: cur, End: b})
+			cur = b
+		}
+	}
+	if cur < math.MaxInt64 {
+		ranges = append(ranges, TokenRange{Start: cur, End: math.MaxInt64})
+	}
+	return ranges
+}
+
+// tabletTokenRanges returns one range per tablet, plus ranges covering the
+// parts of the ring that no known tablet covers, since the driver only learns
+// about tablets as queries get routed to them.
+func tabletTokenRanges(entries tablets.TabletEntryList) []TokenRange {
+	var ranges []TokenRange
+	cur := int64(math.MinInt64)
+	for _, e := range entries {
+		start := max(e.FirstToken(), cur)
+		if e.FirstToken() > cur {
+			ranges = append(ranges, TokenRange{Start: cur, End: e.FirstToken()})
+		}
+		if e.LastToken() > start {
+			ranges = append(ranges, TokenRange{Start: start, End: e.LastToken()})
+		}
+		cur = max(cur, e.LastToken())
+	}
+	if cur < math.MaxInt64 {
+		ranges = append(ranges, TokenRange{Start: cur, End: math.MaxInt64})
+	}
+	return ranges
+}
+
+// TableScanOptions configures Session.ScanTable.
+type TableScanOptions struct {
+	// OnRangeDone, if set, is called once a range has been fully scanned.
+	// Calls are serialized. Recording the completed ranges allows resuming
+	// an interrupted scan, see TableScanError.
+	OnRangeDone func(TokenRange)
+
+	// Columns lists the columns to select. All columns are selected if empty.
+	Columns []string
+
+	// Ranges restricts the scan to the given token ranges, for instance the
+	// ranges left over by an interrupted scan. If empty, the whole ring is
+	// scanned, split by tablet for tablet keyspaces and by vnode otherwise.
+	Ranges []TokenRange
+
+	// Parallelism is the number of ranges scanned concurrently.
+	// Defaults to 8.
+	Parallelism int
+
+	// Splits further splits every range into this many subranges, which
+	// evens out the work when ranges are few or unbalanced. Defaults to 1.
+	Splits int
+
+	// PageSize is the page size of the range queries. Defaults to the
+	// session page size.
+	PageSize int
+
+	// MaxRetries is the number of times a failed page of a range is retried
+	// before the scan fails. Ranges are paged by hand, one query per page, so
+	// a retry requests the failed page again from the paging state of the
+	// previous one and no row is read twice. Defaults to 3, negative disables
+	// retries.
+	MaxRetries int
+
+	// Consistency is the consistency of the range queries. Defaults to the
+	// session consistency when zero.
+	Consistency Consistency
+}
+
+// TableScanError is returned by Session.ScanTable when the scan did not
+// complete. Remaining holds the ranges that were not fully scanned and can be
+// passed as TableScanOptions.Ranges to resume the scan.
+type TableScanError struct {
+	Err       error
+	Remaining []TokenRange
+}
+
+func (e *TableScanError) Error() string {
+	return fmt.Sprintf("gocql: table scan failed with %d ranges remaining: %v", len(e.Remaining), e.Err)
+}
+
+func (e *TableScanError) Unwrap() error {
+	return e.Err
+}
+
+// tableScanStatement returns the statement selecting columns of a token range.
+func tableScanStatement(keyspace, table string, partitionKey []*ColumnMetadata, columns []string) string {
+	pk := make([]string, len(partitionKey))
+	for i, col := range partitionKey {
+		pk[i] = cqlident.Quote(col.Name)
+	}
+	selected := "*"
+	if len(columns) > 0 {
+		quoted := make([]string, len(columns))
+		for i, col := range columns {
+			quoted[i] = cqlident.Quote(col)
+		}
+		selected = strings.Join(quoted, ", ")
+	}
+	token := "token(" + strings.Join(pk, ", ") + ")"
+	return fmt.Sprintf("SELECT %s FROM %s.%s WHERE %s > ? AND %s <= ?",
+		selected, cqlident.Quote(keyspace), cqlident.Quote(table), token, token)
+}
+
+// tableScanRanges splits the ring of the given table along its tablets or,
+// failing that, the token ring known to the token-aware host policy.
+func (s *Session) tableScanRanges(keyspace, table string) ([]TokenRange, error) {
+	if s.tabletsRoutingV1 && s.metadataDescriber != nil {
+		if entries := s.metadataDescriber.getTableTablets(keyspace, table); len(entries) > 0 {
+			return tabletTokenRanges(entries), nil
+		}
+	}
+
+	if tap, ok := s.policy.(*tokenAwareHostPolicy); ok {
+		if meta := tap.getMetadataReadOnly(); meta != nil && meta.tokenRing != nil {
+			if _, ok := meta.tokenRing.partitioner.(murmur3Partitioner); !ok {
+				return nil, fmt.Errorf("gocql: table scans require Murmur3Partitioner, cluster uses %s", meta.tokenRing.partitioner.Name())
+			}
+			boundaries := make([]int64, len(meta.tokenRing.tokens))
+			for i, ht := range meta.tokenRing.tokens {
+				boundaries[i] = int64(ht.token.(int64Token))
+			}
+			return ringTokenRanges(boundaries), nil
+		}
+	}
+
+	// Without a token ring, split the whole ring evenly.
+	return TokenRange{Start: math.MinInt64, End: math.MaxInt64}.split(defaultTableScanParallelism * 4), nil
+}
+
+// ScanTable reads every row of keyspace.table by splitting the token ring
+// into ranges and scanning them in parallel, calling fn for every row.
+//
+// The ring is split along the table's tablets for tablet keyspaces and along
+// the vnodes of the token ring otherwise. Each range query is routed to a
+// replica of the range, and to the shard owning it, and a failed range is
+// retried on its own as configured by opts.MaxRetries.
+//
+// fn is called concurrently from multiple goroutines and the Row it is given
+// is only valid for the duration of the call. Since only pages that were
+// fully read are passed to fn, retries do not pass a row to fn twice. If fn
+// returns an error the scan stops and returns that error.
+//
+// If the scan does not complete, because fn or a range failed or ctx was
+// cancelled, ScanTable returns a *TableScanError listing the ranges that
+// remain to be scanned.
+func (s *Session) ScanTable(ctx context.Context, keyspace, table string, opts TableScanOptions, fn func(*Row) error) error {
+	tableMeta, err := s.TableMetadata(keyspace, table)
+	if err != nil {
+		return err
+	}
+	stmt := tableScanStatement(keyspace, table, tableMeta.PartitionKey, opts.Columns)
+
+	ranges := opts.Ranges
+	if len(ranges) == 0 {
+		if ranges, err = s.tableScanRanges(keyspace, table); err != nil {
+			return err
+		}
+	}
+	if opts.Splits > 1 {
+		var split []TokenRange
+		for _, r := range ranges {
+			split = append(split, r.split(opts.Splits)...)
+		}
+		ranges = split
+	}
+
+	parallelism := opts.Parallelism
+	if parallelism <= 0 {
+		parallelism = defaultTableScanParallelism
+	}
+	parallelism = min(parallelism, len(ranges))
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		firstErr error
+		done     = make([]bool, len(ranges))
+		next     int
+		wg       sync.WaitGroup
+	)
+	// claim hands out the index of the next range to scan, or -1 once all
+	// ranges were handed out or the scan failed.
+	claim := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		if next >= len(ranges) || firstErr != nil || ctx.Err() != nil {
+			return -1
+		}
+		next++
+		return next - 1
+	}
+
+	for w := 0; w < parallelism; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := claim(); i >= 0; i = claim() {
+				err := s.scanTokenRange(ctx, keyspace, table, stmt, ranges[i], &opts, fn)
+
+				mu.Lock()
+				if err != nil {
+					if firstErr == nil {
+						firstErr = err
+						cancel()
+					}
+				} else {
+					done[i] = true
+					if opts.OnRangeDone != nil {
+						opts.OnRangeDone(ranges[i])
+					}
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	var remaining []TokenRange
+	for i, r := range ranges {
+		if !done[i] {
+			remaining = append(remaining, r)
+		}
+	}
+	if len(remaining) == 0 {
+		return nil
+	}
+	if firstErr == nil {
+		// The workers only stop early on an error or a cancelled context.
+		firstErr = ctx.Err()
+	}
+	return &TableScanError{Err: firstErr, Remaining: remaining}
+}
+
+// errTableScanCallback wraps errors returned by the ScanTable callback, which
+// must not be retried.
+type errTableScanCallback struct {
+	err error
+}
+
+func (e errTableScanCallback) Error() string { return e.err.Error() }
+
+// scanTokenRange scans a single range page by page, retrying a failed page
+// up to opts.MaxRetries times.
+func (s *Session) scanTokenRange(ctx context.Context, keyspace, table, stmt string, r TokenRange,
+	opts *TableScanOptions, fn func(*Row) error) error {
+	maxRetries := opts.MaxRetries
+	if maxRetries == 0 {
+		maxRetries = defaultTableScanRetries
+	}
+	routingToken := int64Token(r.End)
+
+	page := func(pageState []byte) (*Iter, func()) {
+		// Setting the page state, even to nil for the first page, disables
+		// automatic paging: every Iter holds a single page, so that a failed
+		// page is retried on its own.
+		qry := s.Query(stmt, r.Start, r.End).PageState(pageState).Idempotent(true)
+		qry.context = ctx
+		qry.routingToken = &routingToken
+		qry.routingInfo.mu.Lock()
+		qry.routingInfo.keyspace = keyspace
+		qry.routingInfo.table = table
+		qry.routingInfo.mu.Unlock()
+		if opts.PageSize > 0 {
+			qry.PageSize(opts.PageSize)
+		}
+		if opts.Consistency != 0 {
+			qry.Consistency(opts.Consistency)
+		}
+		return qry.Iter(), qry.Release
+	}
+	err := scanPages(ctx, maxRetries, page, fn)
+	var cbErr errTableScanCallback
+	switch {
+	case err == nil:
+		return nil
+	case errors.As(err, &cbErr):
+		return cbErr.err
+	case ctx.Err() != nil:
+		return err
+	default:
+		return fmt.Errorf("gocql: scanning token range %v: %w", r, err)
+	}
+}
+
+// scanPages calls fn for the rows of every page returned by page, starting
+// from the first one, until the last one. page returns a single page, from
+// the given page state, and a function releasing it. A page that fails is
+// requested again from the page state of the last page that succeeded, up to
+// maxRetries times, so that no row is passed to fn twice. Errors of fn are
+// returned as errTableScanCallback.
+func scanPages(ctx context.Context, maxRetries int, page func(pageState []byte) (*Iter, func()),
+	fn func(*Row) error) error {
+	var pageState []byte
+	for retries := 0; ; {
+		iter, release := page(pageState)
+		err := iter.err
+		var nextPageState []byte
+		if err == nil {
+			nextPageState = iter.PageState()
+			for row, iterErr := range iter.Rows() {
+				if iterErr != nil {
+					err = iterErr
+					break
+				}
+				if fnErr := fn(row); fnErr != nil {
+					err = errTableScanCallback{err: fnErr}
+					break
+				}
+			}
+		}
+		iter.Close()
+		release()
+
+		var cbErr errTableScanCallback
+		switch {
+		case err == nil:
+			if len(nextPageState) == 0 {
+				return nil
+			}
+			pageState = nextPageState
+			retries = 0
+		case errors.As(err, &cbErr):
+			return cbErr
+		case ctx.Err() != nil:
+			return ctx.Err()
+		case maxRetries < 0 || retries >= maxRetries:
+			return err
+		default:
+			retries++
+		}
+	}
+}
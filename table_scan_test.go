@@ -0,0 +1,246 @@
+//go:build unit
+// +build unit
+
+package gocql
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net"
+	"slices"
+	"testing"
+
+	"github.com/gocql/gocql/internal/tests/mock"
+	"github.com/gocql/gocql/tablets"
+)
+
+// assertCoversRing checks that ranges are contiguous and cover the whole ring.
+func assertCoversRing(t *testing.T, ranges []TokenRange) {
+	t.Helper()
+	if len(ranges) == 0 {
+		t.Fatal("expected at least one range")
+	}
+	if ranges[0].Start != math.MinInt64 {
+		t.Fatalf("first range %v does not start at the beginning of the ring", ranges[0])
+	}
+	for i := 1; i < len(ranges); i++ {
+		if ranges[i].Start != ranges[i-1].End {
+			t.Fatalf("range %v does not follow %v", ranges[i], ranges[i-1])
+		}
+	}
+	for _, r := range ranges {
+		if r.End <= r.Start {
+			t.Fatalf("range %v is empty", r)
+		}
+	}
+	if last := ranges[len(ranges)-1]; last.End != math.MaxInt64 {
+		t.Fatalf("last range %v does not end at the end of the ring", last)
+	}
+}
+
+func TestTokenRangeSplit(t *testing.T) {
+	full := TokenRange{Start: math.MinInt64, End: math.MaxInt64}
+	ranges := full.split(7)
+	if len(ranges) != 7 {
+		t.Fatalf("got %d ranges, want 7", len(ranges))
+	}
+	assertCoversRing(t, ranges)
+
+	small := TokenRange{Start: 10, End: 12}
+	if got := small.split(5); !slices.Equal(got, []TokenRange{{10, 11}, {11, 12}}) {
+		t.Fatalf("got %v, want the range split into single tokens", got)
+	}
+	if got := small.split(1); !slices.Equal(got, []TokenRange{small}) {
+		t.Fatalf("got %v, want the range unchanged", got)
+	}
+}
+
+func TestRingTokenRanges(t *testing.T) {
+	ranges := ringTokenRanges([]int64{100, -100, 0, 100})
+	want := []TokenRange{
+		{math.MinInt64, -100},
+		{-100, 0},
+		{0, 100},
+		{100, math.MaxInt64},
+	}
+	if !slices.Equal(ranges, want) {
+		t.Fatalf("got %v, want %v", ranges, want)
+	}
+	assertCoversRing(t, ringTokenRanges(nil))
+}
+
+func TestTabletTokenRanges(t *testing.T) {
+	list := tablets.NewCowTabletList()
+	defer list.Close()
+
+	replicas := []tablets.ReplicaInfo{tablets.NewReplicaInfo(tablets.HostUUID{1}, 0)}
+	var infos tablets.TabletInfoList
+	for _, r := range [][2]int64{{-1000, -500}, {-500, 0}, {200, 300}} {
+		info, err := tablets.NewTabletInfo("ks", "tbl", r[0], r[1], replicas)
+		if err != nil {
+			t.Fatal(err)
+		}
+		infos = append(infos, info)
+	}
+	list.BulkAddTablets(infos)
+	list.Flush()
+
+	ranges := tabletTokenRanges(list.GetTableTablets("ks", "tbl"))
+	want := []TokenRange{
+		{math.MinInt64, -1000},
+		{-1000, -500},
+		{-500, 0},
+		{0, 200},
+		{200, 300},
+		{300, math.MaxInt64},
+	}
+	if !slices.Equal(ranges, want) {
+		t.Fatalf("got %v, want %v", ranges, want)
+	}
+}
+
+func TestTableScanRangesFromTokenRing(t *testing.T) {
+	policy := TokenAwareHostPolicy(RoundRobinHostPolicy())
+	policyInternal := policy.(*tokenAwareHostPolicy)
+	policyInternal.getKeyspaceName = func() string { return "" }
+	policyInternal.getKeyspaceMetadata = func(string) (*KeyspaceMetadata, error) {
+		return nil, errors.New("not initialized")
+	}
+	for i, token := range []string{"-100", "0", "100"} {
+		policy.AddHost(&HostInfo{hostId: tUUID(i), connectAddress: net.IPv4(10, 0, 0, byte(i+1)), tokens: []string{token}})
+	}
+	policy.SetPartitioner("Murmur3Partitioner")
+
+	s := &Session{policy: policy}
+	ranges, err := s.tableScanRanges("ks", "tbl")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(ranges) != 4 {
+		t.Fatalf("got ranges %v, want one per vnode plus the wrap-around", ranges)
+	}
+	assertCoversRing(t, ranges)
+
+	s = &Session{policy: RoundRobinHostPolicy()}
+	ranges, err = s.tableScanRanges("ks", "tbl")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertCoversRing(t, ranges)
+}
+
+func TestHostPolicy_TokenAware_RoutingToken(t *testing.T) {
+	const keyspace = "ks"
+	policy := TokenAwareHostPolicy(RoundRobinHostPolicy(), DontShuffleReplicas())
+	policyInternal := policy.(*tokenAwareHostPolicy)
+	policyInternal.getKeyspaceName = func() string { return keyspace }
+	policyInternal.getKeyspaceMetadata = func(string) (*KeyspaceMetadata, error) {
+		return &KeyspaceMetadata{
+			Name:          keyspace,
+			StrategyClass: "SimpleStrategy",
+			StrategyOptions: map[string]any{
+				"class":              "SimpleStrategy",
+				"replication_factor": 1,
+			},
+		}, nil
+	}
+	for i, token := range []string{"-100", "0", "100"} {
+		policy.AddHost(&HostInfo{hostId: tUUID(i), connectAddress: net.IPv4(10, 0, 0, byte(i+1)), tokens: []string{token}, state: NodeUp})
+	}
+	policy.SetPartitioner("Murmur3Partitioner")
+	policy.KeyspaceChanged(KeyspaceUpdateEvent{Keyspace: keyspace})
+
+	query := &Query{routingInfo: &queryRoutingInfo{}}
+	query.getKeyspace = func() string { return keyspace }
+	token := int64Token(50)
+	query.routingToken = &token
+
+	iter := policy.Pick(query)
+	host := iter()
+	if host == nil || host.Info().HostID() != tID(2) {
+		t.Fatalf("expected the owner of the routing token %s, got %v", tID(2), host)
+	}
+	if th, ok := host.(int64TokenSelectedHost); !ok {
+		t.Fatal("expected the selected host to carry the routing token")
+	} else if got, has := th.TokenInt64(); !has || got != token {
+		t.Fatalf("got token %v, want %v", got, token)
+	}
+}
+
+func TestTableScanStatement(t *testing.T) {
+	stmt := tableScanStatement("ks", "My\"Table", []*ColumnMetadata{{Name: "a"}, {Name: "b"}}, []string{"a", "v"})
+	want := `SELECT "a", "v" FROM "ks"."My""Table" WHERE token("a", "b") > ? AND token("a", "b") <= ?`
+	if stmt != want {
+		t.Fatalf("got %s, want %s", stmt, want)
+	}
+
+	err := error(&TableScanError{Err: errTableScanTest, Remaining: []TokenRange{{0, 1}}})
+	if !errors.Is(err, errTableScanTest) {
+		t.Fatal("expected TableScanError to unwrap to the cause")
+	}
+}
+
+var errTableScanTest = errors.New("boom")
+
+func TestScanPagesRetriesFailedPage(t *testing.T) {
+	const pages = 4
+	failed := errors.New("read timeout")
+	var requested [][]byte
+	failures := 0
+	page := func(pageState []byte) (*Iter, func()) {
+		requested = append(requested, pageState)
+		n := 0
+		if len(pageState) > 0 {
+			n = int(pageState[0])
+		}
+		if n == 2 && failures < 2 {
+			failures++
+			return &Iter{err: failed}, func() {}
+		}
+		iter := &Iter{
+			meta: resultMetadata{
+				columns:        []ColumnInfo{{Name: "id", TypeInfo: NativeType{typ: TypeInt, proto: 4}}},
+				actualColCount: 1,
+			},
+			framer:  &mock.MockFramer{Data: [][]byte{marshalTestInt(t, int32(2*n)), marshalTestInt(t, int32(2*n+1))}},
+			numRows: 2,
+		}
+		if n < pages-1 {
+			iter.meta.pagingState = []byte{byte(n + 1)}
+		}
+		return iter, func() {}
+	}
+
+	seen := make(map[int32]int)
+	err := scanPages(context.Background(), 3, page, func(row *Row) error {
+		var id int32
+		if err := row.Scan(&id); err != nil {
+			return err
+		}
+		seen[id]++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(seen) != 2*pages {
+		t.Fatalf("got rows %v, want %d rows", seen, 2*pages)
+	}
+	for id, n := range seen {
+		if n != 1 {
+			t.Errorf("row %d passed to fn %d times, want once", id, n)
+		}
+	}
+	want := [][]byte{nil, {1}, {2}, {2}, {2}, {3}}
+	if !slices.EqualFunc(requested, want, slices.Equal) {
+		t.Errorf("requested page states %v, want %v", requested, want)
+	}
+
+	failures = 0
+	requested = nil
+	err = scanPages(context.Background(), 1, page, func(*Row) error { return nil })
+	if !errors.Is(err, failed) {
+		t.Fatalf("got error %v, want %v after exhausting the retries", err, failed)
+	}
+}
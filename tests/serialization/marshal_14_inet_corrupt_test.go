@@ -5,6 +5,7 @@ package serialization_test
 
 import (
 	"net"
+	"net/netip"
 	"testing"
 
 	"github.com/gocql/gocql"
@@ -92,6 +93,13 @@ func TestMarshalsInetMustFail(t *testing.T) {
 				}.AddVariants(mod.All...),
 			}.Run("corrupt_vals", t, marshal)
 
+			serialization.NegativeMarshalSet{
+				Values: mod.Values{
+					netip.MustParseAddr("fe80::1%eth0"),
+					netip.AddrPortFrom(netip.MustParseAddr("fe80::1%eth0"), 9042),
+				}.AddVariants(mod.Reference),
+			}.Run("zone_vals", t, marshal)
+
 			serialization.NegativeUnmarshalSet{
 				Data: []byte{192, 168, 0, 1, 1},
 				Values: mod.Values{
@@ -99,6 +107,8 @@ func TestMarshalsInetMustFail(t *testing.T) {
 					net.IP{},
 					[]byte{},
 					[4]byte{},
+					netip.Addr{},
+					netip.AddrPort{},
 				}.AddVariants(mod.All...),
 			}.Run("big_dataV4", t, unmarshal)
 
@@ -109,6 +119,8 @@ func TestMarshalsInetMustFail(t *testing.T) {
 					net.IP{},
 					[]byte{},
 					[16]byte{},
+					netip.Addr{},
+					netip.AddrPort{},
 				}.AddVariants(mod.All...),
 			}.Run("big_dataV6", t, unmarshal)
 
@@ -126,6 +138,8 @@ func TestMarshalsInetMustFail(t *testing.T) {
 					net.IP{},
 					[]byte{},
 					[4]byte{},
+					netip.Addr{},
+					netip.AddrPort{},
 				}.AddVariants(mod.All...),
 			}.Run("small_dataV4", t, unmarshal)
 
@@ -136,6 +150,8 @@ func TestMarshalsInetMustFail(t *testing.T) {
 					net.IP{},
 					[]byte{},
 					[16]byte{},
+					netip.Addr{},
+					netip.AddrPort{},
 				}.AddVariants(mod.All...),
 			}.Run("small_dataV6", t, unmarshal)
 		})
@@ -5,6 +5,7 @@ package serialization_test
 
 import (
 	"net"
+	"net/netip"
 	"testing"
 
 	"github.com/gocql/gocql"
@@ -59,6 +60,10 @@ func TestMarshalsInet(t *testing.T) {
 					(*net.IP)(nil),
 					"",
 					(*string)(nil),
+					netip.Addr{},
+					(*netip.Addr)(nil),
+					netip.AddrPort{},
+					(*netip.AddrPort)(nil),
 				}.AddVariants(mod.CustomType),
 			}.Run("[nil]nullable", t, marshal, unmarshal)
 
@@ -78,6 +83,7 @@ func TestMarshalsInet(t *testing.T) {
 					[16]byte{},
 					make(net.IP, 0),
 					"0.0.0.0",
+					netip.Addr{},
 				}.AddVariants(mod.All...),
 			}.Run("[]unmarshal", t, nil, unmarshal)
 
@@ -88,6 +94,8 @@ func TestMarshalsInet(t *testing.T) {
 					[]byte{0, 0, 0, 0},
 					net.IP{0, 0, 0, 0},
 					[4]byte{},
+					netip.IPv4Unspecified(),
+					netip.AddrPortFrom(netip.IPv4Unspecified(), 0),
 				}.AddVariants(mod.All...),
 			}.Run("v4zeros", t, marshal, unmarshal)
 
@@ -105,6 +113,8 @@ func TestMarshalsInet(t *testing.T) {
 					[]byte{192, 168, 0, 1},
 					net.IP{192, 168, 0, 1},
 					[4]byte{192, 168, 0, 1},
+					netip.AddrFrom4([4]byte{192, 168, 0, 1}),
+					netip.AddrPortFrom(netip.AddrFrom4([4]byte{192, 168, 0, 1}), 0),
 				}.AddVariants(mod.All...),
 			}.Run("v4", t, marshal, unmarshal)
 
@@ -115,6 +125,21 @@ func TestMarshalsInet(t *testing.T) {
 				}.AddVariants(mod.All...),
 			}.Run("v4unmarshal", t, nil, unmarshal)
 
+			serialization.PositiveSet{
+				Data: []byte{192, 168, 0, 1},
+				Values: mod.Values{
+					netip.MustParseAddr("::ffff:192.168.0.1"),
+					netip.MustParseAddrPort("192.168.0.1:9042"),
+				}.AddVariants(mod.Reference),
+			}.Run("v4netipMarshal", t, marshal, nil)
+
+			serialization.PositiveSet{
+				Data: []byte("\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\xff\xff\xc0\xa8\x00\x01"),
+				Values: mod.Values{
+					netip.AddrFrom4([4]byte{192, 168, 0, 1}),
+				}.AddVariants(mod.Reference),
+			}.Run("v4mappedUnmarshal", t, nil, unmarshal)
+
 			serialization.PositiveSet{
 				Data: []byte{255, 255, 255, 255},
 				Values: mod.Values{
@@ -122,6 +147,7 @@ func TestMarshalsInet(t *testing.T) {
 					[]byte{255, 255, 255, 255},
 					net.IP{255, 255, 255, 255},
 					[4]byte{255, 255, 255, 255},
+					netip.AddrFrom4([4]byte{255, 255, 255, 255}),
 				}.AddVariants(mod.All...),
 			}.Run("v4max", t, marshal, unmarshal)
 
@@ -139,6 +165,7 @@ func TestMarshalsInet(t *testing.T) {
 					[]byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
 					net.IP{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
 					[16]byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+					netip.IPv6Unspecified(),
 				}.AddVariants(mod.All...),
 			}.Run("v6zeros", t, marshal, unmarshal)
 
@@ -156,6 +183,8 @@ func TestMarshalsInet(t *testing.T) {
 					[]byte("\xfe\x80\xcd\x00\x00\x00\x0c\xde\x12\x57\x00\x00\x21\x1e\x72\x9c"),
 					net.IP("\xfe\x80\xcd\x00\x00\x00\x0c\xde\x12\x57\x00\x00\x21\x1e\x72\x9c"),
 					[16]byte{254, 128, 205, 0, 0, 0, 12, 222, 18, 87, 0, 0, 33, 30, 114, 156},
+					netip.MustParseAddr("fe80:cd00:0:cde:1257:0:211e:729c"),
+					netip.MustParseAddrPort("[fe80:cd00:0:cde:1257:0:211e:729c]:0"),
 				}.AddVariants(mod.All...),
 			}.Run("v6", t, marshal, unmarshal)
 
@@ -166,6 +195,7 @@ func TestMarshalsInet(t *testing.T) {
 					[]byte("\xff\xff\xff\xff\xff\xff\xff\xff\xff\xff\xff\xff\xff\xff\xff\xff"),
 					net.IP("\xff\xff\xff\xff\xff\xff\xff\xff\xff\xff\xff\xff\xff\xff\xff\xff"),
 					[16]byte{255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255},
+					netip.MustParseAddr("ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff"),
 				}.AddVariants(mod.All...),
 			}.Run("v6max", t, marshal, unmarshal)
 		})
@@ -59,7 +59,7 @@ func BenchmarkUnmarshalVectorFloat32(b *testing.B) {
 			b.ResetTimer()
 
 			for i := 0; i < b.N; i++ {
-				if err := unmarshalVector(info, data, &result); err != nil {
+				if err := unmarshalVector(nil, info, data, &result); err != nil {
 					b.Fatal(err)
 				}
 			}
@@ -97,7 +97,7 @@ func BenchmarkMarshalVectorFloat32(b *testing.B) {
 			b.ResetTimer()
 
 			for i := 0; i < b.N; i++ {
-				if _, err := marshalVector(info, vec); err != nil {
+				if _, err := marshalVector(nil, info, vec); err != nil {
 					b.Fatal(err)
 				}
 			}
@@ -135,11 +135,11 @@ func BenchmarkVectorRoundTrip(b *testing.B) {
 			b.ResetTimer()
 
 			for i := 0; i < b.N; i++ {
-				data, err := marshalVector(info, srcVec)
+				data, err := marshalVector(nil, info, srcVec)
 				if err != nil {
 					b.Fatal(err)
 				}
-				if err := unmarshalVector(info, data, &dstVec); err != nil {
+				if err := unmarshalVector(nil, info, data, &dstVec); err != nil {
 					b.Fatal(err)
 				}
 			}